@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"time"
+	"user-service/src/challenge"
 	"user-service/src/interfaces/content"
+	"user-service/src/interfaces/database/entity"
 	g "user-service/src/interfaces/global"
 	"user-service/src/repository"
 	"user-service/src/server"
+	"user-service/src/storage"
 
 	c "user-service/src/interfaces/config"
 	pb "user-service/src/interfaces/grpc"
@@ -61,6 +64,42 @@ func main() {
 	}
 	cl.Add("Database", closeFunc)
 
+	if *global.AutoMigrate {
+		if err := db.AutoMigrate(entity.Models()...); err != nil {
+			lg.Fatalf("fail to migrate database: %v", err)
+			return
+		}
+	}
+
+	// 启动检查依赖的表、唯一索引均不依赖自动迁移, 每次启动时检查并创建
+	if err := repository.EnsureUserTables(db); err != nil {
+		lg.Fatalf("fail to create user tables: %v", err)
+		return
+	}
+	duplicates, err := repository.EnsureUserIndexes(db)
+	if err != nil {
+		lg.Fatalf("fail to create user unique indexes: %v", err)
+		return
+	}
+	for _, duplicate := range duplicates {
+		lg.Warnf("%s %q is used by users %v ignoring case, change all but one of them to create the unique index on next startup",
+			duplicate.Column, duplicate.Value, duplicate.Cids)
+	}
+	// 规范化邮箱同样不依赖自动迁移, 缺少记录的用户不受唯一性约束
+	skipped, err := repository.EnsureUserEmails(db, applicationConfig.EmailConfig.Canonical)
+	if err != nil {
+		lg.Fatalf("fail to save canonical user emails: %v", err)
+		return
+	}
+	for _, duplicate := range skipped {
+		lg.Warnf("canonical email %q of user %04d is already used by user %04d, change the email of one of them to save it on next startup",
+			duplicate.Value, duplicate.Cids[1], duplicate.Cids[0])
+	}
+	if err := repository.EnsureUserSkeletons(db); err != nil {
+		lg.Fatalf("fail to save username skeletons: %v", err)
+		return
+	}
+
 	if applicationConfig.TelemetryConfig.Enable {
 		if err := telemetry.InitSDK(lg, cl, applicationConfig.TelemetryConfig); err != nil {
 			lg.Fatalf("fail to initialize telemetry: %v", err)
@@ -75,21 +114,38 @@ func main() {
 		}
 	}
 
+	avatarStorage, err := storage.NewLocalStorage(applicationConfig.AvatarConfig.StoragePath)
+	if err != nil {
+		lg.Fatalf("fail to initialize avatar storage: %v", err)
+		return
+	}
+
 	contentBuilder := content.NewApplicationContentBuilder().
 		SetConfigManager(configManager).
 		SetCleaner(cl).
 		SetLogger(lg).
 		SetJwtClaimFactory(jwt.NewClaimFactory(applicationConfig.JwtConfig)).
 		SetUserRepo(repository.NewUserRepository(lg, db, applicationConfig.DatabaseConfig.QueryTimeoutDuration)).
-		SetRoleRepo(repository.NewRoleRepository(lg, db, applicationConfig.DatabaseConfig.QueryTimeoutDuration))
+		SetRoleRepo(repository.NewRoleRepository(lg, db, applicationConfig.DatabaseConfig.QueryTimeoutDuration)).
+		SetDivisionRepo(repository.NewDivisionRepository(lg, db, applicationConfig.DatabaseConfig.QueryTimeoutDuration)).
+		SetDeletionRepo(repository.NewDeletionRepository(lg, db, applicationConfig.DatabaseConfig.QueryTimeoutDuration)).
+		SetExportRepo(repository.NewExportRepository(lg, db, applicationConfig.DatabaseConfig.QueryTimeoutDuration)).
+		SetProfileRepo(repository.NewProfileRepository(lg, db, applicationConfig.DatabaseConfig.QueryTimeoutDuration)).
+		SetProfileFieldRepo(repository.NewProfileFieldRepository(lg, db, applicationConfig.DatabaseConfig.QueryTimeoutDuration)).
+		SetAvatarRepo(repository.NewAvatarRepository(lg, db, applicationConfig.DatabaseConfig.QueryTimeoutDuration)).
+		SetAvatarStorage(avatarStorage).
+		SetRatingRepo(repository.NewRatingRepository(lg, db, applicationConfig.DatabaseConfig.QueryTimeoutDuration)).
+		SetInstructorRepo(repository.NewInstructorRepository(lg, db, applicationConfig.DatabaseConfig.QueryTimeoutDuration)).
+		SetCidRepo(repository.NewCidRepository(lg, db, applicationConfig.DatabaseConfig.QueryTimeoutDuration)).
+		SetEmailChangeRepo(repository.NewEmailChangeRepository(lg, db, applicationConfig.DatabaseConfig.QueryTimeoutDuration)).
+		SetSessionRepo(repository.NewSessionRepository(lg, db, applicationConfig.DatabaseConfig.QueryTimeoutDuration)).
+		SetUsernameRepo(repository.NewUsernameRepository(lg, db, applicationConfig.DatabaseConfig.QueryTimeoutDuration)).
+		SetChallenge(challenge.New(applicationConfig.ChallengeConfig))
 
 	requiredServices := []string{*g.EmailServiceName, *g.AuditServiceName}
 
 	consulClient := discovery.NewConsulClient(lg, applicationConfig.GlobalConfig.Discovery, g.AppVersion)
 
-	// since we register our service,
-	// but actually we do not provide any grpc interface,
-	// just for health check
 	if err := consulClient.RegisterServer(); err != nil {
 		lg.Fatalf("fail to register server: %v", err)
 		return
@@ -147,7 +203,10 @@ func main() {
 	listener.Start(context.Background())
 	cl.Add("ServiceListener", listener.Stop)
 
-	go server.StartServer(contentBuilder.Build())
+	applicationContent := contentBuilder.Build()
+
+	go server.StartGrpcServer(applicationContent)
+	go server.StartServer(applicationContent)
 
 	cl.Wait()
 }
@@ -0,0 +1,32 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package challenge 人机验证的实现
+package challenge
+
+import (
+	"user-service/src/interfaces/challenge"
+	c "user-service/src/interfaces/config"
+)
+
+// New 根据配置创建人机验证, 关闭人机验证时返回 nil
+func New(config *c.ChallengeConfig) challenge.Interface {
+	verifyUrl := func(defaultUrl string) string {
+		if config.VerifyUrl != "" {
+			return config.VerifyUrl
+		}
+		return defaultUrl
+	}
+	switch config.Provider {
+	case c.ChallengeProviderHCaptcha:
+		return NewSiteVerifier(verifyUrl(HCaptchaVerifyUrl), config.Secret, config.TimeoutDuration)
+	case c.ChallengeProviderTurnstile:
+		return NewSiteVerifier(verifyUrl(TurnstileVerifyUrl), config.Secret, config.TimeoutDuration)
+	case c.ChallengeProviderReCaptcha:
+		return NewSiteVerifier(verifyUrl(ReCaptchaVerifyUrl), config.Secret, config.TimeoutDuration)
+	case c.ChallengeProviderProofOfWork:
+		return NewProofOfWork(config.Secret, config.Difficulty, config.ChallengeTTLDuration)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package challenge
+package challenge
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// usedSweepSize 已使用挑战的记录数超过该值时清理已过期的记录
+const usedSweepSize = 10000
+
+// ProofOfWork 无状态的工作量证明, 无需依赖第三方服务
+//
+// 挑战格式为 "过期时间.随机数.签名", 客户端需要找到 nonce 使 sha256(挑战 + ":" + nonce) 的前导零比特数不少于难度,
+// 并以 "挑战:nonce" 作为令牌提交. 挑战由服务端签名, 无需保存, 已使用的挑战在过期前记录在内存中防止重放
+type ProofOfWork struct {
+	secret     []byte
+	difficulty int
+	ttl        time.Duration
+	lock       sync.Mutex
+	used       map[string]time.Time
+}
+
+// NewProofOfWork 创建工作量证明, 多实例部署时各实例需要使用相同的密钥
+func NewProofOfWork(secret string, difficulty int, ttl time.Duration) *ProofOfWork {
+	return &ProofOfWork{
+		secret:     []byte(secret),
+		difficulty: difficulty,
+		ttl:        ttl,
+		used:       make(map[string]time.Time),
+	}
+}
+
+func (pow *ProofOfWork) sign(payload string) string {
+	mac := hmac.New(sha256.New, pow.secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func (pow *ProofOfWork) Difficulty() int {
+	return pow.difficulty
+}
+
+// Issue 签发挑战, 返回挑战与过期时间
+func (pow *ProofOfWork) Issue() (string, time.Time) {
+	random := make([]byte, 16)
+	_, _ = rand.Read(random)
+	expiresAt := time.Now().Add(pow.ttl)
+	payload := strconv.FormatInt(expiresAt.Unix(), 10) + "." + hex.EncodeToString(random)
+	return payload + "." + pow.sign(payload), expiresAt
+}
+
+// leadingZeroBits 计算哈希值的前导零比特数
+func leadingZeroBits(sum []byte) int {
+	count := 0
+	for _, b := range sum {
+		if b != 0 {
+			return count + bits.LeadingZeros8(b)
+		}
+		count += 8
+	}
+	return count
+}
+
+// Verify 校验 "挑战:nonce" 形式的令牌, 每个挑战只能使用一次
+func (pow *ProofOfWork) Verify(_ context.Context, token string, _ string) (bool, error) {
+	challenge, nonce, ok := strings.Cut(token, ":")
+	if !ok || nonce == "" {
+		return false, nil
+	}
+	parts := strings.Split(challenge, ".")
+	if len(parts) != 3 {
+		return false, nil
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(pow.sign(parts[0]+"."+parts[1]))) {
+		return false, nil
+	}
+	expires, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return false, nil
+	}
+	expiresAt := time.Unix(expires, 0)
+	now := time.Now()
+	if !expiresAt.After(now) {
+		return false, nil
+	}
+	sum := sha256.Sum256([]byte(token))
+	if leadingZeroBits(sum[:]) < pow.difficulty {
+		return false, nil
+	}
+
+	pow.lock.Lock()
+	defer pow.lock.Unlock()
+	if len(pow.used) >= usedSweepSize {
+		for key, expiry := range pow.used {
+			if !expiry.After(now) {
+				delete(pow.used, key)
+			}
+		}
+	}
+	if _, ok := pow.used[challenge]; ok {
+		return false, nil
+	}
+	pow.used[challenge] = expiresAt
+	return true, nil
+}
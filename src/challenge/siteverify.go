@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package challenge
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 各验证服务的默认校验地址
+const (
+	HCaptchaVerifyUrl  = "https://api.hcaptcha.com/siteverify"
+	TurnstileVerifyUrl = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+	ReCaptchaVerifyUrl = "https://www.google.com/recaptcha/api/siteverify"
+)
+
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// SiteVerifier 通过 siteverify 接口校验令牌, hCaptcha、Turnstile 与 reCAPTCHA 使用相同的协议
+type SiteVerifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewSiteVerifier(verifyUrl string, secret string, timeout time.Duration) *SiteVerifier {
+	return &SiteVerifier{
+		url:    verifyUrl,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (v *SiteVerifier) Verify(ctx context.Context, token string, ip string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+	form := url.Values{}
+	form.Set("secret", v.secret)
+	form.Set("response", token)
+	if ip != "" {
+		form.Set("remoteip", ip)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := v.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("siteverify returned status %d", res.StatusCode)
+	}
+	result := &siteVerifyResponse{}
+	if err := json.NewDecoder(res.Body).Decode(result); err != nil {
+		return false, err
+	}
+	return result.Success, nil
+}
@@ -0,0 +1,22 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package challenge 人机验证接口
+package challenge
+
+import (
+	"context"
+	"time"
+)
+
+// Interface 校验客户端提交的人机验证令牌
+type Interface interface {
+	Verify(ctx context.Context, token string, ip string) (bool, error)
+}
+
+// Issuer 需要由服务端签发挑战的验证方式, 例如工作量证明
+type Issuer interface {
+	Interface
+	Issue() (challenge string, expiresAt time.Time)
+	Difficulty() int
+}
@@ -0,0 +1,47 @@
+// Package config
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// AuthorizationConfig 鉴权决策接口配置
+type AuthorizationConfig struct {
+	CacheTTL         string        `yaml:"cache_ttl"`
+	CacheTTLDuration time.Duration `yaml:"-"`
+	CacheSize        int           `yaml:"cache_size"`
+	MaxBatchSize     int           `yaml:"max_batch_size"`
+	// ServiceTokens 允许调用 gRPC 鉴权接口的服务令牌, 为空时不提供 gRPC 鉴权接口
+	ServiceTokens []string `yaml:"service_tokens"`
+}
+
+func (a *AuthorizationConfig) InitDefaults() {
+	a.CacheTTL = "5s"
+	a.CacheSize = 10000
+	a.MaxBatchSize = 100
+	a.ServiceTokens = []string{}
+}
+
+func (a *AuthorizationConfig) Verify() (bool, error) {
+	duration, err := time.ParseDuration(a.CacheTTL)
+	if err != nil {
+		return false, fmt.Errorf("invalid authorization cache ttl %q: %v", a.CacheTTL, err)
+	}
+	if duration < 0 {
+		return false, fmt.Errorf("authorization cache ttl must not be negative")
+	}
+	a.CacheTTLDuration = duration
+	if a.CacheSize < 0 {
+		return false, fmt.Errorf("authorization cache size must not be negative")
+	}
+	if a.MaxBatchSize <= 0 {
+		return false, fmt.Errorf("authorization max batch size must be positive")
+	}
+	for _, token := range a.ServiceTokens {
+		if len(token) < 32 {
+			return false, fmt.Errorf("authorization service token must be at least 32 characters")
+		}
+	}
+	return true, nil
+}
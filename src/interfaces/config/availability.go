@@ -0,0 +1,36 @@
+// Package config
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// AvailabilityConfig 注册信息可用性检查接口配置
+//
+// 人机验证由 ChallengeConfig 中的 availability 接口配置
+type AvailabilityConfig struct {
+	RateLimit          int           `yaml:"rate_limit"`
+	RateWindow         string        `yaml:"rate_window"`
+	RateWindowDuration time.Duration `yaml:"-"`
+}
+
+func (a *AvailabilityConfig) InitDefaults() {
+	a.RateLimit = 20
+	a.RateWindow = "1m"
+}
+
+func (a *AvailabilityConfig) Verify() (bool, error) {
+	if a.RateLimit < 0 {
+		return false, fmt.Errorf("availability rate limit must not be negative")
+	}
+	duration, err := time.ParseDuration(a.RateWindow)
+	if err != nil {
+		return false, fmt.Errorf("invalid availability rate window %q: %v", a.RateWindow, err)
+	}
+	if duration <= 0 {
+		return false, fmt.Errorf("availability rate window must be positive")
+	}
+	a.RateWindowDuration = duration
+	return true, nil
+}
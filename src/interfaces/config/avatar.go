@@ -0,0 +1,94 @@
+// Package config
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/labstack/gommon/bytes"
+)
+
+// 头像来源, 按配置顺序依次尝试
+const (
+	AvatarProviderUploaded  = "uploaded"
+	AvatarProviderQQ        = "qq"
+	AvatarProviderGravatar  = "gravatar"
+	AvatarProviderIdenticon = "identicon"
+)
+
+var avatarProviders = []string{AvatarProviderUploaded, AvatarProviderQQ, AvatarProviderGravatar, AvatarProviderIdenticon}
+
+// avatarDefaultMaxSize 未限制请求体大小时上传头像文件的大小限制
+const avatarDefaultMaxSize = "2M"
+
+// AvatarConfig 头像上传与存储配置
+//
+// 上传头像文件的大小限制由 server.http.body_limit 决定, 未限制请求体大小时为 2M
+type AvatarConfig struct {
+	BodyLimit    string   `yaml:"-"`
+	MaxSizeBytes int64    `yaml:"-"`
+	MaxDimension int      `yaml:"max_dimension"`
+	UploadLimit  int      `yaml:"upload_limit"`
+	MaxHistory   int      `yaml:"max_history"`
+	Sizes        []int    `yaml:"sizes"`
+	StoragePath  string   `yaml:"storage_path"`
+	UrlPrefix    string   `yaml:"url_prefix"`
+	Providers    []string `yaml:"providers"`
+}
+
+func (a *AvatarConfig) InitDefaults() {
+	a.MaxDimension = 4096
+	a.UploadLimit = 10
+	a.MaxHistory = 20
+	a.Sizes = []int{256, 128, 64}
+	a.StoragePath = "./data/avatars"
+	a.UrlPrefix = "/api/v1/avatars"
+	a.Providers = []string{AvatarProviderUploaded, AvatarProviderQQ, AvatarProviderGravatar, AvatarProviderIdenticon}
+}
+
+func (a *AvatarConfig) Verify() (bool, error) {
+	maxSize := a.BodyLimit
+	if maxSize == "" {
+		maxSize = avatarDefaultMaxSize
+	}
+	// 与 echo 解析 body_limit 的方式一致
+	size, err := bytes.Parse(maxSize)
+	if err != nil {
+		return false, fmt.Errorf("invalid body limit %q: %v", maxSize, err)
+	}
+	if size <= 0 {
+		return false, fmt.Errorf("avatar max size must be positive")
+	}
+	a.MaxSizeBytes = size
+	if a.UploadLimit < 0 {
+		return false, fmt.Errorf("avatar upload limit must not be negative")
+	}
+	if a.MaxHistory < 0 {
+		return false, fmt.Errorf("avatar max history must not be negative")
+	}
+	if a.MaxDimension <= 0 {
+		return false, fmt.Errorf("avatar max dimension must be positive")
+	}
+	if len(a.Sizes) == 0 {
+		return false, fmt.Errorf("avatar sizes must not be empty")
+	}
+	for _, s := range a.Sizes {
+		if s <= 0 || s > a.MaxDimension {
+			return false, fmt.Errorf("invalid avatar size %d", s)
+		}
+	}
+	if a.StoragePath == "" {
+		return false, fmt.Errorf("avatar storage path must not be empty")
+	}
+	a.UrlPrefix = strings.TrimSuffix(a.UrlPrefix, "/")
+	for index, provider := range a.Providers {
+		if !slices.Contains(avatarProviders, provider) {
+			return false, fmt.Errorf("invalid avatar provider %q", provider)
+		}
+		if slices.Contains(a.Providers[:index], provider) {
+			return false, fmt.Errorf("duplicate avatar provider %q", provider)
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,112 @@
+// Package config
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// 人机验证方式
+const (
+	ChallengeProviderNone        = "none"
+	ChallengeProviderHCaptcha    = "hcaptcha"
+	ChallengeProviderTurnstile   = "turnstile"
+	ChallengeProviderReCaptcha   = "recaptcha"
+	ChallengeProviderProofOfWork = "pow"
+)
+
+// 需要人机验证的接口
+const (
+	ChallengeEndpointRegister      = "register"
+	ChallengeEndpointLogin         = "login"
+	ChallengeEndpointResetPassword = "reset_password"
+	ChallengeEndpointAvailability  = "availability"
+)
+
+// ChallengeEndpoint 单个接口的人机验证配置
+type ChallengeEndpoint struct {
+	Enable                bool          `yaml:"enable"`
+	FailureThreshold      int           `yaml:"failure_threshold"`
+	FailureWindow         string        `yaml:"failure_window"`
+	FailureWindowDuration time.Duration `yaml:"-"`
+}
+
+// ChallengeConfig 人机验证配置
+type ChallengeConfig struct {
+	Provider             string                        `yaml:"provider"`
+	SiteKey              string                        `yaml:"site_key"`
+	Secret               string                        `yaml:"secret"`
+	VerifyUrl            string                        `yaml:"verify_url"`
+	Timeout              string                        `yaml:"timeout"`
+	TimeoutDuration      time.Duration                 `yaml:"-"`
+	Difficulty           int                           `yaml:"difficulty"`
+	ChallengeTTL         string                        `yaml:"challenge_ttl"`
+	ChallengeTTLDuration time.Duration                 `yaml:"-"`
+	Endpoints            map[string]*ChallengeEndpoint `yaml:"endpoints"`
+}
+
+func (c *ChallengeConfig) InitDefaults() {
+	c.Provider = ChallengeProviderNone
+	c.Timeout = "5s"
+	c.Difficulty = 20
+	c.ChallengeTTL = "5m"
+	c.Endpoints = map[string]*ChallengeEndpoint{
+		ChallengeEndpointRegister:      {Enable: true, FailureThreshold: 3, FailureWindow: "1h"},
+		ChallengeEndpointLogin:         {Enable: true, FailureThreshold: 5, FailureWindow: "15m"},
+		ChallengeEndpointResetPassword: {Enable: true, FailureThreshold: 3, FailureWindow: "1h"},
+		ChallengeEndpointAvailability:  {Enable: false, FailureThreshold: 10, FailureWindow: "1h"},
+	}
+}
+
+func (c *ChallengeConfig) Verify() (bool, error) {
+	switch c.Provider {
+	case ChallengeProviderNone:
+	case ChallengeProviderProofOfWork, ChallengeProviderHCaptcha, ChallengeProviderTurnstile, ChallengeProviderReCaptcha:
+		if c.Secret == "" {
+			return false, fmt.Errorf("challenge secret is required for provider %s", c.Provider)
+		}
+	default:
+		return false, fmt.Errorf("unknown challenge provider %q", c.Provider)
+	}
+	duration, err := time.ParseDuration(c.Timeout)
+	if err != nil {
+		return false, fmt.Errorf("invalid challenge timeout %q: %v", c.Timeout, err)
+	}
+	if duration <= 0 {
+		return false, fmt.Errorf("challenge timeout must be positive")
+	}
+	c.TimeoutDuration = duration
+	if c.Difficulty < 0 || c.Difficulty > 32 {
+		return false, fmt.Errorf("challenge difficulty must be between 0 and 32")
+	}
+	duration, err = time.ParseDuration(c.ChallengeTTL)
+	if err != nil {
+		return false, fmt.Errorf("invalid challenge ttl %q: %v", c.ChallengeTTL, err)
+	}
+	if duration <= 0 {
+		return false, fmt.Errorf("challenge ttl must be positive")
+	}
+	c.ChallengeTTLDuration = duration
+	for name, endpoint := range c.Endpoints {
+		switch name {
+		case ChallengeEndpointRegister, ChallengeEndpointLogin, ChallengeEndpointResetPassword, ChallengeEndpointAvailability:
+		default:
+			return false, fmt.Errorf("unknown challenge endpoint %q", name)
+		}
+		if endpoint.FailureThreshold < 0 {
+			return false, fmt.Errorf("challenge failure threshold of %s must not be negative", name)
+		}
+		if endpoint.FailureThreshold == 0 {
+			continue
+		}
+		duration, err := time.ParseDuration(endpoint.FailureWindow)
+		if err != nil {
+			return false, fmt.Errorf("invalid challenge failure window %q of %s: %v", endpoint.FailureWindow, name, err)
+		}
+		if duration <= 0 {
+			return false, fmt.Errorf("challenge failure window of %s must be positive", name)
+		}
+		endpoint.FailureWindowDuration = duration
+	}
+	return true, nil
+}
@@ -0,0 +1,80 @@
+// Package config
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// CidRange 闭区间表示的呼号段
+type CidRange struct {
+	Start   uint   `yaml:"start"`
+	End     uint   `yaml:"end"`
+	Comment string `yaml:"comment"`
+}
+
+func (r *CidRange) Contains(cid uint) bool {
+	return r.Start <= cid && cid <= r.End
+}
+
+// CidConfig 呼号分配配置
+type CidConfig struct {
+	Min             uint             `yaml:"min"`
+	Max             uint             `yaml:"max"`
+	Reserved        []*CidRange      `yaml:"reserved"`
+	Blocked         []uint           `yaml:"blocked"`
+	BlockedPatterns []string         `yaml:"blocked_patterns"`
+	blockedRegexps  []*regexp.Regexp `yaml:"-"`
+}
+
+func (c *CidConfig) InitDefaults() {
+	c.Min = 1000
+	c.Max = 9999
+	c.Reserved = []*CidRange{}
+	c.Blocked = []uint{}
+	c.BlockedPatterns = []string{}
+}
+
+// IsReserved 判断呼号是否位于保留号段内
+func (c *CidConfig) IsReserved(cid uint) bool {
+	return slices.ContainsFunc(c.Reserved, func(r *CidRange) bool { return r.Contains(cid) })
+}
+
+// IsBlocked 判断呼号是否被禁止使用
+func (c *CidConfig) IsBlocked(cid uint) bool {
+	if slices.Contains(c.Blocked, cid) {
+		return true
+	}
+	value := strconv.FormatUint(uint64(cid), 10)
+	return slices.ContainsFunc(c.blockedRegexps, func(re *regexp.Regexp) bool { return re.MatchString(value) })
+}
+
+// Allocatable 判断呼号是否可以在注册时自动分配
+func (c *CidConfig) Allocatable(cid uint) bool {
+	return c.Min <= cid && cid <= c.Max && !c.IsReserved(cid) && !c.IsBlocked(cid)
+}
+
+func (c *CidConfig) Verify() (bool, error) {
+	if c.Min == 0 {
+		return false, fmt.Errorf("cid min must be positive")
+	}
+	if c.Max < c.Min {
+		return false, fmt.Errorf("cid max must not be less than min")
+	}
+	for _, r := range c.Reserved {
+		if r.Start == 0 || r.End < r.Start {
+			return false, fmt.Errorf("invalid reserved cid range %d-%d", r.Start, r.End)
+		}
+	}
+	c.blockedRegexps = make([]*regexp.Regexp, len(c.BlockedPatterns))
+	for index, pattern := range c.BlockedPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, fmt.Errorf("invalid blocked cid pattern %q: %v", pattern, err)
+		}
+		c.blockedRegexps[index] = re
+	}
+	return true, nil
+}
@@ -4,12 +4,24 @@ package config
 import "half-nothing.cn/service-core/interfaces/config"
 
 type Config struct {
-	GlobalConfig    *GlobalConfig            `yaml:"global"`
-	ServerConfig    *config.ServerConfig     `yaml:"server"`
-	ClientConfig    *config.GrpcClientConfig `yaml:"client"`
-	JwtConfig       *config.JwtConfig        `yaml:"jwt"`
-	DatabaseConfig  *config.DatabaseConfig   `yaml:"database"`
-	TelemetryConfig *config.TelemetryConfig  `yaml:"telemetry"`
+	GlobalConfig        *GlobalConfig            `yaml:"global"`
+	ServerConfig        *config.ServerConfig     `yaml:"server"`
+	ClientConfig        *config.GrpcClientConfig `yaml:"client"`
+	JwtConfig           *config.JwtConfig        `yaml:"jwt"`
+	DatabaseConfig      *config.DatabaseConfig   `yaml:"database"`
+	TelemetryConfig     *config.TelemetryConfig  `yaml:"telemetry"`
+	AuthorizationConfig *AuthorizationConfig     `yaml:"authorization"`
+	DeletionConfig      *DeletionConfig          `yaml:"deletion"`
+	ExportConfig        *ExportConfig            `yaml:"export"`
+	AvatarConfig        *AvatarConfig            `yaml:"avatar"`
+	RatingConfig        *RatingConfig            `yaml:"rating"`
+	InstructorConfig    *InstructorConfig        `yaml:"instructor"`
+	CidConfig           *CidConfig               `yaml:"cid"`
+	AvailabilityConfig  *AvailabilityConfig      `yaml:"availability"`
+	ChallengeConfig     *ChallengeConfig         `yaml:"challenge"`
+	EmailChangeConfig   *EmailChangeConfig       `yaml:"email_change"`
+	UsernameConfig      *UsernameConfig          `yaml:"username"`
+	EmailConfig         *EmailConfig             `yaml:"email"`
 }
 
 func (c *Config) InitDefaults() {
@@ -25,6 +37,30 @@ func (c *Config) InitDefaults() {
 	c.DatabaseConfig.InitDefaults()
 	c.TelemetryConfig = &config.TelemetryConfig{}
 	c.TelemetryConfig.InitDefaults()
+	c.AuthorizationConfig = &AuthorizationConfig{}
+	c.AuthorizationConfig.InitDefaults()
+	c.DeletionConfig = &DeletionConfig{}
+	c.DeletionConfig.InitDefaults()
+	c.ExportConfig = &ExportConfig{}
+	c.ExportConfig.InitDefaults()
+	c.AvatarConfig = &AvatarConfig{}
+	c.AvatarConfig.InitDefaults()
+	c.RatingConfig = &RatingConfig{}
+	c.RatingConfig.InitDefaults()
+	c.InstructorConfig = &InstructorConfig{}
+	c.InstructorConfig.InitDefaults()
+	c.CidConfig = &CidConfig{}
+	c.CidConfig.InitDefaults()
+	c.AvailabilityConfig = &AvailabilityConfig{}
+	c.AvailabilityConfig.InitDefaults()
+	c.ChallengeConfig = &ChallengeConfig{}
+	c.ChallengeConfig.InitDefaults()
+	c.EmailChangeConfig = &EmailChangeConfig{}
+	c.EmailChangeConfig.InitDefaults()
+	c.UsernameConfig = &UsernameConfig{}
+	c.UsernameConfig.InitDefaults()
+	c.EmailConfig = &EmailConfig{}
+	c.EmailConfig.InitDefaults()
 }
 
 func (c *Config) Verify() (bool, error) {
@@ -46,5 +82,43 @@ func (c *Config) Verify() (bool, error) {
 	if ok, err := c.TelemetryConfig.Verify(); !ok {
 		return ok, err
 	}
+	if ok, err := c.AuthorizationConfig.Verify(); !ok {
+		return ok, err
+	}
+	if ok, err := c.DeletionConfig.Verify(); !ok {
+		return ok, err
+	}
+	if ok, err := c.ExportConfig.Verify(); !ok {
+		return ok, err
+	}
+	// 上传头像文件的大小限制与请求体大小限制保持一致
+	c.AvatarConfig.BodyLimit = c.ServerConfig.HttpServerConfig.BodyLimit
+	if ok, err := c.AvatarConfig.Verify(); !ok {
+		return ok, err
+	}
+	if ok, err := c.RatingConfig.Verify(); !ok {
+		return ok, err
+	}
+	if ok, err := c.InstructorConfig.Verify(); !ok {
+		return ok, err
+	}
+	if ok, err := c.CidConfig.Verify(); !ok {
+		return ok, err
+	}
+	if ok, err := c.AvailabilityConfig.Verify(); !ok {
+		return ok, err
+	}
+	if ok, err := c.ChallengeConfig.Verify(); !ok {
+		return ok, err
+	}
+	if ok, err := c.EmailChangeConfig.Verify(); !ok {
+		return ok, err
+	}
+	if ok, err := c.UsernameConfig.Verify(); !ok {
+		return ok, err
+	}
+	if ok, err := c.EmailConfig.Verify(); !ok {
+		return ok, err
+	}
 	return c.JwtConfig.Verify()
 }
@@ -0,0 +1,45 @@
+// Package config
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DeletionConfig 账户注销配置
+type DeletionConfig struct {
+	GracePeriod           string        `yaml:"grace_period"`
+	GracePeriodDuration   time.Duration `yaml:"-"`
+	CheckInterval         string        `yaml:"check_interval"`
+	CheckIntervalDuration time.Duration `yaml:"-"`
+	BatchSize             int           `yaml:"batch_size"`
+}
+
+func (d *DeletionConfig) InitDefaults() {
+	d.GracePeriod = "720h"
+	d.CheckInterval = "1h"
+	d.BatchSize = 100
+}
+
+func (d *DeletionConfig) Verify() (bool, error) {
+	duration, err := time.ParseDuration(d.GracePeriod)
+	if err != nil {
+		return false, fmt.Errorf("invalid deletion grace period %q: %v", d.GracePeriod, err)
+	}
+	if duration < 0 {
+		return false, fmt.Errorf("deletion grace period must not be negative")
+	}
+	d.GracePeriodDuration = duration
+	duration, err = time.ParseDuration(d.CheckInterval)
+	if err != nil {
+		return false, fmt.Errorf("invalid deletion check interval %q: %v", d.CheckInterval, err)
+	}
+	if duration <= 0 {
+		return false, fmt.Errorf("deletion check interval must be positive")
+	}
+	d.CheckIntervalDuration = duration
+	if d.BatchSize <= 0 {
+		return false, fmt.Errorf("deletion batch size must be positive")
+	}
+	return true, nil
+}
@@ -0,0 +1,155 @@
+// Package config
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"strings"
+)
+
+var (
+	ErrEmailInvalid    = errors.New("invalid email address")
+	ErrEmailDisposable = errors.New("disposable email domain")
+)
+
+// EmailProvider 邮箱服务商的规范化规则
+type EmailProvider struct {
+	Domains         []string `yaml:"domains"`
+	CanonicalDomain string   `yaml:"canonical_domain"`
+	StripPlus       bool     `yaml:"strip_plus"`
+	StripDots       bool     `yaml:"strip_dots"`
+}
+
+// EmailConfig 邮箱地址校验与规范化配置
+//
+// 规范化后的邮箱只用于判断是否为同一个邮箱, 不会修改用户保存的邮箱
+type EmailConfig struct {
+	Providers             []*EmailProvider          `yaml:"providers"`
+	DisposableDomains     []string                  `yaml:"disposable_domains"`
+	DisposableDomainsFile string                    `yaml:"disposable_domains_file"`
+	providers             map[string]*EmailProvider `yaml:"-"`
+	disposable            map[string]bool           `yaml:"-"`
+}
+
+func (e *EmailConfig) InitDefaults() {
+	e.Providers = []*EmailProvider{
+		{Domains: []string{"gmail.com", "googlemail.com"}, CanonicalDomain: "gmail.com", StripPlus: true, StripDots: true},
+		{Domains: []string{"outlook.com", "hotmail.com", "live.com"}, StripPlus: true},
+		{Domains: []string{"icloud.com", "me.com", "mac.com"}, CanonicalDomain: "icloud.com", StripPlus: true},
+	}
+	e.DisposableDomains = []string{}
+	e.DisposableDomainsFile = ""
+}
+
+func (e *EmailConfig) Verify() (bool, error) {
+	e.providers = make(map[string]*EmailProvider)
+	for _, provider := range e.Providers {
+		if len(provider.Domains) == 0 {
+			return false, fmt.Errorf("email provider must have at least one domain")
+		}
+		provider.CanonicalDomain = strings.ToLower(provider.CanonicalDomain)
+		for _, domain := range provider.Domains {
+			domain = strings.ToLower(strings.TrimSpace(domain))
+			if domain == "" {
+				return false, fmt.Errorf("email provider domain must not be empty")
+			}
+			if _, ok := e.providers[domain]; ok {
+				return false, fmt.Errorf("duplicate email provider domain %s", domain)
+			}
+			e.providers[domain] = provider
+		}
+	}
+	e.disposable = make(map[string]bool, len(e.DisposableDomains))
+	for _, domain := range e.DisposableDomains {
+		e.disposable[strings.ToLower(strings.TrimSpace(domain))] = true
+	}
+	if e.DisposableDomainsFile != "" {
+		if err := e.loadDisposableDomains(); err != nil {
+			return false, fmt.Errorf("fail to load disposable domains file %s: %v", e.DisposableDomainsFile, err)
+		}
+	}
+	delete(e.disposable, "")
+	return true, nil
+}
+
+// loadDisposableDomains 从文件加载一次性邮箱域名, 每行一个域名, 以 # 开头的行为注释
+func (e *EmailConfig) loadDisposableDomains() error {
+	file, err := os.Open(e.DisposableDomainsFile)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		e.disposable[strings.ToLower(line)] = true
+	}
+	return scanner.Err()
+}
+
+// Parse 按 RFC 5322 解析邮箱地址, 不接受带显示名称的形式, 返回去除首尾空白并将域名转为小写后的地址
+func (e *EmailConfig) Parse(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Name != "" || address.Address != email {
+		return "", ErrEmailInvalid
+	}
+	at := strings.LastIndex(email, "@")
+	local, domain := email[:at], strings.ToLower(email[at+1:])
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, "[") {
+		return "", ErrEmailInvalid
+	}
+	if e.IsDisposable(domain) {
+		return "", ErrEmailDisposable
+	}
+	return local + "@" + domain, nil
+}
+
+// IsDisposable 判断域名及其上级域名是否属于一次性邮箱
+func (e *EmailConfig) IsDisposable(domain string) bool {
+	domain = strings.ToLower(domain)
+	for {
+		if e.disposable[domain] {
+			return true
+		}
+		index := strings.Index(domain, ".")
+		if index < 0 {
+			return false
+		}
+		domain = domain[index+1:]
+	}
+}
+
+// Canonical 计算用于判断唯一性的规范化邮箱, 地址需要先经过 Parse 校验
+//
+// 统一转为小写, 并按服务商规则合并域名别名、去除 + 之后的标签和本地部分中的点
+func (e *EmailConfig) Canonical(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	local, domain := email[:at], email[at+1:]
+	provider, ok := e.providers[domain]
+	if !ok {
+		return email
+	}
+	if provider.CanonicalDomain != "" {
+		domain = provider.CanonicalDomain
+	}
+	if provider.StripPlus {
+		if index := strings.Index(local, "+"); index > 0 {
+			local = local[:index]
+		}
+	}
+	if provider.StripDots {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	return local + "@" + domain
+}
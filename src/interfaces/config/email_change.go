@@ -0,0 +1,44 @@
+// Package config
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EmailChangeTokenPlaceholder 确认与撤销链接中令牌的占位符
+const EmailChangeTokenPlaceholder = "{token}"
+
+// EmailChangeConfig 邮箱修改配置
+type EmailChangeConfig struct {
+	ValidDays     int           `yaml:"valid_days"`
+	ValidDuration time.Duration `yaml:"-"`
+	ConfirmUrl    string        `yaml:"confirm_url"`
+	RevertUrl     string        `yaml:"revert_url"`
+}
+
+func (e *EmailChangeConfig) InitDefaults() {
+	e.ValidDays = 7
+	e.ConfirmUrl = "http://localhost/email/confirm?token={token}"
+	e.RevertUrl = "http://localhost/email/revert?token={token}"
+}
+
+func (e *EmailChangeConfig) Verify() (bool, error) {
+	if e.ValidDays <= 0 {
+		return false, fmt.Errorf("email change valid days must be positive")
+	}
+	e.ValidDuration = time.Duration(e.ValidDays) * 24 * time.Hour
+	if !strings.Contains(e.ConfirmUrl, EmailChangeTokenPlaceholder) {
+		return false, fmt.Errorf("email change confirm url must contain %s", EmailChangeTokenPlaceholder)
+	}
+	if !strings.Contains(e.RevertUrl, EmailChangeTokenPlaceholder) {
+		return false, fmt.Errorf("email change revert url must contain %s", EmailChangeTokenPlaceholder)
+	}
+	return true, nil
+}
+
+// Link 将令牌填入链接模板
+func (e *EmailChangeConfig) Link(template string, token string) string {
+	return strings.ReplaceAll(template, EmailChangeTokenPlaceholder, token)
+}
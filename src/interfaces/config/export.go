@@ -0,0 +1,45 @@
+// Package config
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExportConfig 个人数据导出配置
+type ExportConfig struct {
+	Interval          string        `yaml:"interval"`
+	IntervalDuration  time.Duration `yaml:"-"`
+	Retention         string        `yaml:"retention"`
+	RetentionDuration time.Duration `yaml:"-"`
+	RateLimit         int           `yaml:"rate_limit"`
+}
+
+func (e *ExportConfig) InitDefaults() {
+	e.Interval = "24h"
+	e.Retention = "168h"
+	e.RateLimit = 10
+}
+
+func (e *ExportConfig) Verify() (bool, error) {
+	duration, err := time.ParseDuration(e.Interval)
+	if err != nil {
+		return false, fmt.Errorf("invalid export interval %q: %v", e.Interval, err)
+	}
+	if duration < 0 {
+		return false, fmt.Errorf("export interval must not be negative")
+	}
+	e.IntervalDuration = duration
+	duration, err = time.ParseDuration(e.Retention)
+	if err != nil {
+		return false, fmt.Errorf("invalid export retention %q: %v", e.Retention, err)
+	}
+	if duration <= 0 {
+		return false, fmt.Errorf("export retention must be positive")
+	}
+	e.RetentionDuration = duration
+	if e.RateLimit < 0 {
+		return false, fmt.Errorf("export rate limit must not be negative")
+	}
+	return true, nil
+}
@@ -0,0 +1,29 @@
+// Package config
+package config
+
+import "fmt"
+
+// InstructorConfig 教员指派配置
+type InstructorConfig struct {
+	MinRating   int      `yaml:"min_rating"`
+	Roles       []string `yaml:"roles"`
+	MaxTrainees int      `yaml:"max_trainees"`
+}
+
+func (i *InstructorConfig) InitDefaults() {
+	i.MinRating = 7
+	i.Roles = []string{}
+	i.MaxTrainees = 10
+}
+
+func (i *InstructorConfig) Verify() (bool, error) {
+	if i.MaxTrainees < 0 {
+		return false, fmt.Errorf("instructor max trainees must not be negative")
+	}
+	for _, role := range i.Roles {
+		if role == "" {
+			return false, fmt.Errorf("instructor role name must not be empty")
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,85 @@
+// Package config
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// RatingLevel 管制员等级定义
+type RatingLevel struct {
+	Value           int           `yaml:"value"`
+	Name            string        `yaml:"name"`
+	MinTime         string        `yaml:"min_time"`
+	MinTimeDuration time.Duration `yaml:"-"`
+}
+
+// RatingConfig 管制员等级配置, 等级按 Levels 中的顺序由低到高排列
+type RatingConfig struct {
+	Levels           []*RatingLevel `yaml:"levels"`
+	MaxPromotionStep int            `yaml:"max_promotion_step"`
+	AllowDemotion    bool           `yaml:"allow_demotion"`
+}
+
+func (r *RatingConfig) InitDefaults() {
+	r.Levels = []*RatingLevel{
+		{Value: 0, Name: "OBS"},
+		{Value: 1, Name: "S1", MinTime: "720h"},
+		{Value: 2, Name: "S2", MinTime: "1440h"},
+		{Value: 3, Name: "S3", MinTime: "2160h"},
+		{Value: 4, Name: "C1", MinTime: "2160h"},
+		{Value: 5, Name: "C2"},
+		{Value: 6, Name: "C3"},
+		{Value: 7, Name: "I1"},
+		{Value: 8, Name: "I2"},
+		{Value: 9, Name: "I3"},
+		{Value: 10, Name: "SUP"},
+		{Value: 11, Name: "ADM"},
+	}
+	r.MaxPromotionStep = 1
+	r.AllowDemotion = true
+}
+
+// Index 获取等级在等级列表中的位置, 等级不存在时返回 -1
+func (r *RatingConfig) Index(value int) int {
+	for index, level := range r.Levels {
+		if level.Value == value {
+			return index
+		}
+	}
+	return -1
+}
+
+func (r *RatingConfig) Verify() (bool, error) {
+	if len(r.Levels) == 0 {
+		return false, fmt.Errorf("rating levels must not be empty")
+	}
+	names := make(map[string]bool, len(r.Levels))
+	for index, level := range r.Levels {
+		if level.Name == "" {
+			return false, fmt.Errorf("rating level %d has no name", level.Value)
+		}
+		if names[level.Name] {
+			return false, fmt.Errorf("duplicate rating level name %q", level.Name)
+		}
+		names[level.Name] = true
+		if index > 0 && level.Value <= r.Levels[index-1].Value {
+			return false, fmt.Errorf("rating level values must be strictly increasing")
+		}
+		if level.MinTime == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(level.MinTime)
+		if err != nil {
+			return false, fmt.Errorf("invalid min time %q of rating %s: %v", level.MinTime, level.Name, err)
+		}
+		if duration < 0 {
+			return false, fmt.Errorf("min time of rating %s must not be negative", level.Name)
+		}
+		level.MinTimeDuration = duration
+	}
+	if r.MaxPromotionStep < 0 {
+		return false, fmt.Errorf("rating max promotion step must not be negative")
+	}
+	return true, nil
+}
@@ -0,0 +1,50 @@
+// Package config
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// UsernameConfig 用户名修改策略配置
+type UsernameConfig struct {
+	ChangeCooldown         string        `yaml:"change_cooldown"`
+	ChangeCooldownDuration time.Duration `yaml:"-"`
+	ReservationDays        int           `yaml:"reservation_days"`
+	ReservationDuration    time.Duration `yaml:"-"`
+	Blocklist              []string      `yaml:"blocklist"`
+	ProtectedNames         []string      `yaml:"protected_names"`
+}
+
+func (u *UsernameConfig) InitDefaults() {
+	u.ChangeCooldown = "720h"
+	u.ReservationDays = 90
+	u.Blocklist = []string{}
+	u.ProtectedNames = []string{"admin", "administrator", "staff", "support", "system"}
+}
+
+func (u *UsernameConfig) Verify() (bool, error) {
+	duration, err := time.ParseDuration(u.ChangeCooldown)
+	if err != nil {
+		return false, fmt.Errorf("invalid username change cooldown %q: %v", u.ChangeCooldown, err)
+	}
+	if duration < 0 {
+		return false, fmt.Errorf("username change cooldown must not be negative")
+	}
+	u.ChangeCooldownDuration = duration
+	if u.ReservationDays < 0 {
+		return false, fmt.Errorf("username reservation days must not be negative")
+	}
+	u.ReservationDuration = time.Duration(u.ReservationDays) * 24 * time.Hour
+	for _, word := range u.Blocklist {
+		if word == "" {
+			return false, fmt.Errorf("username blocklist entry must not be empty")
+		}
+	}
+	for _, name := range u.ProtectedNames {
+		if name == "" {
+			return false, fmt.Errorf("protected username must not be empty")
+		}
+	}
+	return true, nil
+}
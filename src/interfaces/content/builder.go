@@ -2,8 +2,10 @@
 package content
 
 import (
+	"user-service/src/interfaces/challenge"
 	c "user-service/src/interfaces/config"
 	"user-service/src/interfaces/repository"
+	"user-service/src/interfaces/storage"
 
 	"half-nothing.cn/service-core/interfaces/cleaner"
 	"half-nothing.cn/service-core/interfaces/config"
@@ -46,6 +48,76 @@ func (builder *ApplicationContentBuilder) SetRoleRepo(roleRepo repository.RoleIn
 	return builder
 }
 
+func (builder *ApplicationContentBuilder) SetDivisionRepo(divisionRepo repository.DivisionInterface) *ApplicationContentBuilder {
+	builder.content.divisionRepo = divisionRepo
+	return builder
+}
+
+func (builder *ApplicationContentBuilder) SetDeletionRepo(deletionRepo repository.DeletionInterface) *ApplicationContentBuilder {
+	builder.content.deletionRepo = deletionRepo
+	return builder
+}
+
+func (builder *ApplicationContentBuilder) SetExportRepo(exportRepo repository.ExportInterface) *ApplicationContentBuilder {
+	builder.content.exportRepo = exportRepo
+	return builder
+}
+
+func (builder *ApplicationContentBuilder) SetProfileRepo(profileRepo repository.ProfileInterface) *ApplicationContentBuilder {
+	builder.content.profileRepo = profileRepo
+	return builder
+}
+
+func (builder *ApplicationContentBuilder) SetProfileFieldRepo(profileFieldRepo repository.ProfileFieldInterface) *ApplicationContentBuilder {
+	builder.content.profileFieldRepo = profileFieldRepo
+	return builder
+}
+
+func (builder *ApplicationContentBuilder) SetAvatarRepo(avatarRepo repository.AvatarInterface) *ApplicationContentBuilder {
+	builder.content.avatarRepo = avatarRepo
+	return builder
+}
+
+func (builder *ApplicationContentBuilder) SetAvatarStorage(avatarStorage storage.Interface) *ApplicationContentBuilder {
+	builder.content.avatarStorage = avatarStorage
+	return builder
+}
+
+func (builder *ApplicationContentBuilder) SetRatingRepo(ratingRepo repository.RatingInterface) *ApplicationContentBuilder {
+	builder.content.ratingRepo = ratingRepo
+	return builder
+}
+
+func (builder *ApplicationContentBuilder) SetInstructorRepo(instructorRepo repository.InstructorInterface) *ApplicationContentBuilder {
+	builder.content.instructorRepo = instructorRepo
+	return builder
+}
+
+func (builder *ApplicationContentBuilder) SetCidRepo(cidRepo repository.CidInterface) *ApplicationContentBuilder {
+	builder.content.cidRepo = cidRepo
+	return builder
+}
+
+func (builder *ApplicationContentBuilder) SetEmailChangeRepo(emailChangeRepo repository.EmailChangeInterface) *ApplicationContentBuilder {
+	builder.content.emailChangeRepo = emailChangeRepo
+	return builder
+}
+
+func (builder *ApplicationContentBuilder) SetSessionRepo(sessionRepo repository.SessionInterface) *ApplicationContentBuilder {
+	builder.content.sessionRepo = sessionRepo
+	return builder
+}
+
+func (builder *ApplicationContentBuilder) SetUsernameRepo(usernameRepo repository.UsernameInterface) *ApplicationContentBuilder {
+	builder.content.usernameRepo = usernameRepo
+	return builder
+}
+
+func (builder *ApplicationContentBuilder) SetChallenge(challenge challenge.Interface) *ApplicationContentBuilder {
+	builder.content.challenge = challenge
+	return builder
+}
+
 func (builder *ApplicationContentBuilder) SetJwtClaimFactory(claimFactory jwt.ClaimFactoryInterface) *ApplicationContentBuilder {
 	builder.content.claimFactory = claimFactory
 	return builder
@@ -2,8 +2,10 @@
 package content
 
 import (
+	"user-service/src/interfaces/challenge"
 	c "user-service/src/interfaces/config"
 	"user-service/src/interfaces/repository"
+	"user-service/src/interfaces/storage"
 
 	"half-nothing.cn/service-core/interfaces/cleaner"
 	"half-nothing.cn/service-core/interfaces/config"
@@ -19,6 +21,20 @@ type ApplicationContent struct {
 	claimFactory      jwt.ClaimFactoryInterface          // JWT 令牌工厂
 	userRepo          repository.UserInterface           // 用户数据库
 	roleRepo          repository.RoleInterface           // 角色数据库
+	divisionRepo      repository.DivisionInterface       // 分区数据库
+	deletionRepo      repository.DeletionInterface       // 账户注销数据库
+	exportRepo        repository.ExportInterface         // 个人数据导出数据库
+	profileRepo       repository.ProfileInterface        // 用户扩展资料数据库
+	profileFieldRepo  repository.ProfileFieldInterface   // 自定义资料字段数据库
+	avatarRepo        repository.AvatarInterface         // 头像数据库
+	avatarStorage     storage.Interface                  // 头像文件存储
+	ratingRepo        repository.RatingInterface         // 管制员等级变更数据库
+	instructorRepo    repository.InstructorInterface     // 教员指派数据库
+	cidRepo           repository.CidInterface            // 呼号分配数据库
+	emailChangeRepo   repository.EmailChangeInterface    // 邮箱修改数据库
+	sessionRepo       repository.SessionInterface        // 会话失效数据库
+	usernameRepo      repository.UsernameInterface       // 用户名修改记录数据库
+	challenge         challenge.Interface                // 人机验证
 	grpcClientManager *GrpcClientManager
 }
 
@@ -42,6 +58,62 @@ func (app *ApplicationContent) RoleRepo() repository.RoleInterface {
 	return app.roleRepo
 }
 
+func (app *ApplicationContent) DivisionRepo() repository.DivisionInterface {
+	return app.divisionRepo
+}
+
+func (app *ApplicationContent) DeletionRepo() repository.DeletionInterface {
+	return app.deletionRepo
+}
+
+func (app *ApplicationContent) ExportRepo() repository.ExportInterface {
+	return app.exportRepo
+}
+
+func (app *ApplicationContent) ProfileRepo() repository.ProfileInterface {
+	return app.profileRepo
+}
+
+func (app *ApplicationContent) ProfileFieldRepo() repository.ProfileFieldInterface {
+	return app.profileFieldRepo
+}
+
+func (app *ApplicationContent) AvatarRepo() repository.AvatarInterface {
+	return app.avatarRepo
+}
+
+func (app *ApplicationContent) AvatarStorage() storage.Interface {
+	return app.avatarStorage
+}
+
+func (app *ApplicationContent) RatingRepo() repository.RatingInterface {
+	return app.ratingRepo
+}
+
+func (app *ApplicationContent) InstructorRepo() repository.InstructorInterface {
+	return app.instructorRepo
+}
+
+func (app *ApplicationContent) CidRepo() repository.CidInterface {
+	return app.cidRepo
+}
+
+func (app *ApplicationContent) EmailChangeRepo() repository.EmailChangeInterface {
+	return app.emailChangeRepo
+}
+
+func (app *ApplicationContent) SessionRepo() repository.SessionInterface {
+	return app.sessionRepo
+}
+
+func (app *ApplicationContent) UsernameRepo() repository.UsernameInterface {
+	return app.usernameRepo
+}
+
+func (app *ApplicationContent) Challenge() challenge.Interface {
+	return app.challenge
+}
+
 func (app *ApplicationContent) GrpcClientManager() *GrpcClientManager {
 	return app.grpcClientManager
 }
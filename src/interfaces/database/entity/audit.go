@@ -0,0 +1,44 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity 本服务扩展的数据库实体
+package entity
+
+// AuditEvent 本服务扩展的审计事件
+type AuditEvent struct {
+	Value       string
+	Description string
+}
+
+var (
+	AuditEventPermissionEscalationDenied = &AuditEvent{Value: "PERMISSION_ESCALATION_DENIED", Description: "越权操作被拒绝"}
+	AuditEventDivisionCreated            = &AuditEvent{Value: "DIVISION_CREATED", Description: "创建分区"}
+	AuditEventDivisionUpdated            = &AuditEvent{Value: "DIVISION_UPDATED", Description: "修改分区"}
+	AuditEventDivisionDeleted            = &AuditEvent{Value: "DIVISION_DELETED", Description: "删除分区"}
+	AuditEventDivisionMemberAdd          = &AuditEvent{Value: "DIVISION_MEMBER_ADD", Description: "添加分区成员"}
+	AuditEventDivisionMemberRemove       = &AuditEvent{Value: "DIVISION_MEMBER_REMOVE", Description: "移除分区成员"}
+	AuditEventScopedRoleGrant            = &AuditEvent{Value: "SCOPED_ROLE_GRANT", Description: "授予分区角色"}
+	AuditEventScopedRoleRevoke           = &AuditEvent{Value: "SCOPED_ROLE_REVOKE", Description: "撤销分区角色"}
+	AuditEventRoleImported               = &AuditEvent{Value: "ROLE_IMPORTED", Description: "导入角色配置"}
+	AuditEventRolePermissionLost         = &AuditEvent{Value: "ROLE_PERMISSION_LOST", Description: "删除角色导致用户失去权限"}
+	AuditEventAccountDeletionRequested   = &AuditEvent{Value: "ACCOUNT_DELETION_REQUESTED", Description: "申请注销账户"}
+	AuditEventAccountDeletionCancelled   = &AuditEvent{Value: "ACCOUNT_DELETION_CANCELLED", Description: "取消注销账户"}
+	AuditEventAccountDeleted             = &AuditEvent{Value: "ACCOUNT_DELETED", Description: "账户已注销并匿名化"}
+	AuditEventDataExportRequested        = &AuditEvent{Value: "DATA_EXPORT_REQUESTED", Description: "申请导出个人数据"}
+	AuditEventDataExportDownloaded       = &AuditEvent{Value: "DATA_EXPORT_DOWNLOADED", Description: "下载个人数据导出文件"}
+	AuditEventProfileFieldCreated        = &AuditEvent{Value: "PROFILE_FIELD_CREATED", Description: "创建自定义资料字段"}
+	AuditEventProfileFieldUpdated        = &AuditEvent{Value: "PROFILE_FIELD_UPDATED", Description: "修改自定义资料字段"}
+	AuditEventProfileFieldDeleted        = &AuditEvent{Value: "PROFILE_FIELD_DELETED", Description: "删除自定义资料字段"}
+	AuditEventAvatarUploaded             = &AuditEvent{Value: "AVATAR_UPLOADED", Description: "上传头像"}
+	AuditEventAvatarRemoved              = &AuditEvent{Value: "AVATAR_REMOVED", Description: "管理员移除头像"}
+	AuditEventRatingChanged              = &AuditEvent{Value: "ATC_RATING_CHANGED", Description: "修改管制员等级"}
+	AuditEventInstructorAssigned         = &AuditEvent{Value: "INSTRUCTOR_ASSIGNED", Description: "指派教员"}
+	AuditEventInstructorUnassigned       = &AuditEvent{Value: "INSTRUCTOR_UNASSIGNED", Description: "取消教员指派"}
+	AuditEventCidReserved                = &AuditEvent{Value: "CID_RESERVED", Description: "保留呼号"}
+	AuditEventCidReleased                = &AuditEvent{Value: "CID_RELEASED", Description: "释放保留呼号"}
+	AuditEventCidAssigned                = &AuditEvent{Value: "CID_ASSIGNED", Description: "为用户指定呼号"}
+	AuditEventEmailChangeRequested       = &AuditEvent{Value: "EMAIL_CHANGE_REQUESTED", Description: "申请修改邮箱"}
+	AuditEventEmailChangeConfirmed       = &AuditEvent{Value: "EMAIL_CHANGE_CONFIRMED", Description: "确认修改邮箱"}
+	AuditEventEmailChangeCancelled       = &AuditEvent{Value: "EMAIL_CHANGE_CANCELLED", Description: "取消修改邮箱"}
+	AuditEventEmailChangeReverted        = &AuditEvent{Value: "EMAIL_CHANGE_REVERTED", Description: "通过原邮箱恢复邮箱"}
+)
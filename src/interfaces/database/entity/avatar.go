@@ -0,0 +1,32 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+// 头像状态
+const (
+	AvatarStatusActive  = "active"
+	AvatarStatusRemoved = "removed"
+)
+
+// Avatar 用户上传的头像, 保留历史记录, 当前头像为用户 image_id 对应的记录
+type Avatar struct {
+	ID            uint         `gorm:"primarykey"`
+	UserId        uint         `gorm:"index;not null"`
+	ImageId       uint         `gorm:"index;not null"`
+	Key           string       `gorm:"size:64;uniqueIndex;not null"`
+	ContentType   string       `gorm:"size:32;not null"`
+	Size          int64        `gorm:"not null"`
+	Status        string       `gorm:"size:16;not null"`
+	RemovedBy     *uint        `gorm:"default:null"`
+	RemovedReason string       `gorm:"size:255"`
+	RemovedAt     sql.NullTime `gorm:"default:null"`
+	CreatedAt     time.Time    `gorm:"not null"`
+	UpdatedAt     time.Time    `gorm:"not null"`
+}
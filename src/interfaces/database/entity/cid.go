@@ -0,0 +1,28 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity
+package entity
+
+import "time"
+
+// CidSequenceId 用户呼号分配序列的记录 ID
+const CidSequenceId = 1
+
+// CidSequence 呼号分配序列, 分配时对该记录加行锁, 保证多个实例之间不会分配出相同的呼号
+type CidSequence struct {
+	ID        uint      `gorm:"primarykey"`
+	Next      uint      `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+// CidReservation 管理员保留的呼号, UserId 不为空时表示已分配给该用户
+type CidReservation struct {
+	ID         uint      `gorm:"primarykey"`
+	Cid        uint      `gorm:"uniqueIndex;not null"`
+	Comment    string    `gorm:"size:255;not null"`
+	ReservedBy uint      `gorm:"not null"`
+	UserId     *uint     `gorm:"index;default:null"`
+	CreatedAt  time.Time `gorm:"not null"`
+	UpdatedAt  time.Time `gorm:"not null"`
+}
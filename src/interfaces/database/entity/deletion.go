@@ -0,0 +1,31 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+// 账户删除请求状态
+const (
+	DeletionStatusPending   = "pending"
+	DeletionStatusCancelled = "cancelled"
+	DeletionStatusCompleted = "completed"
+)
+
+// AccountDeletion 账户删除请求, 宽限期结束后由定时任务匿名化用户数据
+type AccountDeletion struct {
+	ID          uint         `gorm:"primarykey"`
+	UserId      uint         `gorm:"index;not null"`
+	Cid         uint         `gorm:"not null"`
+	OperatorCid uint         `gorm:"not null"`
+	Status      string       `gorm:"size:16;index;not null"`
+	Reason      string       `gorm:"size:255"`
+	ScheduledAt time.Time    `gorm:"index;not null"`
+	CompletedAt sql.NullTime `gorm:"default:null"`
+	CreatedAt   time.Time    `gorm:"not null"`
+	UpdatedAt   time.Time    `gorm:"not null"`
+}
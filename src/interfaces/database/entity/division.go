@@ -0,0 +1,42 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity
+package entity
+
+import (
+	"time"
+
+	"half-nothing.cn/service-core/interfaces/database/entity"
+)
+
+// Division 分区(FIR/管制分部), 作为权限的作用范围
+type Division struct {
+	ID        uint      `gorm:"primarykey"`
+	Code      string    `gorm:"size:32;uniqueIndex;not null"`
+	Name      string    `gorm:"size:64;not null"`
+	Comment   string    `gorm:"size:255"`
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+// UserDivision 用户所属分区
+type UserDivision struct {
+	ID         uint         `gorm:"primarykey"`
+	UserId     uint         `gorm:"uniqueIndex:idx_user_division;not null"`
+	DivisionId uint         `gorm:"uniqueIndex:idx_user_division;index;not null"`
+	CreatedAt  time.Time    `gorm:"not null"`
+	User       *entity.User `gorm:"foreignKey:UserId"`
+	Division   *Division    `gorm:"foreignKey:DivisionId"`
+}
+
+// DivisionRole 绑定到分区的角色授予, 角色权限仅在该分区内生效
+type DivisionRole struct {
+	ID         uint         `gorm:"primarykey"`
+	UserId     uint         `gorm:"uniqueIndex:idx_division_role;not null"`
+	RoleId     uint         `gorm:"uniqueIndex:idx_division_role;index;not null"`
+	DivisionId uint         `gorm:"uniqueIndex:idx_division_role;index;not null"`
+	CreatedAt  time.Time    `gorm:"not null"`
+	Role       *entity.Role `gorm:"foreignKey:RoleId"`
+	Division   *Division    `gorm:"foreignKey:DivisionId"`
+}
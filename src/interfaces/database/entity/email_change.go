@@ -0,0 +1,44 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+// 邮箱修改请求状态
+const (
+	EmailChangeStatusPending   = "pending"
+	EmailChangeStatusConfirmed = "confirmed"
+	EmailChangeStatusCancelled = "cancelled"
+	EmailChangeStatusReverted  = "reverted"
+)
+
+// EmailChange 邮箱修改请求
+//
+// 新邮箱通过确认链接确认后修改才会生效, 有效期内原邮箱可以通过撤销链接取消尚未确认的修改或恢复原邮箱,
+// 数据库中只保存两个令牌的 SHA-256 摘要
+type EmailChange struct {
+	ID               uint         `gorm:"primarykey"`
+	UserId           uint         `gorm:"index;not null"`
+	OldEmail         string       `gorm:"size:128;not null"`
+	NewEmail         string       `gorm:"size:128;not null"`
+	ConfirmTokenHash string       `gorm:"size:64;uniqueIndex;not null"`
+	RevertTokenHash  string       `gorm:"size:64;uniqueIndex;not null"`
+	Status           string       `gorm:"size:16;index;not null"`
+	Ip               string       `gorm:"size:64"`
+	UserAgent        string       `gorm:"size:255"`
+	ExpiresAt        time.Time    `gorm:"not null"`
+	ConfirmedAt      sql.NullTime `gorm:"default:null"`
+	ClosedAt         sql.NullTime `gorm:"default:null"`
+	CreatedAt        time.Time    `gorm:"not null"`
+	UpdatedAt        time.Time    `gorm:"not null"`
+}
+
+// Expired 确认与撤销链接是否已过期
+func (change *EmailChange) Expired(now time.Time) bool {
+	return !now.Before(change.ExpiresAt)
+}
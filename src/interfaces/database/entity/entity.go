@@ -0,0 +1,30 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity 本服务扩展的数据库实体
+package entity
+
+// Models 返回本服务扩展的所有数据库实体, 用于自动迁移
+func Models() []interface{} {
+	return []interface{}{
+		&Division{},
+		&UserDivision{},
+		&DivisionRole{},
+		&AccountDeletion{},
+		&DataExport{},
+		&UserProfile{},
+		&ProfileField{},
+		&UserFieldValue{},
+		&Avatar{},
+		&RatingChange{},
+		&InstructorAssignment{},
+		&CidSequence{},
+		&CidReservation{},
+		&EmailChange{},
+		&SessionRevocation{},
+		&PasswordChangeRequirement{},
+		&UsernameChange{},
+		&UserEmail{},
+		&UserSkeleton{},
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+// 个人数据导出状态
+const (
+	ExportStatusPending   = "pending"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+)
+
+// 个人数据导出格式
+const (
+	ExportFormatJson = "json"
+	ExportFormatZip  = "zip"
+)
+
+// DataExport 个人数据导出任务, 生成的归档在过期前可供用户下载
+type DataExport struct {
+	ID          uint         `gorm:"primarykey"`
+	UserId      uint         `gorm:"index;not null"`
+	Format      string       `gorm:"size:8;not null"`
+	Status      string       `gorm:"size:16;not null"`
+	Data        []byte       `gorm:"default:null"`
+	CompletedAt sql.NullTime `gorm:"default:null"`
+	ExpiresAt   sql.NullTime `gorm:"default:null"`
+	CreatedAt   time.Time    `gorm:"not null"`
+	UpdatedAt   time.Time    `gorm:"not null"`
+}
@@ -0,0 +1,23 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+// InstructorAssignment 学员的教员指派记录, 未结束的记录为学员当前的教员
+type InstructorAssignment struct {
+	ID           uint         `gorm:"primarykey"`
+	TraineeId    uint         `gorm:"index;not null"`
+	InstructorId uint         `gorm:"index;not null"`
+	AssignedBy   uint         `gorm:"not null"`
+	Reason       string       `gorm:"size:255;not null"`
+	EndedBy      *uint        `gorm:"default:null"`
+	EndReason    string       `gorm:"size:255"`
+	EndedAt      sql.NullTime `gorm:"index;default:null"`
+	CreatedAt    time.Time    `gorm:"not null"`
+}
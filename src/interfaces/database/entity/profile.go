@@ -0,0 +1,45 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity
+package entity
+
+import "time"
+
+// UserProfile 用户扩展资料
+type UserProfile struct {
+	ID          uint   `gorm:"primarykey"`
+	UserId      uint   `gorm:"uniqueIndex;not null"`
+	DisplayName string `gorm:"size:64"`
+	RealName    string `gorm:"size:64"`
+	Timezone    string `gorm:"size:64"`
+	Language    string `gorm:"size:16"`
+	DivisionId  *uint  `gorm:"index;default:null"`
+	// 公开资料的隐私设置, 控制其他用户能否看到对应内容, 默认均不公开
+	ShowUsername bool `gorm:"not null;default:false"`
+	ShowAvatar   bool `gorm:"not null;default:false"`
+	ShowRating   bool `gorm:"not null;default:false"`
+	ShowRoles    bool `gorm:"not null;default:false"`
+	ShowDivision bool `gorm:"not null;default:false"`
+	// 是否允许使用 QQ 头像与 Gravatar 头像
+	AllowQQAvatar bool              `gorm:"not null;default:true"`
+	AllowGravatar bool              `gorm:"not null;default:true"`
+	CreatedAt     time.Time         `gorm:"not null"`
+	UpdatedAt     time.Time         `gorm:"not null"`
+	Division      *Division         `gorm:"foreignKey:DivisionId"`
+	Fields        []*UserFieldValue `gorm:"foreignKey:UserId;references:UserId"`
+}
+
+// DefaultUserProfile 用户尚未填写资料时使用的默认资料, 隐私设置与数据库默认值一致
+func DefaultUserProfile(userId uint) *UserProfile {
+	return &UserProfile{
+		UserId:        userId,
+		ShowUsername:  false,
+		ShowAvatar:    false,
+		ShowRating:    false,
+		ShowRoles:     false,
+		ShowDivision:  false,
+		AllowQQAvatar: true,
+		AllowGravatar: true,
+	}
+}
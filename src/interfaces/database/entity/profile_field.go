@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity
+package entity
+
+import "time"
+
+// 自定义资料字段类型
+const (
+	FieldTypeString = "string"
+	FieldTypeInt    = "int"
+	FieldTypeBool   = "bool"
+	FieldTypeEnum   = "enum"
+	FieldTypeDate   = "date"
+)
+
+// FieldTypes 支持的自定义资料字段类型
+var FieldTypes = []string{FieldTypeString, FieldTypeInt, FieldTypeBool, FieldTypeEnum, FieldTypeDate}
+
+// 资料的可见范围与可编辑范围, 由宽到窄排列
+const (
+	VisibilityPublic = "public"
+	VisibilitySelf   = "self"
+	VisibilityStaff  = "staff"
+)
+
+var visibilityRank = map[string]int{
+	VisibilityPublic: 0,
+	VisibilitySelf:   1,
+	VisibilityStaff:  2,
+}
+
+// ValidVisibility 判断可见范围是否合法
+func ValidVisibility(visibility string) bool {
+	_, ok := visibilityRank[visibility]
+	return ok
+}
+
+// CanView 判断处于 viewer 范围的查看者能否看到 visibility 范围的资料
+func CanView(visibility string, viewer string) bool {
+	rank, ok := visibilityRank[visibility]
+	if !ok {
+		return false
+	}
+	return rank <= visibilityRank[viewer]
+}
+
+// ProfileField 管理员定义的自定义资料字段
+type ProfileField struct {
+	ID         uint      `gorm:"primarykey"`
+	Key        string    `gorm:"size:32;uniqueIndex;not null"`
+	Name       string    `gorm:"size:64;not null"`
+	Type       string    `gorm:"size:16;not null"`
+	Options    []string  `gorm:"serializer:json"`
+	Regex      string    `gorm:"size:255"`
+	Visibility string    `gorm:"size:16;not null"`
+	Editable   string    `gorm:"size:16;not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+	UpdatedAt  time.Time `gorm:"not null"`
+}
+
+// UserFieldValue 用户的自定义资料字段值
+type UserFieldValue struct {
+	ID        uint          `gorm:"primarykey"`
+	UserId    uint          `gorm:"uniqueIndex:idx_user_field;not null"`
+	FieldId   uint          `gorm:"uniqueIndex:idx_user_field;index:idx_field_value;not null"`
+	Value     string        `gorm:"size:255;index:idx_field_value;not null"`
+	CreatedAt time.Time     `gorm:"not null"`
+	UpdatedAt time.Time     `gorm:"not null"`
+	Field     *ProfileField `gorm:"foreignKey:FieldId"`
+}
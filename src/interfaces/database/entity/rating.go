@@ -0,0 +1,18 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity
+package entity
+
+import "time"
+
+// RatingChange 管制员等级变更记录
+type RatingChange struct {
+	ID         uint      `gorm:"primarykey"`
+	UserId     uint      `gorm:"index;not null"`
+	OldRating  int       `gorm:"not null"`
+	NewRating  int       `gorm:"not null"`
+	OperatorId uint      `gorm:"index;not null"`
+	Reason     string    `gorm:"size:255;not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+}
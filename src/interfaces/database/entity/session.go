@@ -0,0 +1,20 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity
+package entity
+
+import "time"
+
+// SessionRevocation 用户会话失效记录, 签发时间早于 RevokedAt 的访问令牌与刷新令牌均不能再使用
+type SessionRevocation struct {
+	UserId    uint      `gorm:"primarykey;autoIncrement:false"`
+	RevokedAt time.Time `gorm:"not null"`
+}
+
+// PasswordChangeRequirement 管理员要求用户修改密码, 记录存在时用户需要修改密码后才能正常使用账户
+type PasswordChangeRequirement struct {
+	UserId      uint      `gorm:"primarykey;autoIncrement:false"`
+	OperatorCid uint      `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"not null"`
+}
@@ -0,0 +1,11 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity
+package entity
+
+// UserEmail 用户邮箱的规范化形式, 用于保证按服务商规则视为相同的邮箱只能被一个用户使用
+type UserEmail struct {
+	UserId    uint   `gorm:"primarykey;autoIncrement:false"`
+	Canonical string `gorm:"size:128;uniqueIndex;not null"`
+}
@@ -0,0 +1,11 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity
+package entity
+
+// UserSkeleton 用户名的骨架, 用于查找与其他用户名容易混淆的用户名
+type UserSkeleton struct {
+	UserId   uint   `gorm:"primarykey;autoIncrement:false"`
+	Skeleton string `gorm:"size:64;index;not null"`
+}
@@ -0,0 +1,23 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package entity
+package entity
+
+import "time"
+
+// UsernameChange 用户名修改记录
+//
+// 旧用户名在 ReservedUntil 之前保留给原用户, 其他用户不能使用与其相似的用户名,
+// 只有 SelfService 为 true 的用户自行修改计入修改间隔
+type UsernameChange struct {
+	ID            uint      `gorm:"primarykey"`
+	UserId        uint      `gorm:"index;not null"`
+	OldUsername   string    `gorm:"size:64;not null"`
+	NewUsername   string    `gorm:"size:64;not null"`
+	OldSkeleton   string    `gorm:"size:64;index;not null"`
+	OperatorCid   uint      `gorm:"not null"`
+	SelfService   bool      `gorm:"not null;default:false"`
+	ReservedUntil time.Time `gorm:"not null"`
+	CreatedAt     time.Time `gorm:"not null"`
+}
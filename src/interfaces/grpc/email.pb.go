@@ -1444,6 +1444,282 @@ func (x *EmailChange) GetUserAgent() string {
 	return ""
 }
 
+type EmailChangeConfirm struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetEmail   string                 `protobuf:"bytes,1,opt,name=targetEmail,proto3" json:"targetEmail,omitempty"`
+	Cid           string                 `protobuf:"bytes,2,opt,name=cid,proto3" json:"cid,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Link          string                 `protobuf:"bytes,4,opt,name=link,proto3" json:"link,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,5,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
+	Ip            string                 `protobuf:"bytes,6,opt,name=ip,proto3" json:"ip,omitempty"`
+	UserAgent     string                 `protobuf:"bytes,7,opt,name=userAgent,proto3" json:"userAgent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmailChangeConfirm) Reset() {
+	*x = EmailChangeConfirm{}
+	mi := &file_email_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmailChangeConfirm) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmailChangeConfirm) ProtoMessage() {}
+
+func (x *EmailChangeConfirm) ProtoReflect() protoreflect.Message {
+	mi := &file_email_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmailChangeConfirm.ProtoReflect.Descriptor instead.
+func (*EmailChangeConfirm) Descriptor() ([]byte, []int) {
+	return file_email_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *EmailChangeConfirm) GetTargetEmail() string {
+	if x != nil {
+		return x.TargetEmail
+	}
+	return ""
+}
+
+func (x *EmailChangeConfirm) GetCid() string {
+	if x != nil {
+		return x.Cid
+	}
+	return ""
+}
+
+func (x *EmailChangeConfirm) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *EmailChangeConfirm) GetLink() string {
+	if x != nil {
+		return x.Link
+	}
+	return ""
+}
+
+func (x *EmailChangeConfirm) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *EmailChangeConfirm) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *EmailChangeConfirm) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+type EmailChangeRevert struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetEmail   string                 `protobuf:"bytes,1,opt,name=targetEmail,proto3" json:"targetEmail,omitempty"`
+	Cid           string                 `protobuf:"bytes,2,opt,name=cid,proto3" json:"cid,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Link          string                 `protobuf:"bytes,4,opt,name=link,proto3" json:"link,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,5,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
+	Ip            string                 `protobuf:"bytes,6,opt,name=ip,proto3" json:"ip,omitempty"`
+	UserAgent     string                 `protobuf:"bytes,7,opt,name=userAgent,proto3" json:"userAgent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmailChangeRevert) Reset() {
+	*x = EmailChangeRevert{}
+	mi := &file_email_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmailChangeRevert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmailChangeRevert) ProtoMessage() {}
+
+func (x *EmailChangeRevert) ProtoReflect() protoreflect.Message {
+	mi := &file_email_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmailChangeRevert.ProtoReflect.Descriptor instead.
+func (*EmailChangeRevert) Descriptor() ([]byte, []int) {
+	return file_email_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *EmailChangeRevert) GetTargetEmail() string {
+	if x != nil {
+		return x.TargetEmail
+	}
+	return ""
+}
+
+func (x *EmailChangeRevert) GetCid() string {
+	if x != nil {
+		return x.Cid
+	}
+	return ""
+}
+
+func (x *EmailChangeRevert) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *EmailChangeRevert) GetLink() string {
+	if x != nil {
+		return x.Link
+	}
+	return ""
+}
+
+func (x *EmailChangeRevert) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *EmailChangeRevert) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *EmailChangeRevert) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+type AccountDeletion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetEmail   string                 `protobuf:"bytes,1,opt,name=targetEmail,proto3" json:"targetEmail,omitempty"`
+	Cid           string                 `protobuf:"bytes,2,opt,name=cid,proto3" json:"cid,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // pending = 已申请注销, 将于 scheduledAt 注销; completed = 已注销
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	ScheduledAt   string                 `protobuf:"bytes,5,opt,name=scheduledAt,proto3" json:"scheduledAt,omitempty"`
+	Operator      string                 `protobuf:"bytes,6,opt,name=operator,proto3" json:"operator,omitempty"`
+	Contact       string                 `protobuf:"bytes,7,opt,name=contact,proto3" json:"contact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountDeletion) Reset() {
+	*x = AccountDeletion{}
+	mi := &file_email_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountDeletion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountDeletion) ProtoMessage() {}
+
+func (x *AccountDeletion) ProtoReflect() protoreflect.Message {
+	mi := &file_email_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountDeletion.ProtoReflect.Descriptor instead.
+func (*AccountDeletion) Descriptor() ([]byte, []int) {
+	return file_email_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AccountDeletion) GetTargetEmail() string {
+	if x != nil {
+		return x.TargetEmail
+	}
+	return ""
+}
+
+func (x *AccountDeletion) GetCid() string {
+	if x != nil {
+		return x.Cid
+	}
+	return ""
+}
+
+func (x *AccountDeletion) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AccountDeletion) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AccountDeletion) GetScheduledAt() string {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return ""
+}
+
+func (x *AccountDeletion) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *AccountDeletion) GetContact() string {
+	if x != nil {
+		return x.Contact
+	}
+	return ""
+}
+
 type SendResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -1453,7 +1729,7 @@ type SendResponse struct {
 
 func (x *SendResponse) Reset() {
 	*x = SendResponse{}
-	mi := &file_email_proto_msgTypes[19]
+	mi := &file_email_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1465,7 +1741,7 @@ func (x *SendResponse) String() string {
 func (*SendResponse) ProtoMessage() {}
 
 func (x *SendResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_email_proto_msgTypes[19]
+	mi := &file_email_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1478,7 +1754,7 @@ func (x *SendResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SendResponse.ProtoReflect.Descriptor instead.
 func (*SendResponse) Descriptor() ([]byte, []int) {
-	return file_email_proto_rawDescGZIP(), []int{19}
+	return file_email_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *SendResponse) GetSuccess() bool {
@@ -1498,7 +1774,7 @@ type VerifyCode struct {
 
 func (x *VerifyCode) Reset() {
 	*x = VerifyCode{}
-	mi := &file_email_proto_msgTypes[20]
+	mi := &file_email_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1510,7 +1786,7 @@ func (x *VerifyCode) String() string {
 func (*VerifyCode) ProtoMessage() {}
 
 func (x *VerifyCode) ProtoReflect() protoreflect.Message {
-	mi := &file_email_proto_msgTypes[20]
+	mi := &file_email_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1523,7 +1799,7 @@ func (x *VerifyCode) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifyCode.ProtoReflect.Descriptor instead.
 func (*VerifyCode) Descriptor() ([]byte, []int) {
-	return file_email_proto_rawDescGZIP(), []int{20}
+	return file_email_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *VerifyCode) GetCode() string {
@@ -1550,7 +1826,7 @@ type VerifyResponse struct {
 
 func (x *VerifyResponse) Reset() {
 	*x = VerifyResponse{}
-	mi := &file_email_proto_msgTypes[21]
+	mi := &file_email_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1562,7 +1838,7 @@ func (x *VerifyResponse) String() string {
 func (*VerifyResponse) ProtoMessage() {}
 
 func (x *VerifyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_email_proto_msgTypes[21]
+	mi := &file_email_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1575,7 +1851,7 @@ func (x *VerifyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifyResponse.ProtoReflect.Descriptor instead.
 func (*VerifyResponse) Descriptor() ([]byte, []int) {
-	return file_email_proto_rawDescGZIP(), []int{21}
+	return file_email_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *VerifyResponse) GetSuccess() bool {
@@ -1601,7 +1877,7 @@ type RemoveVerifyCode struct {
 
 func (x *RemoveVerifyCode) Reset() {
 	*x = RemoveVerifyCode{}
-	mi := &file_email_proto_msgTypes[22]
+	mi := &file_email_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1613,7 +1889,7 @@ func (x *RemoveVerifyCode) String() string {
 func (*RemoveVerifyCode) ProtoMessage() {}
 
 func (x *RemoveVerifyCode) ProtoReflect() protoreflect.Message {
-	mi := &file_email_proto_msgTypes[22]
+	mi := &file_email_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1626,7 +1902,7 @@ func (x *RemoveVerifyCode) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveVerifyCode.ProtoReflect.Descriptor instead.
 func (*RemoveVerifyCode) Descriptor() ([]byte, []int) {
-	return file_email_proto_rawDescGZIP(), []int{22}
+	return file_email_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *RemoveVerifyCode) GetEmail() string {
@@ -1645,7 +1921,7 @@ type RemoveVerifyCodeResponse struct {
 
 func (x *RemoveVerifyCodeResponse) Reset() {
 	*x = RemoveVerifyCodeResponse{}
-	mi := &file_email_proto_msgTypes[23]
+	mi := &file_email_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1657,7 +1933,7 @@ func (x *RemoveVerifyCodeResponse) String() string {
 func (*RemoveVerifyCodeResponse) ProtoMessage() {}
 
 func (x *RemoveVerifyCodeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_email_proto_msgTypes[23]
+	mi := &file_email_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1670,7 +1946,7 @@ func (x *RemoveVerifyCodeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveVerifyCodeResponse.ProtoReflect.Descriptor instead.
 func (*RemoveVerifyCodeResponse) Descriptor() ([]byte, []int) {
-	return file_email_proto_rawDescGZIP(), []int{23}
+	return file_email_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *RemoveVerifyCodeResponse) GetSuccess() bool {
@@ -1798,7 +2074,31 @@ const file_email_proto_rawDesc = "" +
 	"\x05email\x18\x03 \x01(\tR\x05email\x12\x12\n" +
 	"\x04time\x18\x04 \x01(\tR\x04time\x12\x0e\n" +
 	"\x02ip\x18\x05 \x01(\tR\x02ip\x12\x1c\n" +
-	"\tuserAgent\x18\x06 \x01(\tR\tuserAgent\"(\n" +
+	"\tuserAgent\x18\x06 \x01(\tR\tuserAgent\"\xbe\x01\n" +
+	"\x12EmailChangeConfirm\x12 \n" +
+	"\vtargetEmail\x18\x01 \x01(\tR\vtargetEmail\x12\x10\n" +
+	"\x03cid\x18\x02 \x01(\tR\x03cid\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x12\n" +
+	"\x04link\x18\x04 \x01(\tR\x04link\x12\x1c\n" +
+	"\texpiresAt\x18\x05 \x01(\tR\texpiresAt\x12\x0e\n" +
+	"\x02ip\x18\x06 \x01(\tR\x02ip\x12\x1c\n" +
+	"\tuserAgent\x18\a \x01(\tR\tuserAgent\"\xbd\x01\n" +
+	"\x11EmailChangeRevert\x12 \n" +
+	"\vtargetEmail\x18\x01 \x01(\tR\vtargetEmail\x12\x10\n" +
+	"\x03cid\x18\x02 \x01(\tR\x03cid\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x12\n" +
+	"\x04link\x18\x04 \x01(\tR\x04link\x12\x1c\n" +
+	"\texpiresAt\x18\x05 \x01(\tR\texpiresAt\x12\x0e\n" +
+	"\x02ip\x18\x06 \x01(\tR\x02ip\x12\x1c\n" +
+	"\tuserAgent\x18\a \x01(\tR\tuserAgent\"\xcd\x01\n" +
+	"\x0fAccountDeletion\x12 \n" +
+	"\vtargetEmail\x18\x01 \x01(\tR\vtargetEmail\x12\x10\n" +
+	"\x03cid\x18\x02 \x01(\tR\x03cid\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12 \n" +
+	"\vscheduledAt\x18\x05 \x01(\tR\vscheduledAt\x12\x1a\n" +
+	"\boperator\x18\x06 \x01(\tR\boperator\x12\x18\n" +
+	"\acontact\x18\a \x01(\tR\acontact\"(\n" +
 	"\fSendResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\"6\n" +
 	"\n" +
@@ -1811,7 +2111,7 @@ const file_email_proto_rawDesc = "" +
 	"\x10RemoveVerifyCode\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\"4\n" +
 	"\x18RemoveVerifyCodeResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess2\x9d\r\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess2\x9d\x0f\n" +
 	"\x05Email\x12P\n" +
 	"\x13SendActivityAtcJoin\x12\x1d.fsd_universe.ActivityAtcJoin\x1a\x1a.fsd_universe.SendResponse\x12R\n" +
 	"\x14SendActivityAtcLeave\x12\x1e.fsd_universe.ActivityAtcLeave\x1a\x1a.fsd_universe.SendResponse\x12T\n" +
@@ -1832,7 +2132,10 @@ const file_email_proto_rawDesc = "" +
 	"\x0eSendRoleChange\x12\x18.fsd_universe.RoleChange\x1a\x1a.fsd_universe.SendResponse\x12H\n" +
 	"\x0fSendTicketReply\x12\x19.fsd_universe.TicketReply\x1a\x1a.fsd_universe.SendResponse\x12@\n" +
 	"\vSendWelcome\x12\x15.fsd_universe.Welcome\x1a\x1a.fsd_universe.SendResponse\x12H\n" +
-	"\x0fSendEmailChange\x12\x19.fsd_universe.EmailChange\x1a\x1a.fsd_universe.SendResponse\x12I\n" +
+	"\x0fSendEmailChange\x12\x19.fsd_universe.EmailChange\x1a\x1a.fsd_universe.SendResponse\x12V\n" +
+	"\x16SendEmailChangeConfirm\x12 .fsd_universe.EmailChangeConfirm\x1a\x1a.fsd_universe.SendResponse\x12T\n" +
+	"\x15SendEmailChangeRevert\x12\x1f.fsd_universe.EmailChangeRevert\x1a\x1a.fsd_universe.SendResponse\x12P\n" +
+	"\x13SendAccountDeletion\x12\x1d.fsd_universe.AccountDeletion\x1a\x1a.fsd_universe.SendResponse\x12I\n" +
 	"\x0fVerifyEmailCode\x12\x18.fsd_universe.VerifyCode\x1a\x1c.fsd_universe.VerifyResponse\x12Y\n" +
 	"\x0fRemoveEmailCode\x12\x1e.fsd_universe.RemoveVerifyCode\x1a&.fsd_universe.RemoveVerifyCodeResponseB\x15Z\x13src/interfaces/grpcb\x06proto3"
 
@@ -1848,7 +2151,7 @@ func file_email_proto_rawDescGZIP() []byte {
 	return file_email_proto_rawDescData
 }
 
-var file_email_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_email_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_email_proto_goTypes = []any{
 	(*ActivityAtcJoin)(nil),          // 0: fsd_universe.ActivityAtcJoin
 	(*ActivityAtcLeave)(nil),         // 1: fsd_universe.ActivityAtcLeave
@@ -1869,11 +2172,14 @@ var file_email_proto_goTypes = []any{
 	(*TicketReply)(nil),              // 16: fsd_universe.TicketReply
 	(*Welcome)(nil),                  // 17: fsd_universe.Welcome
 	(*EmailChange)(nil),              // 18: fsd_universe.EmailChange
-	(*SendResponse)(nil),             // 19: fsd_universe.SendResponse
-	(*VerifyCode)(nil),               // 20: fsd_universe.VerifyCode
-	(*VerifyResponse)(nil),           // 21: fsd_universe.VerifyResponse
-	(*RemoveVerifyCode)(nil),         // 22: fsd_universe.RemoveVerifyCode
-	(*RemoveVerifyCodeResponse)(nil), // 23: fsd_universe.RemoveVerifyCodeResponse
+	(*EmailChangeConfirm)(nil),       // 19: fsd_universe.EmailChangeConfirm
+	(*EmailChangeRevert)(nil),        // 20: fsd_universe.EmailChangeRevert
+	(*AccountDeletion)(nil),          // 21: fsd_universe.AccountDeletion
+	(*SendResponse)(nil),             // 22: fsd_universe.SendResponse
+	(*VerifyCode)(nil),               // 23: fsd_universe.VerifyCode
+	(*VerifyResponse)(nil),           // 24: fsd_universe.VerifyResponse
+	(*RemoveVerifyCode)(nil),         // 25: fsd_universe.RemoveVerifyCode
+	(*RemoveVerifyCodeResponse)(nil), // 26: fsd_universe.RemoveVerifyCodeResponse
 }
 var file_email_proto_depIdxs = []int32{
 	0,  // 0: fsd_universe.Email.SendActivityAtcJoin:input_type -> fsd_universe.ActivityAtcJoin
@@ -1895,31 +2201,37 @@ var file_email_proto_depIdxs = []int32{
 	16, // 16: fsd_universe.Email.SendTicketReply:input_type -> fsd_universe.TicketReply
 	17, // 17: fsd_universe.Email.SendWelcome:input_type -> fsd_universe.Welcome
 	18, // 18: fsd_universe.Email.SendEmailChange:input_type -> fsd_universe.EmailChange
-	20, // 19: fsd_universe.Email.VerifyEmailCode:input_type -> fsd_universe.VerifyCode
-	22, // 20: fsd_universe.Email.RemoveEmailCode:input_type -> fsd_universe.RemoveVerifyCode
-	19, // 21: fsd_universe.Email.SendActivityAtcJoin:output_type -> fsd_universe.SendResponse
-	19, // 22: fsd_universe.Email.SendActivityAtcLeave:output_type -> fsd_universe.SendResponse
-	19, // 23: fsd_universe.Email.SendActivityPilotJoin:output_type -> fsd_universe.SendResponse
-	19, // 24: fsd_universe.Email.SendActivityPilotLeave:output_type -> fsd_universe.SendResponse
-	19, // 25: fsd_universe.Email.SendApplicationPassed:output_type -> fsd_universe.SendResponse
-	19, // 26: fsd_universe.Email.SendApplicationProcessing:output_type -> fsd_universe.SendResponse
-	19, // 27: fsd_universe.Email.SendApplicationRejected:output_type -> fsd_universe.SendResponse
-	19, // 28: fsd_universe.Email.SendAtcRatingChange:output_type -> fsd_universe.SendResponse
-	19, // 29: fsd_universe.Email.SendBanned:output_type -> fsd_universe.SendResponse
-	19, // 30: fsd_universe.Email.SendUnbanned:output_type -> fsd_universe.SendResponse
-	19, // 31: fsd_universe.Email.SendInstructorChange:output_type -> fsd_universe.SendResponse
-	19, // 32: fsd_universe.Email.SendKickedFromServer:output_type -> fsd_universe.SendResponse
-	19, // 33: fsd_universe.Email.SendPasswordChange:output_type -> fsd_universe.SendResponse
-	19, // 34: fsd_universe.Email.SendPasswordReset:output_type -> fsd_universe.SendResponse
-	19, // 35: fsd_universe.Email.SendPermissionChange:output_type -> fsd_universe.SendResponse
-	19, // 36: fsd_universe.Email.SendRoleChange:output_type -> fsd_universe.SendResponse
-	19, // 37: fsd_universe.Email.SendTicketReply:output_type -> fsd_universe.SendResponse
-	19, // 38: fsd_universe.Email.SendWelcome:output_type -> fsd_universe.SendResponse
-	19, // 39: fsd_universe.Email.SendEmailChange:output_type -> fsd_universe.SendResponse
-	21, // 40: fsd_universe.Email.VerifyEmailCode:output_type -> fsd_universe.VerifyResponse
-	23, // 41: fsd_universe.Email.RemoveEmailCode:output_type -> fsd_universe.RemoveVerifyCodeResponse
-	21, // [21:42] is the sub-list for method output_type
-	0,  // [0:21] is the sub-list for method input_type
+	19, // 19: fsd_universe.Email.SendEmailChangeConfirm:input_type -> fsd_universe.EmailChangeConfirm
+	20, // 20: fsd_universe.Email.SendEmailChangeRevert:input_type -> fsd_universe.EmailChangeRevert
+	21, // 21: fsd_universe.Email.SendAccountDeletion:input_type -> fsd_universe.AccountDeletion
+	23, // 22: fsd_universe.Email.VerifyEmailCode:input_type -> fsd_universe.VerifyCode
+	25, // 23: fsd_universe.Email.RemoveEmailCode:input_type -> fsd_universe.RemoveVerifyCode
+	22, // 24: fsd_universe.Email.SendActivityAtcJoin:output_type -> fsd_universe.SendResponse
+	22, // 25: fsd_universe.Email.SendActivityAtcLeave:output_type -> fsd_universe.SendResponse
+	22, // 26: fsd_universe.Email.SendActivityPilotJoin:output_type -> fsd_universe.SendResponse
+	22, // 27: fsd_universe.Email.SendActivityPilotLeave:output_type -> fsd_universe.SendResponse
+	22, // 28: fsd_universe.Email.SendApplicationPassed:output_type -> fsd_universe.SendResponse
+	22, // 29: fsd_universe.Email.SendApplicationProcessing:output_type -> fsd_universe.SendResponse
+	22, // 30: fsd_universe.Email.SendApplicationRejected:output_type -> fsd_universe.SendResponse
+	22, // 31: fsd_universe.Email.SendAtcRatingChange:output_type -> fsd_universe.SendResponse
+	22, // 32: fsd_universe.Email.SendBanned:output_type -> fsd_universe.SendResponse
+	22, // 33: fsd_universe.Email.SendUnbanned:output_type -> fsd_universe.SendResponse
+	22, // 34: fsd_universe.Email.SendInstructorChange:output_type -> fsd_universe.SendResponse
+	22, // 35: fsd_universe.Email.SendKickedFromServer:output_type -> fsd_universe.SendResponse
+	22, // 36: fsd_universe.Email.SendPasswordChange:output_type -> fsd_universe.SendResponse
+	22, // 37: fsd_universe.Email.SendPasswordReset:output_type -> fsd_universe.SendResponse
+	22, // 38: fsd_universe.Email.SendPermissionChange:output_type -> fsd_universe.SendResponse
+	22, // 39: fsd_universe.Email.SendRoleChange:output_type -> fsd_universe.SendResponse
+	22, // 40: fsd_universe.Email.SendTicketReply:output_type -> fsd_universe.SendResponse
+	22, // 41: fsd_universe.Email.SendWelcome:output_type -> fsd_universe.SendResponse
+	22, // 42: fsd_universe.Email.SendEmailChange:output_type -> fsd_universe.SendResponse
+	22, // 43: fsd_universe.Email.SendEmailChangeConfirm:output_type -> fsd_universe.SendResponse
+	22, // 44: fsd_universe.Email.SendEmailChangeRevert:output_type -> fsd_universe.SendResponse
+	22, // 45: fsd_universe.Email.SendAccountDeletion:output_type -> fsd_universe.SendResponse
+	24, // 46: fsd_universe.Email.VerifyEmailCode:output_type -> fsd_universe.VerifyResponse
+	26, // 47: fsd_universe.Email.RemoveEmailCode:output_type -> fsd_universe.RemoveVerifyCodeResponse
+	24, // [24:48] is the sub-list for method output_type
+	0,  // [0:24] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -1936,7 +2248,7 @@ func file_email_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_email_proto_rawDesc), len(file_email_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   24,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
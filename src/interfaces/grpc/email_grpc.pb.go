@@ -41,6 +41,9 @@ const (
 	Email_SendTicketReply_FullMethodName           = "/fsd_universe.Email/SendTicketReply"
 	Email_SendWelcome_FullMethodName               = "/fsd_universe.Email/SendWelcome"
 	Email_SendEmailChange_FullMethodName           = "/fsd_universe.Email/SendEmailChange"
+	Email_SendEmailChangeConfirm_FullMethodName    = "/fsd_universe.Email/SendEmailChangeConfirm"
+	Email_SendEmailChangeRevert_FullMethodName     = "/fsd_universe.Email/SendEmailChangeRevert"
+	Email_SendAccountDeletion_FullMethodName       = "/fsd_universe.Email/SendAccountDeletion"
 	Email_VerifyEmailCode_FullMethodName           = "/fsd_universe.Email/VerifyEmailCode"
 	Email_RemoveEmailCode_FullMethodName           = "/fsd_universe.Email/RemoveEmailCode"
 )
@@ -68,6 +71,9 @@ type EmailClient interface {
 	SendTicketReply(ctx context.Context, in *TicketReply, opts ...grpc.CallOption) (*SendResponse, error)
 	SendWelcome(ctx context.Context, in *Welcome, opts ...grpc.CallOption) (*SendResponse, error)
 	SendEmailChange(ctx context.Context, in *EmailChange, opts ...grpc.CallOption) (*SendResponse, error)
+	SendEmailChangeConfirm(ctx context.Context, in *EmailChangeConfirm, opts ...grpc.CallOption) (*SendResponse, error)
+	SendEmailChangeRevert(ctx context.Context, in *EmailChangeRevert, opts ...grpc.CallOption) (*SendResponse, error)
+	SendAccountDeletion(ctx context.Context, in *AccountDeletion, opts ...grpc.CallOption) (*SendResponse, error)
 	VerifyEmailCode(ctx context.Context, in *VerifyCode, opts ...grpc.CallOption) (*VerifyResponse, error)
 	RemoveEmailCode(ctx context.Context, in *RemoveVerifyCode, opts ...grpc.CallOption) (*RemoveVerifyCodeResponse, error)
 }
@@ -270,6 +276,36 @@ func (c *emailClient) SendEmailChange(ctx context.Context, in *EmailChange, opts
 	return out, nil
 }
 
+func (c *emailClient) SendEmailChangeConfirm(ctx context.Context, in *EmailChangeConfirm, opts ...grpc.CallOption) (*SendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendResponse)
+	err := c.cc.Invoke(ctx, Email_SendEmailChangeConfirm_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailClient) SendEmailChangeRevert(ctx context.Context, in *EmailChangeRevert, opts ...grpc.CallOption) (*SendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendResponse)
+	err := c.cc.Invoke(ctx, Email_SendEmailChangeRevert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailClient) SendAccountDeletion(ctx context.Context, in *AccountDeletion, opts ...grpc.CallOption) (*SendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendResponse)
+	err := c.cc.Invoke(ctx, Email_SendAccountDeletion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *emailClient) VerifyEmailCode(ctx context.Context, in *VerifyCode, opts ...grpc.CallOption) (*VerifyResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(VerifyResponse)
@@ -313,6 +349,9 @@ type EmailServer interface {
 	SendTicketReply(context.Context, *TicketReply) (*SendResponse, error)
 	SendWelcome(context.Context, *Welcome) (*SendResponse, error)
 	SendEmailChange(context.Context, *EmailChange) (*SendResponse, error)
+	SendEmailChangeConfirm(context.Context, *EmailChangeConfirm) (*SendResponse, error)
+	SendEmailChangeRevert(context.Context, *EmailChangeRevert) (*SendResponse, error)
+	SendAccountDeletion(context.Context, *AccountDeletion) (*SendResponse, error)
 	VerifyEmailCode(context.Context, *VerifyCode) (*VerifyResponse, error)
 	RemoveEmailCode(context.Context, *RemoveVerifyCode) (*RemoveVerifyCodeResponse, error)
 	mustEmbedUnimplementedEmailServer()
@@ -382,6 +421,15 @@ func (UnimplementedEmailServer) SendWelcome(context.Context, *Welcome) (*SendRes
 func (UnimplementedEmailServer) SendEmailChange(context.Context, *EmailChange) (*SendResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SendEmailChange not implemented")
 }
+func (UnimplementedEmailServer) SendEmailChangeConfirm(context.Context, *EmailChangeConfirm) (*SendResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SendEmailChangeConfirm not implemented")
+}
+func (UnimplementedEmailServer) SendEmailChangeRevert(context.Context, *EmailChangeRevert) (*SendResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SendEmailChangeRevert not implemented")
+}
+func (UnimplementedEmailServer) SendAccountDeletion(context.Context, *AccountDeletion) (*SendResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SendAccountDeletion not implemented")
+}
 func (UnimplementedEmailServer) VerifyEmailCode(context.Context, *VerifyCode) (*VerifyResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method VerifyEmailCode not implemented")
 }
@@ -751,6 +799,60 @@ func _Email_SendEmailChange_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Email_SendEmailChangeConfirm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailChangeConfirm)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServer).SendEmailChangeConfirm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Email_SendEmailChangeConfirm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServer).SendEmailChangeConfirm(ctx, req.(*EmailChangeConfirm))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Email_SendEmailChangeRevert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailChangeRevert)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServer).SendEmailChangeRevert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Email_SendEmailChangeRevert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServer).SendEmailChangeRevert(ctx, req.(*EmailChangeRevert))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Email_SendAccountDeletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountDeletion)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServer).SendAccountDeletion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Email_SendAccountDeletion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServer).SendAccountDeletion(ctx, req.(*AccountDeletion))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Email_VerifyEmailCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(VerifyCode)
 	if err := dec(in); err != nil {
@@ -870,6 +972,18 @@ var Email_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendEmailChange",
 			Handler:    _Email_SendEmailChange_Handler,
 		},
+		{
+			MethodName: "SendEmailChangeConfirm",
+			Handler:    _Email_SendEmailChangeConfirm_Handler,
+		},
+		{
+			MethodName: "SendEmailChangeRevert",
+			Handler:    _Email_SendEmailChangeRevert_Handler,
+		},
+		{
+			MethodName: "SendAccountDeletion",
+			Handler:    _Email_SendAccountDeletion_Handler,
+		},
 		{
 			MethodName: "VerifyEmailCode",
 			Handler:    _Email_VerifyEmailCode_Handler,
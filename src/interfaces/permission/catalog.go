@@ -0,0 +1,198 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package permission
+package permission
+
+import (
+	"maps"
+	"math/bits"
+	"slices"
+	"sort"
+	"strings"
+
+	"half-nothing.cn/service-core/permission"
+)
+
+const (
+	LangZhCN = "zh-CN"
+	LangEn   = "en"
+)
+
+// Languages 权限目录支持的语言, 第一个为默认语言
+var Languages = []string{LangZhCN, LangEn}
+
+const (
+	CategoryUser     = "user"
+	CategoryRole     = "role"
+	CategoryDivision = "division"
+	CategorySystem   = "system"
+)
+
+// NodeInfo 权限节点描述
+type NodeInfo struct {
+	Name     string
+	Node     permission.Permission
+	Category string
+	Names    map[string]string
+	Implies  []string
+}
+
+// Bit 权限节点所在的位
+func (n *NodeInfo) Bit() int {
+	return bits.TrailingZeros64(uint64(n.Node))
+}
+
+// nodeMetadata 权限节点的类别、多语言名称与隐含关系, 未列出的节点按名称前缀推断类别并以节点名称作为显示名称
+var nodeMetadata = map[string]*NodeInfo{
+	"UserShowList": {
+		Category: CategoryUser,
+		Names:    map[string]string{LangZhCN: "查看用户列表", LangEn: "View user list"},
+	},
+	"UserEditInfo": {
+		Category: CategoryUser,
+		Names:    map[string]string{LangZhCN: "编辑用户信息", LangEn: "Edit user information"},
+		Implies:  []string{"UserShowList"},
+	},
+	"UserBan": {
+		Category: CategoryUser,
+		Names:    map[string]string{LangZhCN: "封禁用户", LangEn: "Ban users"},
+		Implies:  []string{"UserShowList"},
+	},
+	"UserEditPermission": {
+		Category: CategoryUser,
+		Names:    map[string]string{LangZhCN: "编辑用户权限", LangEn: "Edit user permissions"},
+		Implies:  []string{"UserShowList"},
+	},
+	"UserEditRole": {
+		Category: CategoryUser,
+		Names:    map[string]string{LangZhCN: "编辑用户角色", LangEn: "Edit user roles"},
+		Implies:  []string{"UserShowList", "RoleShowList"},
+	},
+	"UserDelete": {
+		Category: CategoryUser,
+		Names:    map[string]string{LangZhCN: "删除用户", LangEn: "Delete users"},
+		Implies:  []string{"UserShowList"},
+	},
+	"ProfileFieldManage": {
+		Category: CategoryUser,
+		Names:    map[string]string{LangZhCN: "管理自定义资料字段", LangEn: "Manage custom profile fields"},
+	},
+	"UserEditRating": {
+		Category: CategoryUser,
+		Names:    map[string]string{LangZhCN: "修改管制员等级", LangEn: "Edit ATC ratings"},
+		Implies:  []string{"UserShowList"},
+	},
+	"InstructorAssign": {
+		Category: CategoryUser,
+		Names:    map[string]string{LangZhCN: "指派教员", LangEn: "Assign instructors"},
+		Implies:  []string{"UserShowList"},
+	},
+	"CidManage": {
+		Category: CategoryUser,
+		Names:    map[string]string{LangZhCN: "管理呼号分配", LangEn: "Manage CID allocation"},
+		Implies:  []string{"UserShowList"},
+	},
+	"RoleShowList": {
+		Category: CategoryRole,
+		Names:    map[string]string{LangZhCN: "查看角色列表", LangEn: "View role list"},
+	},
+	"RoleCreate": {
+		Category: CategoryRole,
+		Names:    map[string]string{LangZhCN: "创建角色", LangEn: "Create roles"},
+		Implies:  []string{"RoleShowList"},
+	},
+	"RoleEdit": {
+		Category: CategoryRole,
+		Names:    map[string]string{LangZhCN: "编辑角色", LangEn: "Edit roles"},
+		Implies:  []string{"RoleShowList"},
+	},
+	"RoleDelete": {
+		Category: CategoryRole,
+		Names:    map[string]string{LangZhCN: "删除角色", LangEn: "Delete roles"},
+		Implies:  []string{"RoleShowList"},
+	},
+	"RoleEditPermission": {
+		Category: CategoryRole,
+		Names:    map[string]string{LangZhCN: "编辑角色权限", LangEn: "Edit role permissions"},
+		Implies:  []string{"RoleShowList"},
+	},
+	"DivisionShowList": {
+		Category: CategoryDivision,
+		Names:    map[string]string{LangZhCN: "查看分区列表", LangEn: "View division list"},
+	},
+	"DivisionManage": {
+		Category: CategoryDivision,
+		Names:    map[string]string{LangZhCN: "管理分区", LangEn: "Manage divisions"},
+		Implies:  []string{"UserShowList", "DivisionShowList"},
+	},
+	"SuperAdmin": {
+		Category: CategorySystem,
+		Names:    map[string]string{LangZhCN: "超级管理员", LangEn: "Super administrator"},
+	},
+}
+
+// categoryOrder 权限目录中类别的排列顺序
+var categoryOrder = []string{CategoryUser, CategoryRole, CategoryDivision, CategorySystem}
+
+// Catalog 本服务使用的全部权限节点, 由 service-core 内置节点与扩展节点生成, 按类别与权限位排列
+var Catalog = buildCatalog()
+
+// newNodeInfo 生成权限节点描述, 缺少的元数据使用默认值补全
+func newNodeInfo(name string, node permission.Permission) *NodeInfo {
+	info := &NodeInfo{Name: name, Node: node, Names: make(map[string]string, len(Languages))}
+	if meta, ok := nodeMetadata[name]; ok {
+		info.Category = meta.Category
+		info.Implies = meta.Implies
+		maps.Copy(info.Names, meta.Names)
+	}
+	if info.Category == "" {
+		switch {
+		case strings.HasPrefix(name, "User"):
+			info.Category = CategoryUser
+		case strings.HasPrefix(name, "Role"):
+			info.Category = CategoryRole
+		case strings.HasPrefix(name, "Division"):
+			info.Category = CategoryDivision
+		default:
+			info.Category = CategorySystem
+		}
+	}
+	for _, lang := range Languages {
+		if info.Names[lang] == "" {
+			info.Names[lang] = name
+		}
+	}
+	return info
+}
+
+func buildCatalog() []*NodeInfo {
+	catalog := make([]*NodeInfo, 0, len(Nodes))
+	for _, enum := range permission.Permissions.GetEnums() {
+		catalog = append(catalog, newNodeInfo(enum.Value, enum.Data))
+	}
+	for name, node := range Nodes {
+		catalog = append(catalog, newNodeInfo(name, node))
+	}
+	sort.SliceStable(catalog, func(i, j int) bool {
+		ci, cj := slices.Index(categoryOrder, catalog[i].Category), slices.Index(categoryOrder, catalog[j].Category)
+		if ci != cj {
+			return ci < cj
+		}
+		return catalog[i].Bit() < catalog[j].Bit()
+	})
+	return catalog
+}
+
+// Decode 将权限位解码为节点名称, 返回无法识别的剩余权限位
+func Decode(value uint64) (names []string, unknown uint64) {
+	names = make([]string, 0)
+	unknown = value
+	for _, node := range Catalog {
+		if value&uint64(node.Node) == uint64(node.Node) {
+			names = append(names, node.Name)
+			unknown &^= uint64(node.Node)
+		}
+	}
+	return
+}
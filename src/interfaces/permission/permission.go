@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package permission 本服务扩展的权限节点
+package permission
+
+import (
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/permission"
+	"half-nothing.cn/service-core/utils"
+)
+
+// 扩展权限节点自最高位向低位分配, 避免与 service-core 内置节点冲突
+const (
+	// SuperAdmin 超级管理员, 允许修改自己的权限与角色
+	SuperAdmin permission.Permission = 1 << (63 - iota)
+	// DivisionManage 管理分区及分区成员
+	DivisionManage
+	// UserDelete 删除(注销)用户
+	UserDelete
+	// ProfileFieldManage 管理自定义资料字段
+	ProfileFieldManage
+	// UserEditRating 修改用户管制员等级
+	UserEditRating
+	// InstructorAssign 为学员指派教员
+	InstructorAssign
+	// CidManage 保留呼号及为用户指定呼号
+	CidManage
+	// DivisionShowList 查看分区列表
+	DivisionShowList
+)
+
+// Nodes 扩展权限节点名称到节点的映射
+var Nodes = map[string]permission.Permission{
+	"SuperAdmin":         SuperAdmin,
+	"DivisionManage":     DivisionManage,
+	"UserDelete":         UserDelete,
+	"ProfileFieldManage": ProfileFieldManage,
+	"UserEditRating":     UserEditRating,
+	"InstructorAssign":   InstructorAssign,
+	"CidManage":          CidManage,
+	"DivisionShowList":   DivisionShowList,
+}
+
+// GetNode 根据节点名称获取权限节点, 同时查找 service-core 内置节点与扩展节点
+func GetNode(name string) (permission.Permission, bool) {
+	if permission.Permissions.IsValidEnum(name) {
+		return permission.Permissions.GetEnum(name).Data, true
+	}
+	node, ok := Nodes[name]
+	return node, ok
+}
+
+// Effective 计算用户的有效权限, 即用户自身权限与所有角色权限的并集
+func Effective(user *entity.User) permission.Permission {
+	perm := permission.Permission(user.Permission)
+	utils.ForEach(user.Roles, func(index int, role *entity.UserRole) {
+		if role.Role != nil {
+			perm.Merge(permission.Permission(role.Role.Permission))
+		}
+	})
+	return perm
+}
+
+// Contains 判断 target 中的每一个权限位是否都包含在 owner 中
+func Contains(owner permission.Permission, target permission.Permission) bool {
+	return target&^owner == 0
+}
@@ -0,0 +1,22 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/database/repository"
+)
+
+type AvatarInterface interface {
+	repository.Base[*Entity.Avatar]
+	GetByUser(userId uint) ([]*Entity.Avatar, error)
+	GetByKey(key string) (*Entity.Avatar, error)
+	GetActiveByImage(userId uint, imageId uint) (*Entity.Avatar, error)
+	// Create 保存头像并设置为用户当前头像, maxHistory 大于 0 时删除超出数量的最早的头像记录并返回
+	Create(avatar *Entity.Avatar, url string, maxHistory int) ([]*Entity.Avatar, error)
+	SetCurrent(userId uint, imageId *uint) error
+	Remove(avatar *Entity.Avatar, operatorId uint, reason string) error
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"errors"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/database/repository"
+)
+
+var (
+	// ErrCidExhausted 可分配的呼号已用尽
+	ErrCidExhausted = errors.New("no cid available")
+	// ErrCidTaken 呼号已被其他用户使用或已被保留
+	ErrCidTaken = errors.New("cid has been taken")
+)
+
+type CidInterface interface {
+	repository.Base[*Entity.CidReservation]
+	GetReservations() ([]*Entity.CidReservation, error)
+	GetReservation(cid uint) (*Entity.CidReservation, error)
+	IsUsed(cid uint) (bool, error)
+	Release(cid uint) error
+	CreateUser(user *entity.User, canonicalEmail string, minCid uint, maxCid uint, allocatable func(cid uint) bool) error
+	AssignCid(userId uint, cid uint) error
+}
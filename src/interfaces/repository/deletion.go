@@ -0,0 +1,27 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"errors"
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/database/repository"
+)
+
+// ErrDeletionClosed 注销请求已被处理或已被取消
+var ErrDeletionClosed = errors.New("account deletion has been closed")
+
+type DeletionInterface interface {
+	repository.Base[*Entity.AccountDeletion]
+	GetLatest(userId uint) (*Entity.AccountDeletion, error)
+	GetByUser(userId uint) ([]*Entity.AccountDeletion, error)
+	GetDue(now time.Time, limit int) ([]*Entity.AccountDeletion, error)
+	Cancel(deletionId uint) error
+	// Complete 完成注销请求, 请求已不处于待处理状态时返回 ErrDeletionClosed,
+	// 多个实例同时处理同一请求时只有一个会成功
+	Complete(deletion *Entity.AccountDeletion, anonymized map[string]interface{}) error
+}
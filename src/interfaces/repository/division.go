@@ -0,0 +1,25 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/database/repository"
+)
+
+type DivisionInterface interface {
+	repository.Base[*Entity.Division]
+	GetPages(pageNum int, pageSize int, search string) ([]*Entity.Division, int64, error)
+	DeleteDivision(divisionId uint) error
+	CountMembers(divisionId uint) (int64, error)
+	GetUserDivisionIds(userId uint) ([]uint, error)
+	GetUserDivisions(userId uint) ([]*Entity.Division, error)
+	AddMembers(divisionId uint, userIds []uint) error
+	RemoveMembers(divisionId uint, userIds []uint) error
+	GetScopedRoles(userId uint) ([]*Entity.DivisionRole, error)
+	GrantScopedRole(divisionId uint, userId uint, roleIds []uint) error
+	RevokeScopedRole(divisionId uint, userId uint, roleIds []uint) error
+}
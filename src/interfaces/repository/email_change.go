@@ -0,0 +1,35 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"errors"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/database/repository"
+)
+
+var (
+	// ErrEmailChangeClosed 邮箱修改请求的状态已被其他请求改变
+	ErrEmailChangeClosed = errors.New("email change has been closed")
+	// ErrEmailChanged 用户当前邮箱与修改请求记录的邮箱不一致
+	ErrEmailChanged = errors.New("email has been changed")
+)
+
+type EmailChangeInterface interface {
+	repository.Base[*Entity.EmailChange]
+	GetPending(userId uint) (*Entity.EmailChange, error)
+	GetByUser(userId uint) ([]*Entity.EmailChange, error)
+	GetByConfirmToken(tokenHash string) (*Entity.EmailChange, error)
+	GetByRevertToken(tokenHash string) (*Entity.EmailChange, error)
+	// Request 保存新的邮箱修改请求, 同时取消该用户其他尚未确认的请求
+	Request(change *Entity.EmailChange) error
+	// Confirm 将用户邮箱修改为新邮箱并更新其规范化形式, 新邮箱已被使用时返回 ErrDuplicated
+	Confirm(change *Entity.EmailChange, canonicalEmail string) error
+	// Cancel 取消尚未确认的请求, revokeSessions 为 true 时同时使该用户的所有会话失效
+	Cancel(change *Entity.EmailChange, revokeSessions bool) error
+	// Revert 将用户邮箱恢复为原邮箱并更新其规范化形式, 同时使该用户的所有会话失效, 原邮箱已被使用时返回 ErrDuplicated
+	Revert(change *Entity.EmailChange, canonicalEmail string) error
+}
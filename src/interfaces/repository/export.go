@@ -0,0 +1,21 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/database/repository"
+)
+
+type ExportInterface interface {
+	repository.Base[*Entity.DataExport]
+	GetLatest(userId uint) (*Entity.DataExport, error)
+	GetWithData(exportId uint) (*Entity.DataExport, error)
+	Complete(exportId uint, data []byte, expiresAt time.Time) error
+	PurgeExpired(now time.Time) (int64, error)
+	Fail(exportId uint) error
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"errors"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/database/repository"
+)
+
+// ErrInstructorFull 教员指导的学员数量已达到上限
+var ErrInstructorFull = errors.New("instructor has reached max trainees")
+
+type InstructorInterface interface {
+	repository.Base[*Entity.InstructorAssignment]
+	GetCurrent(traineeId uint) (*Entity.InstructorAssignment, error)
+	GetByTrainee(traineeId uint) ([]*Entity.InstructorAssignment, error)
+	GetTrainees(instructorId uint) ([]*Entity.InstructorAssignment, error)
+	// Assign 结束学员当前的指派并创建新的指派, maxTrainees 大于 0 时教员指导的学员已达到上限则返回 ErrInstructorFull
+	Assign(assignment *Entity.InstructorAssignment, maxTrainees int) error
+	Unassign(assignment *Entity.InstructorAssignment, operatorId uint, reason string) error
+}
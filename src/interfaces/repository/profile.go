@@ -0,0 +1,17 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/database/repository"
+)
+
+type ProfileInterface interface {
+	repository.Base[*Entity.UserProfile]
+	GetByUserId(userId uint) (*Entity.UserProfile, error)
+	GetByUserIds(userIds []uint) (map[uint]*Entity.UserProfile, error)
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"errors"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/database/repository"
+)
+
+// ErrFieldValuesMismatch 已有的字段值不符合新的字段定义
+var ErrFieldValuesMismatch = errors.New("profile field values mismatch")
+
+type ProfileFieldInterface interface {
+	repository.Base[*Entity.ProfileField]
+	GetAll() ([]*Entity.ProfileField, error)
+	GetByKey(key string) (*Entity.ProfileField, error)
+	// UpdateField 保存字段定义, check 不为 nil 时在同一事务中校验所有已有的字段值, 存在不合法的值时返回 ErrFieldValuesMismatch
+	UpdateField(field *Entity.ProfileField, check func(value string) bool) error
+	DeleteField(fieldId uint) error
+}
@@ -0,0 +1,22 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"errors"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/database/repository"
+)
+
+// ErrRatingChanged 修改等级时用户当前等级已被其他操作修改
+var ErrRatingChanged = errors.New("user rating has been changed")
+
+type RatingInterface interface {
+	repository.Base[*Entity.RatingChange]
+	GetByUser(userId uint) ([]*Entity.RatingChange, error)
+	GetLatest(userId uint) (*Entity.RatingChange, error)
+	ChangeRating(change *Entity.RatingChange) error
+}
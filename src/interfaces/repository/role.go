@@ -9,13 +9,24 @@ import (
 	"half-nothing.cn/service-core/interfaces/database/repository"
 )
 
+// RoleImport 导入角色时需要写入的角色, Members 为 nil 时不修改角色成员
+type RoleImport struct {
+	Role    *entity.Role
+	Members []uint
+}
+
 type RoleInterface interface {
 	repository.Base[*entity.Role]
 	GetPages(pageNum int, pageSize int, search string) ([]*entity.Role, int64, error)
 	SetPermission(roleId uint, permission uint64) error
 	GetRoleUsers(roleId uint) ([]*entity.UserRole, error)
-	DeleteRole(roleId uint) error
+	CountRoleUsers(roleId uint) (int64, error)
+	DeleteRole(roleId uint, replacementId uint) error
 	GetByIds(roleIds []uint) ([]*entity.Role, error)
 	GrantUser(roleId uint, userId []uint) error
 	RevokeUser(roleId uint, userId []uint) error
+	SyncUsers(roleId uint, addIds []uint, removeIds []uint) error
+	GetAll() ([]*entity.Role, error)
+	GetAllUserRoles() ([]*entity.UserRole, error)
+	ImportRoles(upserts []*RoleImport, removeIds []uint) error
 }
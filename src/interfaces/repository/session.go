@@ -0,0 +1,22 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/database/repository"
+)
+
+type SessionInterface interface {
+	repository.Base[*Entity.SessionRevocation]
+	// GetRevokedAt 获取用户会话最近一次失效的时间, 从未失效时返回零值
+	GetRevokedAt(userId uint) (time.Time, error)
+	Revoke(userId uint) error
+	IsPasswordChangeRequired(userId uint) (bool, error)
+	// RequirePasswordChange 要求用户修改密码, 同时使该用户的所有会话失效
+	ClearPasswordChange(userId uint) error
+}
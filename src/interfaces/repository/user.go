@@ -3,6 +3,8 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
+	Entity "user-service/src/interfaces/database/entity"
 
 	"half-nothing.cn/service-core/interfaces/database/entity"
 	"half-nothing.cn/service-core/interfaces/database/repository"
@@ -32,15 +34,62 @@ func (id StringUserId) GetUser(userRepo UserInterface) (*entity.User, error) {
 	return userRepo.GetByUsernameOrEmail(string(id))
 }
 
+// ErrDuplicated 写入时违反唯一约束
+var ErrDuplicated = errors.New("duplicated entry")
+
+// UserConflict 与已有用户重复的字段
+type UserConflict int
+
+const (
+	UserConflictNone UserConflict = iota
+	UserConflictCid
+	UserConflictUsername
+	UserConflictEmail
+)
+
+// FieldFilter 按自定义资料字段值筛选用户
+type FieldFilter struct {
+	FieldId uint
+	Value   string
+}
+
+// UserUpdate 需要在同一事务中写入的用户信息与扩展资料修改
+type UserUpdate struct {
+	// Updates 用户信息的修改
+	Updates map[string]interface{}
+	// CanonicalEmail 不为空时同时更新邮箱的规范化形式
+	CanonicalEmail string
+	// Profile 扩展资料的修改
+	Profile map[string]interface{}
+	// FieldValues 自定义字段 ID 到字段值的映射, 值为空字符串时删除该字段值
+	FieldValues map[uint]string
+	// UsernameChange 不为空时同时记录用户名修改并更新用户名骨架
+	UsernameChange *Entity.UsernameChange
+	// PasswordChange 不为空时同时要求用户修改密码, 并使用户已签发的会话全部失效
+	PasswordChange *Entity.PasswordChangeRequirement
+	// RevokeSessions 为 true 时使用户已签发的会话全部失效
+	RevokeSessions bool
+}
+
 type UserInterface interface {
 	repository.Base[*entity.User]
 	GetByCid(id uint) (*entity.User, error)
 	GetByUsernameOrEmail(usernameOrEmail string) (*entity.User, error)
 	CheckCidUsernameAndEmail(cid uint, username string, email string) (bool, error)
-	GetPages(pageNum int, pageSize int, search string) ([]*entity.User, int64, error)
+	FindConflict(cid uint, username string, email string) (UserConflict, error)
+	// IsEmailUsed 判断规范化后的邮箱是否已被 excludeUserId 以外的用户使用
+	IsEmailUsed(canonical string, excludeUserId uint) (bool, error)
+	// UpdateWithProfile 在同一事务中修改用户信息与扩展资料, 违反唯一约束时返回 ErrDuplicated
+	UpdateWithProfile(user *entity.User, update *UserUpdate) error
+	GetPages(pageNum int, pageSize int, search string, divisionIds []uint, fieldFilter *FieldFilter) ([]*entity.User, int64, error)
 	GrantRole(userId uint, roleIds []uint) error
 	RevokeRole(userId uint, roleIds []uint) error
 	GetByIds(userIds []uint) ([]*entity.User, error)
+	GetByCids(cids []uint) ([]*entity.User, error)
+	// GetByIdsOrCids 按用户 ID 或呼号批量获取用户, 已注销的用户不在结果中
+	GetByIdsOrCids(userIds []uint, cids []uint) ([]*entity.User, error)
+	GetByRole(roleId uint) ([]*entity.User, error)
+	GetRoleUserPages(roleId uint, pageNum int, pageSize int, search string) ([]*entity.User, int64, error)
 	Ban(userId uint, time sql.NullTime) error
 	Unban(userId uint) error
 }
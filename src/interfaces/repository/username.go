@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"strings"
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/database/repository"
+)
+
+// UsernameConfusables 易混淆字符的替换规则, 按顺序依次替换
+//
+// 用户名只允许 ASCII 字母、数字、下划线和连字符, 因此只需要处理这些字符之间的混淆
+var UsernameConfusables = [][2]string{
+	{"_", ""},
+	{"-", ""},
+	{"rn", "m"},
+	{"vv", "w"},
+	{"0", "o"},
+	{"1", "l"},
+	{"i", "l"},
+	{"3", "e"},
+	{"4", "a"},
+	{"5", "s"},
+	{"7", "t"},
+	{"8", "b"},
+}
+
+// UsernameSkeleton 计算用户名的骨架, 骨架相同的用户名在视觉上容易混淆
+func UsernameSkeleton(username string) string {
+	skeleton := strings.ToLower(username)
+	for _, pair := range UsernameConfusables {
+		skeleton = strings.ReplaceAll(skeleton, pair[0], pair[1])
+	}
+	return skeleton
+}
+
+type UsernameInterface interface {
+	repository.Base[*Entity.UsernameChange]
+	GetByUser(userId uint) ([]*Entity.UsernameChange, error)
+	// GetLatestSelfService 获取用户最近一次自行修改用户名的记录
+	GetLatestSelfService(userId uint) (*Entity.UsernameChange, error)
+	// IsReserved 判断骨架是否与其他用户仍在保留期内的旧用户名相同
+	IsReserved(skeleton string, userId uint, now time.Time) (bool, error)
+	// HasSimilar 判断是否存在用户名骨架相同的其他用户
+	HasSimilar(skeleton string, userId uint) (bool, error)
+	// ContainsStaff 判断骨架是否包含其他管理人员的用户名骨架
+	ContainsStaff(skeleton string, userId uint) (bool, error)
+}
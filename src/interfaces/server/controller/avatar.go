@@ -0,0 +1,18 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import "github.com/labstack/echo/v4"
+
+type AvatarInterface interface {
+	Upload(ctx echo.Context) error
+	GetSelfAvatars(ctx echo.Context) error
+	SelectAvatar(ctx echo.Context) error
+	ClearAvatar(ctx echo.Context) error
+	GetUserAvatars(ctx echo.Context) error
+	RemoveAvatar(ctx echo.Context) error
+	GetFile(ctx echo.Context) error
+	GetIdenticon(ctx echo.Context) error
+}
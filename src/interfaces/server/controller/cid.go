@@ -0,0 +1,14 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import "github.com/labstack/echo/v4"
+
+type CidInterface interface {
+	GetReservations(ctx echo.Context) error
+	Reserve(ctx echo.Context) error
+	Release(ctx echo.Context) error
+	AssignCid(ctx echo.Context) error
+}
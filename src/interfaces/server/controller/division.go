@@ -0,0 +1,18 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import "github.com/labstack/echo/v4"
+
+type DivisionInterface interface {
+	GetPages(ctx echo.Context) error
+	GetById(ctx echo.Context) error
+	Create(ctx echo.Context) error
+	Update(ctx echo.Context) error
+	Delete(ctx echo.Context) error
+	AddMembers(ctx echo.Context) error
+	RemoveMembers(ctx echo.Context) error
+	GetUserDivisions(ctx echo.Context) error
+}
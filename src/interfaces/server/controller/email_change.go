@@ -0,0 +1,14 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import "github.com/labstack/echo/v4"
+
+type EmailChangeInterface interface {
+	GetEmailChange(ctx echo.Context) error
+	CancelEmailChange(ctx echo.Context) error
+	ConfirmEmailChange(ctx echo.Context) error
+	RevertEmailChange(ctx echo.Context) error
+}
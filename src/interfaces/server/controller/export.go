@@ -0,0 +1,13 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import "github.com/labstack/echo/v4"
+
+type DataExportInterface interface {
+	RequestExport(ctx echo.Context) error
+	GetExport(ctx echo.Context) error
+	Download(ctx echo.Context) error
+}
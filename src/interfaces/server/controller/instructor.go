@@ -0,0 +1,15 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import "github.com/labstack/echo/v4"
+
+type InstructorInterface interface {
+	GetSelfInstructor(ctx echo.Context) error
+	GetSelfTrainees(ctx echo.Context) error
+	GetUserInstructor(ctx echo.Context) error
+	AssignInstructor(ctx echo.Context) error
+	UnassignInstructor(ctx echo.Context) error
+}
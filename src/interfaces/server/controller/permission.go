@@ -13,4 +13,9 @@ type PermissionInterface interface {
 	RevokeUserRole(ctx echo.Context) error
 	GrantRoleUser(ctx echo.Context) error
 	RevokeRoleUser(ctx echo.Context) error
+	SetRoleUsers(ctx echo.Context) error
+	GrantScopedRole(ctx echo.Context) error
+	RevokeScopedRole(ctx echo.Context) error
+	GetCatalog(ctx echo.Context) error
+	DecodePermission(ctx echo.Context) error
 }
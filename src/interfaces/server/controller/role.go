@@ -9,7 +9,11 @@ import "github.com/labstack/echo/v4"
 type RoleInterface interface {
 	GetPages(ctx echo.Context) error
 	GetById(ctx echo.Context) error
+	GetUsers(ctx echo.Context) error
 	Create(ctx echo.Context) error
 	Update(ctx echo.Context) error
 	Delete(ctx echo.Context) error
+	GetDeletionImpact(ctx echo.Context) error
+	Export(ctx echo.Context) error
+	Import(ctx echo.Context) error
 }
@@ -0,0 +1,14 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import "github.com/labstack/echo/v4"
+
+type SessionInterface interface {
+	// RequireSession 拒绝会话已失效或被要求修改密码的访问令牌, 需要放在 JWT 中间件之后
+	RequireSession(next echo.HandlerFunc) echo.HandlerFunc
+	// RequirePasswordSession 用于修改密码接口, 与 RequireSession 相同但允许被要求修改密码的用户访问
+	RequirePasswordSession(next echo.HandlerFunc) echo.HandlerFunc
+}
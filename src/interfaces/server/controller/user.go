@@ -18,4 +18,10 @@ type UserInterface interface {
 	UpdatePassword(ctx echo.Context) error
 	Ban(ctx echo.Context) error
 	Unban(ctx echo.Context) error
+	RequestDeletion(ctx echo.Context) error
+	GetDeletion(ctx echo.Context) error
+	CancelDeletion(ctx echo.Context) error
+	Delete(ctx echo.Context) error
+	GetPublicProfile(ctx echo.Context) error
+	BatchGetPublicProfile(ctx echo.Context) error
 }
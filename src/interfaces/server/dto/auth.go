@@ -11,8 +11,9 @@ import (
 
 type UserLogin struct {
 	dto.HttpContent
-	Username string `json:"username" valid:"required,max=128"`
-	Password string `json:"password" valid:"required"`
+	Username  string `json:"username" valid:"required,max=128"`
+	Password  string `json:"password" valid:"required"`
+	Challenge string `json:"challenge"`
 }
 
 type UserLoginResponse struct {
@@ -20,6 +21,8 @@ type UserLoginResponse struct {
 	Token        string    `json:"token"`
 	ExpiresIn    int       `json:"expires_in"`
 	RefreshToken string    `json:"refresh_token"`
+	// PasswordChangeRequired 管理员要求用户修改密码, 修改密码前其他需要登录的接口均返回 PASSWORD_CHANGE_REQUIRED
+	PasswordChangeRequired bool `json:"password_change_required"`
 }
 
 type UserFsdLogin struct {
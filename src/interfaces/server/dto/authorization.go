@@ -0,0 +1,40 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package dto
+package dto
+
+import (
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+)
+
+type AuthorizationRequest struct {
+	SubjectId    uint     `json:"subject_id"`
+	Permissions  []string `json:"permissions"`
+	TargetUserId uint     `json:"target_user_id"`
+	Resource     string   `json:"resource"`
+}
+
+type AuthorizationDecision struct {
+	Allowed    bool     `json:"allowed"`
+	Reason     string   `json:"reason"`
+	Missing    []string `json:"missing"`
+	DivisionId uint     `json:"division_id"`
+}
+
+type CheckPermission struct {
+	dto.HttpContent
+	jwt.Content
+	AuthorizationRequest
+}
+
+type BatchCheckPermission struct {
+	dto.HttpContent
+	jwt.Content
+	Requests []*AuthorizationRequest `json:"requests"`
+}
+
+type BatchCheckPermissionResponse struct {
+	Decisions []*AuthorizationDecision `json:"decisions"`
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package dto
+package dto
+
+import (
+	"time"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+)
+
+type AvatarInfo struct {
+	Id            uint              `json:"id"`
+	Url           string            `json:"url"`
+	Sizes         map[string]string `json:"sizes"`
+	Current       bool              `json:"current"`
+	Status        string            `json:"status"`
+	RemovedReason string            `json:"removed_reason,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+type AvatarList struct {
+	Avatars []*AvatarInfo `json:"avatars"`
+}
+
+// UploadAvatar 上传头像, 图片内容由控制器从 multipart 表单的 file 字段读取
+type UploadAvatar struct {
+	dto.HttpContent
+	jwt.Content
+	Data []byte `json:"-"`
+}
+
+type GetSelfAvatars struct {
+	dto.HttpContent
+	jwt.Content
+}
+
+type SelectAvatar struct {
+	dto.HttpContent
+	jwt.Content
+	Id uint `param:"id" valid:"required,min=0;exclude"`
+}
+
+type ClearAvatar struct {
+	dto.HttpContent
+	jwt.Content
+}
+
+type GetUserAvatars struct {
+	dto.HttpContent
+	jwt.Content
+	Id uint `param:"id" valid:"required,min=0;exclude"`
+}
+
+type RemoveAvatar struct {
+	dto.HttpContent
+	jwt.Content
+	Id       uint   `param:"id" valid:"required,min=0;exclude"`
+	AvatarId uint   `param:"avatar_id" valid:"required,min=0;exclude"`
+	Reason   string `json:"reason" valid:"required,max=255"`
+}
+
+type GetAvatarFile struct {
+	File string `param:"file" valid:"required,max=64"`
+}
+
+type GetIdenticon struct {
+	Cid uint `param:"cid" valid:"required,min=0;exclude"`
+}
+
+// AvatarFile 头像图片内容
+type AvatarFile struct {
+	ContentType string
+	Content     []byte
+}
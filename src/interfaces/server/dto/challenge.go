@@ -0,0 +1,29 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package dto
+package dto
+
+import (
+	"time"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+)
+
+type GetChallenge struct {
+	dto.HttpContent
+	Endpoint string `param:"endpoint" valid:"required,max=32"`
+}
+
+// ChallengeInfo 接口当前是否需要人机验证及前端完成验证所需的信息
+//
+// 使用工作量证明时返回挑战, 客户端需找到 nonce 使 sha256(challenge + ":" + nonce) 的前导零比特数不少于 difficulty,
+// 并以 "challenge:nonce" 作为令牌提交
+type ChallengeInfo struct {
+	Required   bool       `json:"required"`
+	Provider   string     `json:"provider"`
+	SiteKey    string     `json:"site_key,omitempty"`
+	Challenge  string     `json:"challenge,omitempty"`
+	Difficulty int        `json:"difficulty,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package dto
+package dto
+
+import (
+	"time"
+	c "user-service/src/interfaces/config"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+)
+
+type CidReservationInfo struct {
+	Cid        uint      `json:"cid"`
+	Comment    string    `json:"comment"`
+	ReservedBy uint      `json:"reserved_by"`
+	UserId     *uint     `json:"user_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (info *CidReservationInfo) FromEntity(reservation *Entity.CidReservation) *CidReservationInfo {
+	info.Cid = reservation.Cid
+	info.Comment = reservation.Comment
+	info.ReservedBy = reservation.ReservedBy
+	info.UserId = reservation.UserId
+	info.CreatedAt = reservation.CreatedAt
+	return info
+}
+
+type CidRangeInfo struct {
+	Start   uint   `json:"start"`
+	End     uint   `json:"end"`
+	Comment string `json:"comment"`
+}
+
+// CidAllocationInfo 呼号分配规则与管理员保留的呼号
+type CidAllocationInfo struct {
+	Min           uint                  `json:"min"`
+	Max           uint                  `json:"max"`
+	ReservedRange []*CidRangeInfo       `json:"reserved_ranges"`
+	Blocked       []uint                `json:"blocked"`
+	Reservations  []*CidReservationInfo `json:"reservations"`
+}
+
+func (info *CidAllocationInfo) FromConfig(config *c.CidConfig) *CidAllocationInfo {
+	info.Min = config.Min
+	info.Max = config.Max
+	info.ReservedRange = make([]*CidRangeInfo, len(config.Reserved))
+	for index, r := range config.Reserved {
+		info.ReservedRange[index] = &CidRangeInfo{Start: r.Start, End: r.End, Comment: r.Comment}
+	}
+	info.Blocked = config.Blocked
+	return info
+}
+
+type GetCidReservations struct {
+	dto.HttpContent
+	jwt.Content
+}
+
+type ReserveCid struct {
+	dto.HttpContent
+	jwt.Content
+	TargetCid uint   `json:"cid" valid:"required,min=0;exclude"`
+	Comment   string `json:"comment" valid:"required,max=255"`
+}
+
+type ReleaseCid struct {
+	dto.HttpContent
+	jwt.Content
+	TargetCid uint `param:"cid" valid:"required,min=0;exclude"`
+}
+
+type AssignCid struct {
+	dto.HttpContent
+	jwt.Content
+	Id        uint   `param:"id" valid:"required,min=0;exclude"`
+	TargetCid uint   `json:"cid" valid:"required,min=0;exclude"`
+	Reason    string `json:"reason" valid:"required,max=255"`
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package dto
+package dto
+
+import (
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+)
+
+type DivisionInfo struct {
+	Id          uint   `json:"id"`
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (d *DivisionInfo) FromDivisionEntity(division *Entity.Division) *DivisionInfo {
+	d.Id = division.ID
+	d.Code = division.Code
+	d.Name = division.Name
+	d.Description = division.Comment
+	return d
+}
+
+type GetDivisionPage struct {
+	dto.HttpContent
+	jwt.Content
+	PageNum  int    `query:"page_num" valid:"required,min=0;exclude"`
+	PageSize int    `query:"page_size" valid:"required,min=0;exclude"`
+	Search   string `query:"search"`
+}
+
+type GetDivisionPageResponse struct {
+	Data     []*DivisionInfo `json:"page_data"`
+	Total    int             `json:"total"`
+	PageNum  int             `json:"page_num"`
+	PageSize int             `json:"page_size"`
+}
+
+type GetDivisionDetail struct {
+	dto.HttpContent
+	jwt.Content
+	Id uint `param:"id" valid:"required,min=0;exclude"`
+}
+
+type CreateDivision struct {
+	dto.HttpContent
+	jwt.Content
+	Code        string `json:"code" valid:"required,max=32,regex=^[A-Za-z0-9_-]+$"`
+	Name        string `json:"name" valid:"required,max=64"`
+	Description string `json:"description" valid:"max=255"`
+}
+
+type UpdateDivision struct {
+	dto.HttpContent
+	jwt.Content
+	Id          uint   `param:"id" valid:"required,min=0;exclude"`
+	Name        string `json:"name" valid:"max=64"`
+	Description string `json:"description" valid:"max=255"`
+}
+
+type DeleteDivision struct {
+	dto.HttpContent
+	jwt.Content
+	Id    uint `param:"id" valid:"required,min=0;exclude"`
+	Force bool `query:"force"`
+}
+
+type EditDivisionMember struct {
+	dto.HttpContent
+	jwt.Content
+	Id      uint   `param:"id" valid:"required,min=0;exclude"`
+	UserIds []uint `json:"ids" valid:"required,min=0;exclude"`
+}
+
+type GetUserDivisions struct {
+	dto.HttpContent
+	jwt.Content
+	Id uint `param:"id" valid:"required,min=0;exclude"`
+}
+
+type UserDivisionsResponse struct {
+	Divisions []*DivisionInfo `json:"divisions"`
+}
+
+type GrantScopedRole struct {
+	dto.HttpContent
+	jwt.Content
+	DivisionId uint   `param:"id" valid:"required,min=0;exclude"`
+	UserId     uint   `param:"user_id" valid:"required,min=0;exclude"`
+	RoleIds    []uint `json:"ids" valid:"required,min=0;exclude"`
+}
+
+type RevokeScopedRole struct {
+	dto.HttpContent
+	jwt.Content
+	DivisionId uint   `param:"id" valid:"required,min=0;exclude"`
+	UserId     uint   `param:"user_id" valid:"required,min=0;exclude"`
+	RoleIds    []uint `json:"ids" valid:"required,min=0;exclude"`
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package dto
+package dto
+
+import (
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+)
+
+type EmailChangeInfo struct {
+	Id        uint      `json:"id"`
+	NewEmail  string    `json:"new_email"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (info *EmailChangeInfo) FromEntity(change *Entity.EmailChange) *EmailChangeInfo {
+	info.Id = change.ID
+	info.NewEmail = change.NewEmail
+	info.Status = change.Status
+	info.ExpiresAt = change.ExpiresAt
+	info.CreatedAt = change.CreatedAt
+	return info
+}
+
+type GetEmailChange struct {
+	dto.HttpContent
+	jwt.Content
+}
+
+type CancelEmailChange struct {
+	dto.HttpContent
+	jwt.Content
+}
+
+// ConfirmEmailChange 新邮箱收到的确认链接中的令牌
+type ConfirmEmailChange struct {
+	dto.HttpContent
+	Token string `json:"token" valid:"required,max=128"`
+}
+
+// RevertEmailChange 原邮箱收到的撤销链接中的令牌
+type RevertEmailChange struct {
+	dto.HttpContent
+	Token string `json:"token" valid:"required,max=128"`
+}
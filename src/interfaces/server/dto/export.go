@@ -0,0 +1,170 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package dto
+package dto
+
+import (
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+)
+
+type RequestDataExport struct {
+	dto.HttpContent
+	jwt.Content
+	Format string `json:"format"`
+}
+
+type GetDataExport struct {
+	dto.HttpContent
+	jwt.Content
+}
+
+type DownloadDataExport struct {
+	dto.HttpContent
+	jwt.Content
+}
+
+type DataExportInfo struct {
+	Id          uint       `json:"id"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	RequestedAt time.Time  `json:"requested_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+func (d *DataExportInfo) FromEntity(export *Entity.DataExport) *DataExportInfo {
+	d.Id = export.ID
+	d.Format = export.Format
+	d.Status = export.Status
+	d.RequestedAt = export.CreatedAt
+	if export.CompletedAt.Valid {
+		d.CompletedAt = &export.CompletedAt.Time
+	}
+	if export.ExpiresAt.Valid {
+		d.ExpiresAt = &export.ExpiresAt.Time
+	}
+	return d
+}
+
+// DataExportFile 可供下载的导出文件
+type DataExportFile struct {
+	Name    string
+	Format  string
+	Content []byte
+}
+
+type LoginRecord struct {
+	Time *time.Time `json:"time"`
+	Ip   *string    `json:"ip"`
+}
+
+type SanctionRecord struct {
+	Banned      bool       `json:"banned"`
+	BannedUntil *time.Time `json:"banned_until"`
+}
+
+type ScopedRoleInfo struct {
+	Division *DivisionInfo `json:"division"`
+	Role     *BaseRoleInfo `json:"role"`
+}
+
+// CustomFieldRecord 用户填写的自定义资料字段值, 不区分字段的可见范围
+type CustomFieldRecord struct {
+	Key       string    `json:"key"`
+	Name      string    `json:"name"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (r *CustomFieldRecord) FromEntity(value *Entity.UserFieldValue) *CustomFieldRecord {
+	if value.Field != nil {
+		r.Key = value.Field.Key
+		r.Name = value.Field.Name
+	}
+	r.Value = value.Value
+	r.UpdatedAt = value.UpdatedAt
+	return r
+}
+
+// EmailChangeRecord 邮箱修改请求的完整记录
+type EmailChangeRecord struct {
+	EmailChangeInfo
+	OldEmail    string     `json:"old_email"`
+	Ip          string     `json:"ip"`
+	UserAgent   string     `json:"user_agent"`
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+}
+
+func (r *EmailChangeRecord) FromEntity(change *Entity.EmailChange) *EmailChangeRecord {
+	r.EmailChangeInfo.FromEntity(change)
+	r.OldEmail = change.OldEmail
+	r.Ip = change.Ip
+	r.UserAgent = change.UserAgent
+	if change.ConfirmedAt.Valid {
+		r.ConfirmedAt = &change.ConfirmedAt.Time
+	}
+	if change.ClosedAt.Valid {
+		r.ClosedAt = &change.ClosedAt.Time
+	}
+	return r
+}
+
+// Counterparty 导出数据中涉及的其他用户, 只包含呼号与显示名称
+type Counterparty struct {
+	Cid         uint   `json:"cid"`
+	DisplayName string `json:"display_name"`
+}
+
+// AssignmentRecord 导出数据中的教员指派记录, 不包含对方的联系方式与内部编号
+type AssignmentRecord struct {
+	Id         uint          `json:"id"`
+	Trainee    *Counterparty `json:"trainee,omitempty"`
+	Instructor *Counterparty `json:"instructor,omitempty"`
+	Reason     string        `json:"reason"`
+	EndReason  string        `json:"end_reason,omitempty"`
+	EndedAt    *time.Time    `json:"ended_at,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+func (r *AssignmentRecord) FromEntity(assignment *Entity.InstructorAssignment) *AssignmentRecord {
+	r.Id = assignment.ID
+	r.Reason = assignment.Reason
+	r.EndReason = assignment.EndReason
+	if assignment.EndedAt.Valid {
+		r.EndedAt = &assignment.EndedAt.Time
+	}
+	r.CreatedAt = assignment.CreatedAt
+	return r
+}
+
+// AssignmentHistory 导出数据中学员当前的教员与指派历史
+type AssignmentHistory struct {
+	Current *AssignmentRecord   `json:"current"`
+	History []*AssignmentRecord `json:"history"`
+}
+
+// PersonalData 本服务保存的某个用户的全部个人数据
+type PersonalData struct {
+	ExportedAt           time.Time              `json:"exported_at"`
+	Profile              *FullUserInfo          `json:"profile"`
+	CustomFields         []*CustomFieldRecord   `json:"custom_fields"`
+	Permissions          []string               `json:"permissions"`
+	EffectivePermissions []string               `json:"effective_permissions"`
+	Divisions            []*DivisionInfo        `json:"divisions"`
+	ScopedRoles          []*ScopedRoleInfo      `json:"scoped_roles"`
+	Avatars              []*AvatarInfo          `json:"avatars"`
+	Rating               *RatingInfo            `json:"rating"`
+	Instructors          *AssignmentHistory     `json:"instructors"`
+	Trainees             []*AssignmentRecord    `json:"trainees"`
+	UsernameHistory      []*UsernameChangeInfo  `json:"username_history"`
+	EmailChanges         []*EmailChangeRecord   `json:"email_changes"`
+	LoginHistory         []*LoginRecord         `json:"login_history"`
+	Sanctions            []*SanctionRecord      `json:"sanctions"`
+	AccountDeletions     []*AccountDeletionInfo `json:"account_deletions"`
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package dto
+package dto
+
+import (
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+)
+
+type InstructorAssignmentInfo struct {
+	Id           uint          `json:"id"`
+	TraineeId    uint          `json:"trainee_id"`
+	InstructorId uint          `json:"instructor_id"`
+	Trainee      *BaseUserInfo `json:"trainee,omitempty"`
+	Instructor   *BaseUserInfo `json:"instructor,omitempty"`
+	AssignedBy   uint          `json:"assigned_by"`
+	Reason       string        `json:"reason"`
+	EndedBy      *uint         `json:"ended_by,omitempty"`
+	EndReason    string        `json:"end_reason,omitempty"`
+	EndedAt      *time.Time    `json:"ended_at,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+func (info *InstructorAssignmentInfo) FromEntity(assignment *Entity.InstructorAssignment) *InstructorAssignmentInfo {
+	info.Id = assignment.ID
+	info.TraineeId = assignment.TraineeId
+	info.InstructorId = assignment.InstructorId
+	info.AssignedBy = assignment.AssignedBy
+	info.Reason = assignment.Reason
+	info.EndedBy = assignment.EndedBy
+	info.EndReason = assignment.EndReason
+	if assignment.EndedAt.Valid {
+		info.EndedAt = &assignment.EndedAt.Time
+	}
+	info.CreatedAt = assignment.CreatedAt
+	return info
+}
+
+// InstructorHistory 学员当前的教员与指派历史
+type InstructorHistory struct {
+	Current *InstructorAssignmentInfo   `json:"current"`
+	History []*InstructorAssignmentInfo `json:"history"`
+}
+
+type TraineeList struct {
+	Trainees []*InstructorAssignmentInfo `json:"trainees"`
+}
+
+type GetSelfInstructor struct {
+	dto.HttpContent
+	jwt.Content
+}
+
+type GetSelfTrainees struct {
+	dto.HttpContent
+	jwt.Content
+}
+
+type GetUserInstructor struct {
+	dto.HttpContent
+	jwt.Content
+	Id uint `param:"id" valid:"required,min=0;exclude"`
+}
+
+type AssignInstructor struct {
+	dto.HttpContent
+	jwt.Content
+	Id           uint   `param:"id" valid:"required,min=0;exclude"`
+	InstructorId uint   `json:"instructor_id" valid:"required,min=0;exclude"`
+	Reason       string `json:"reason" valid:"required,max=255"`
+}
+
+type UnassignInstructor struct {
+	dto.HttpContent
+	jwt.Content
+	Id     uint   `param:"id" valid:"required,min=0;exclude"`
+	Reason string `json:"reason" valid:"required,max=255"`
+}
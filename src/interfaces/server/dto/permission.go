@@ -50,3 +50,39 @@ type RevokeUserRole struct {
 	UserId  uint   `param:"id" valid:"required,min=0;exclude"`
 	RoleIds []uint `json:"ids" valid:"required,min=0;exclude"`
 }
+
+type GetPermissionCatalog struct {
+	Lang string `query:"lang"`
+}
+
+type PermissionNode struct {
+	Name        string   `json:"name"`
+	Bit         int      `json:"bit"`
+	Value       uint64   `json:"value"`
+	DisplayName string   `json:"display_name"`
+	Category    string   `json:"category"`
+	Implies     []string `json:"implies"`
+}
+
+type PermissionCatalog struct {
+	Lang  string            `json:"lang"`
+	Nodes []*PermissionNode `json:"nodes"`
+}
+
+type DecodePermission struct {
+	Value uint64 `query:"value"`
+}
+
+type DecodedPermission struct {
+	Value       uint64   `json:"value"`
+	Nodes       []string `json:"nodes"`
+	UnknownBits uint64   `json:"unknown_bits"`
+}
+
+type SetRoleUsers struct {
+	dto.HttpContent
+	jwt.Content
+	RoleId uint `param:"id" valid:"required,min=0;exclude"`
+	// UserIds 角色的全部成员, 缺少该字段时拒绝请求, 清空角色成员需要显式传入空列表
+	UserIds *[]uint `json:"ids"`
+}
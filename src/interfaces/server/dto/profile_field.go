@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package dto
+package dto
+
+import (
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+)
+
+type ProfileFieldInfo struct {
+	Id         uint     `json:"id"`
+	Key        string   `json:"key"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Options    []string `json:"options"`
+	Regex      string   `json:"regex"`
+	Visibility string   `json:"visibility"`
+	Editable   string   `json:"editable"`
+}
+
+func (p *ProfileFieldInfo) FromEntity(field *Entity.ProfileField) *ProfileFieldInfo {
+	p.Id = field.ID
+	p.Key = field.Key
+	p.Name = field.Name
+	p.Type = field.Type
+	p.Options = field.Options
+	if p.Options == nil {
+		p.Options = make([]string, 0)
+	}
+	p.Regex = field.Regex
+	p.Visibility = field.Visibility
+	p.Editable = field.Editable
+	return p
+}
+
+type GetProfileFields struct {
+	dto.HttpContent
+	jwt.Content
+}
+
+type ProfileFieldList struct {
+	Fields []*ProfileFieldInfo `json:"fields"`
+}
+
+type CreateProfileField struct {
+	dto.HttpContent
+	jwt.Content
+	Key        string   `json:"key" valid:"required,max=32,regex=^[a-z][a-z0-9_]*$"`
+	Name       string   `json:"name" valid:"required,max=64"`
+	Type       string   `json:"type" valid:"required"`
+	Options    []string `json:"options"`
+	Regex      string   `json:"regex" valid:"max=255"`
+	Visibility string   `json:"visibility" valid:"required"`
+	Editable   string   `json:"editable" valid:"required"`
+}
+
+type UpdateProfileField struct {
+	dto.HttpContent
+	jwt.Content
+	Id         uint     `param:"id" valid:"required,min=0;exclude"`
+	Name       string   `json:"name" valid:"max=64"`
+	Options    []string `json:"options"`
+	Regex      *string  `json:"regex"`
+	Visibility string   `json:"visibility"`
+	Editable   string   `json:"editable"`
+}
+
+type DeleteProfileField struct {
+	dto.HttpContent
+	jwt.Content
+	Id uint `param:"id" valid:"required,min=0;exclude"`
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package dto
+package dto
+
+import (
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+)
+
+type RatingChangeInfo struct {
+	Id         uint      `json:"id"`
+	OldRating  int       `json:"old_rating"`
+	OldName    string    `json:"old_name"`
+	NewRating  int       `json:"new_rating"`
+	NewName    string    `json:"new_name"`
+	OperatorId uint      `json:"operator_id"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (info *RatingChangeInfo) FromEntity(change *Entity.RatingChange, oldName string, newName string) *RatingChangeInfo {
+	info.Id = change.ID
+	info.OldRating = change.OldRating
+	info.OldName = oldName
+	info.NewRating = change.NewRating
+	info.NewName = newName
+	info.OperatorId = change.OperatorId
+	info.Reason = change.Reason
+	info.CreatedAt = change.CreatedAt
+	return info
+}
+
+type RatingInfo struct {
+	Rating  int                 `json:"rating"`
+	Name    string              `json:"name"`
+	Since   time.Time           `json:"since"`
+	History []*RatingChangeInfo `json:"history"`
+}
+
+type GetRating struct {
+	dto.HttpContent
+	jwt.Content
+	Id uint `param:"id" valid:"required,min=0;exclude"`
+}
+
+type ChangeRating struct {
+	dto.HttpContent
+	jwt.Content
+	Id     uint   `param:"id" valid:"required,min=0;exclude"`
+	Rating *int   `json:"rating"`
+	Reason string `json:"reason" valid:"required,max=255"`
+}
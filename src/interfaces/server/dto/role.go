@@ -26,7 +26,7 @@ func (role *BaseRoleInfo) FromRoleEntity(entity *entity.Role) {
 
 type RoleInfo struct {
 	BaseRoleInfo
-	Users []*BaseUserInfo `json:"users"`
+	UserCount int64 `json:"user_count"`
 }
 
 func (role *RoleInfo) FromRoleEntity(entity *entity.Role) {
@@ -51,8 +51,27 @@ type UpdateRole struct {
 type DeleteRole struct {
 	dto.HttpContent
 	jwt.Content
-	Id    uint `param:"id" valid:"required,min=0;exclude"`
-	Force bool `query:"force"`
+	Id            uint `param:"id" valid:"required,min=0;exclude"`
+	Force         bool `query:"force"`
+	ReplacementId uint `query:"replacement_id"`
+}
+
+type GetRoleDeletionImpact struct {
+	dto.HttpContent
+	jwt.Content
+	Id            uint `param:"id" valid:"required,min=0;exclude"`
+	ReplacementId uint `query:"replacement_id"`
+}
+
+type RoleDeletionImpactUser struct {
+	User            *BaseUserInfo `json:"user"`
+	LostPermissions []string      `json:"lost_permissions"`
+}
+
+type RoleDeletionImpact struct {
+	Role        *BaseRoleInfo             `json:"role"`
+	Replacement *BaseRoleInfo             `json:"replacement"`
+	Users       []*RoleDeletionImpactUser `json:"users"`
 }
 
 type GetRoleDetail struct {
@@ -69,9 +88,74 @@ type GetRolePage struct {
 	Search   string `query:"search"`
 }
 
+type GetRoleUserPage struct {
+	dto.HttpContent
+	jwt.Content
+	Id       uint   `param:"id" valid:"required,min=0;exclude"`
+	PageNum  int    `query:"page_num" valid:"required,min=0;exclude"`
+	PageSize int    `query:"page_size" valid:"required,min=0;exclude"`
+	Search   string `query:"search"`
+}
+
+type GetRoleUserPageResponse struct {
+	Data     []*BaseUserInfo `json:"page_data"`
+	Total    int             `json:"total"`
+	PageNum  int             `json:"page_num"`
+	PageSize int             `json:"page_size"`
+}
+
 type GetRolePageResponse struct {
 	Data     []*BaseRoleInfo `json:"page_data"`
 	Total    int             `json:"total"`
 	PageNum  int             `json:"page_num"`
 	PageSize int             `json:"page_size"`
 }
+
+const RoleTemplateVersion = 1
+
+type RoleTemplate struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description" yaml:"description"`
+	Permissions []string `json:"permissions" yaml:"permissions"`
+	// UnknownBits 无法解码为权限节点的权限位, 导入时原样保留
+	UnknownBits uint64 `json:"unknown_bits,omitempty" yaml:"unknown_bits,omitempty"`
+	Members     []uint `json:"members,omitempty" yaml:"members,omitempty"`
+}
+
+// RoleTemplateFile 角色模板文件, ManageMembers 为 true 时导入会将角色成员同步为 Members 中的用户
+type RoleTemplateFile struct {
+	Version       int             `json:"version" yaml:"version"`
+	ExportedAt    string          `json:"exported_at,omitempty" yaml:"exported_at,omitempty"`
+	ManageMembers bool            `json:"manage_members" yaml:"manage_members"`
+	Roles         []*RoleTemplate `json:"roles" yaml:"roles"`
+}
+
+type ExportRoles struct {
+	dto.HttpContent
+	jwt.Content
+	Format  string `query:"format"`
+	Members bool   `query:"members"`
+}
+
+type ImportRoles struct {
+	dto.HttpContent
+	jwt.Content
+	DryRun bool              `query:"dry_run"`
+	Prune  bool              `query:"prune"`
+	File   *RoleTemplateFile `json:"-"`
+}
+
+type RoleDiff struct {
+	Name    string   `json:"name"`
+	Changes []string `json:"changes"`
+}
+
+type ImportRolesResponse struct {
+	DryRun    bool        `json:"dry_run"`
+	Created   []*RoleDiff `json:"created"`
+	Changed   []*RoleDiff `json:"changed"`
+	Removed   []*RoleDiff `json:"removed"`
+	Unchanged []string    `json:"unchanged"`
+	// NotInTemplate 不在模板中且未删除的角色, Prune 为 true 时这些角色出现在 Removed 中
+	NotInTemplate []string `json:"not_in_template"`
+}
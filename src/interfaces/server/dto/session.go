@@ -0,0 +1,14 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package dto
+package dto
+
+import "half-nothing.cn/service-core/interfaces/http/jwt"
+
+// CheckSession 校验访问令牌所属的会话是否仍然有效
+type CheckSession struct {
+	jwt.Content
+	// PasswordChange 请求为修改密码, 管理员要求修改密码时只允许该请求
+	PasswordChange bool
+}
@@ -5,8 +5,9 @@
 package dto
 
 import (
-	"fmt"
+	"strings"
 	"time"
+	Entity "user-service/src/interfaces/database/entity"
 
 	"half-nothing.cn/service-core/interfaces/database/entity"
 	"half-nothing.cn/service-core/interfaces/http/dto"
@@ -21,29 +22,93 @@ type BaseUserInfo struct {
 	Email     string `json:"email"`
 	Cid       uint   `json:"cid"`
 	AvatarUrl string `json:"avatar_url"`
-	QQ        string `json:"qq"`
 }
 
-func (b *BaseUserInfo) FromUserEntity(user *entity.User) *BaseUserInfo {
+// AvatarResolver 根据用户与用户的隐私设置解析头像地址, profile 为 nil 时使用默认设置
+type AvatarResolver func(user *entity.User, profile *Entity.UserProfile) string
+
+// avatarResolver 启动时根据配置设置
+var avatarResolver AvatarResolver = func(user *entity.User, profile *Entity.UserProfile) string {
+	if user.CurrentAvatar != nil {
+		return user.CurrentAvatar.Url
+	}
+	return ""
+}
+
+// SetAvatarResolver 设置头像地址的解析方式
+func SetAvatarResolver(resolver AvatarResolver) {
+	avatarResolver = resolver
+}
+
+func (b *BaseUserInfo) FromUserEntity(user *entity.User, profile *Entity.UserProfile) *BaseUserInfo {
 	b.Id = user.ID
 	b.Username = user.Username
 	b.Email = user.Email
 	b.Cid = user.Cid
-	if user.QQ != nil {
-		b.QQ = *user.QQ
+	b.AvatarUrl = avatarResolver(user, profile)
+	return b
+}
+
+// ProfileInfo 用户扩展资料, RealName 仅对本人与管理人员可见, 自定义字段按字段定义的可见范围过滤
+type ProfileInfo struct {
+	DisplayName  string            `json:"display_name"`
+	RealName     *string           `json:"real_name,omitempty"`
+	Timezone     string            `json:"timezone"`
+	Language     string            `json:"language"`
+	Division     *DivisionInfo     `json:"division"`
+	CustomFields map[string]string `json:"custom_fields"`
+	Privacy      *PrivacyInfo      `json:"privacy,omitempty"`
+}
+
+// PrivacyInfo 公开资料的隐私设置
+type PrivacyInfo struct {
+	ShowUsername  bool `json:"show_username"`
+	ShowAvatar    bool `json:"show_avatar"`
+	ShowRating    bool `json:"show_rating"`
+	ShowRoles     bool `json:"show_roles"`
+	ShowDivision  bool `json:"show_division"`
+	AllowQQAvatar bool `json:"allow_qq_avatar"`
+	AllowGravatar bool `json:"allow_gravatar"`
+}
+
+// FromProfileEntity 按查看者所处的可见范围填充扩展资料
+func (p *ProfileInfo) FromProfileEntity(profile *Entity.UserProfile, viewer string) *ProfileInfo {
+	p.CustomFields = make(map[string]string)
+	if profile == nil {
+		profile = Entity.DefaultUserProfile(0)
 	}
-	if user.CurrentAvatar != nil {
-		b.AvatarUrl = user.CurrentAvatar.Url
-	} else if b.QQ != "" {
-		b.AvatarUrl = fmt.Sprintf("https://q2.qlogo.cn/headimg_dl?dst_uin=%s&spec=100", b.QQ)
-	} else {
-		b.AvatarUrl = ""
+	if Entity.CanView(Entity.VisibilitySelf, viewer) {
+		p.Privacy = &PrivacyInfo{
+			ShowUsername:  profile.ShowUsername,
+			ShowAvatar:    profile.ShowAvatar,
+			ShowRating:    profile.ShowRating,
+			ShowRoles:     profile.ShowRoles,
+			ShowDivision:  profile.ShowDivision,
+			AllowQQAvatar: profile.AllowQQAvatar,
+			AllowGravatar: profile.AllowGravatar,
+		}
 	}
-	return b
+	p.DisplayName = profile.DisplayName
+	p.Timezone = profile.Timezone
+	p.Language = profile.Language
+	if profile.Division != nil {
+		p.Division = (&DivisionInfo{}).FromDivisionEntity(profile.Division)
+	}
+	if Entity.CanView(Entity.VisibilitySelf, viewer) {
+		p.RealName = &profile.RealName
+	}
+	for _, value := range profile.Fields {
+		if value.Field != nil && Entity.CanView(value.Field.Visibility, viewer) {
+			p.CustomFields[value.Field.Key] = value.Value
+		}
+	}
+	return p
 }
 
+// UserInfo 用户本人与管理人员可见的用户信息
 type UserInfo struct {
 	BaseUserInfo
+	QQ              string          `json:"qq"`
 	Rating          int             `json:"rating"`
 	Permission      uint64          `json:"permission"`
 	TotalPermission uint64          `json:"total_permission"`
@@ -51,10 +116,15 @@ type UserInfo struct {
 	LastLoginTime   *time.Time      `json:"last_login_time"`
 	LastLoginIp     *string         `json:"last_login_ip"`
 	Roles           []*BaseRoleInfo `json:"roles"`
+	Profile         *ProfileInfo    `json:"profile"`
 }
 
-func (u *UserInfo) FromUserEntity(user *entity.User) *UserInfo {
-	u.BaseUserInfo.FromUserEntity(user)
+func (u *UserInfo) FromUserEntity(user *entity.User, profile *Entity.UserProfile) *UserInfo {
+	u.BaseUserInfo.FromUserEntity(user, profile)
+	u.Profile = (&ProfileInfo{}).FromProfileEntity(profile, Entity.VisibilitySelf)
+	if user.QQ != nil {
+		u.QQ = *user.QQ
+	}
 	u.Rating = user.Rating
 	perm := permission.Permission(user.Permission)
 	utils.ForEach(user.Roles, func(index int, role *entity.UserRole) {
@@ -83,8 +153,9 @@ type FullUserInfo struct {
 	BannedTime *time.Time `json:"banned_time"`
 }
 
-func (u *FullUserInfo) FromUserEntity(user *entity.User) *FullUserInfo {
-	u.UserInfo.FromUserEntity(user)
+func (u *FullUserInfo) FromUserEntity(user *entity.User, profile *Entity.UserProfile) *FullUserInfo {
+	u.UserInfo.FromUserEntity(user, profile)
+	u.Profile = (&ProfileInfo{}).FromProfileEntity(profile, Entity.VisibilityStaff)
 	u.Banned = user.Banned
 	if user.BannedUntil.Valid {
 		u.BannedTime = &user.BannedUntil.Time
@@ -94,34 +165,70 @@ func (u *FullUserInfo) FromUserEntity(user *entity.User) *FullUserInfo {
 
 type UserRegister struct {
 	dto.HttpContent
-	Username string `json:"username" valid:"required,max=64,regex=^[A-Za-z_-][\\w-]*$"`
-	Email    string `json:"email" valid:"required,max=128,regex=^[\\w-]+@[\\w-]+(\\.[\\w-]+)+$"`
-	Password string `json:"password" valid:"required"`
-	Code     string `json:"code" valid:"required,length=6"`
-	Cid      int    `json:"cid" valid:"required,min=0;exclude"`
+	Username  string `json:"username" valid:"required,max=64,regex=^[A-Za-z_-][\\w-]*$"`
+	Email     string `json:"email" valid:"required,max=128"`
+	Password  string `json:"password" valid:"required"`
+	Code      string `json:"code" valid:"required,length=6"`
+	Challenge string `json:"challenge"`
+}
+
+// NormalizeUsername 规范化用户名, 去除首尾空白
+func NormalizeUsername(username string) string {
+	return strings.TrimSpace(username)
+}
+
+// NormalizeEmail 规范化邮箱, 去除首尾空白并转为小写
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Normalize 按注册时保存的形式规范化输入
+func (form *UserRegister) Normalize() {
+	form.Username = NormalizeUsername(form.Username)
+	form.Email = NormalizeEmail(form.Email)
+}
+
+// RegisterResult 注册结果, 呼号由服务端分配
+type RegisterResult struct {
+	Cid uint `json:"cid"`
 }
 
 type UserCheckAvailability struct {
 	dto.HttpContent
-	Username string `query:"username" valid:"max=64,regex=^[A-Za-z_-][\\w-]*$"`
-	Email    string `query:"email" valid:"max=128,regex=^[\\w-]+@[\\w-]+(\\.[\\w-]+)+$"`
-	Cid      int    `query:"cid"`
+	Username  string `query:"username" valid:"max=64,regex=^[A-Za-z_-][\\w-]*$"`
+	Email     string `query:"email" valid:"max=128"`
+	Challenge string `query:"challenge"`
+}
+
+func (form *UserCheckAvailability) Normalize() {
+	form.Username = NormalizeUsername(form.Username)
+	form.Email = NormalizeEmail(form.Email)
+}
+
+// AvailabilityResult 各字段是否可用, 未检查的字段不返回
+type AvailabilityResult struct {
+	Username *bool `json:"username,omitempty"`
+	Email    *bool `json:"email,omitempty"`
 }
 
 type UserResetPassword struct {
 	dto.HttpContent
 
-	Email    string `json:"email" valid:"required,max=128,regex=^[\\w-]+@[\\w-]+(\\.[\\w-]+)+$"`
-	Code     string `json:"code" valid:"required,length=6"`
-	Password string `json:"password" valid:"required"`
+	Email     string `json:"email" valid:"required,max=128"`
+	Code      string `json:"code" valid:"required,length=6"`
+	Password  string `json:"password" valid:"required"`
+	Challenge string `json:"challenge"`
 }
 
 type GetUserPage struct {
 	dto.HttpContent
 	jwt.Content
-	PageNum  int    `query:"page_num" valid:"required,min=0;exclude"`
-	PageSize int    `query:"page_size" valid:"required,min=0;exclude"`
-	Search   string `query:"search"`
+	PageNum    int    `query:"page_num" valid:"required,min=0;exclude"`
+	PageSize   int    `query:"page_size" valid:"required,min=0;exclude"`
+	Search     string `query:"search"`
+	DivisionId uint   `query:"division_id"`
+	Field      string `query:"field"`
+	FieldValue string `query:"field_value"`
 }
 
 type GetUserPageResponse struct {
@@ -145,11 +252,105 @@ type GetUserData struct {
 type UpdateCurrentUserData struct {
 	dto.HttpContent
 	jwt.Content
-	Username  string `json:"username" valid:"max=64,regex=^[A-Za-z_-][\\w-]*$"`
-	Email     string `json:"email" valid:"max=128,regex=^[\\w-]+@[\\w-]+(\\.[\\w-]+)+$"`
-	EmailCode string `json:"email_code" valid:"length=6"`
-	QQ        string `json:"qq" valid:"max=16,regex=^[1-9][0-9]*$"`
-	ImageId   *uint  `json:"image_id"`
+	Username string `json:"username" valid:"max=64,regex=^[A-Za-z_-][\\w-]*$"`
+	// Email 新邮箱, 需要通过发送到新邮箱的确认链接确认后才会生效
+	Email   string `json:"email" valid:"max=128"`
+	QQ      string `json:"qq" valid:"max=16,regex=^[1-9][0-9]*$"`
+	ImageId *uint  `json:"image_id"`
+	UpdateProfile
+}
+
+// UpdateProfile 扩展资料修改字段, 空字符串表示不修改, DivisionId 为 0 表示清除所属分区
+type UpdateProfile struct {
+	DisplayName string `json:"display_name" valid:"max=64"`
+	RealName    string `json:"real_name" valid:"max=64"`
+	Timezone    string `json:"timezone" valid:"max=64"`
+	Language    string `json:"language" valid:"max=16,regex=^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$"`
+	DivisionId  *uint  `json:"division_id"`
+	// CustomFields 自定义字段键到字段值的映射, 值为空字符串时删除该字段值
+	CustomFields map[string]string `json:"custom_fields"`
+	// Privacy 公开资料的隐私设置, 为空的项不修改
+	Privacy *UpdatePrivacy `json:"privacy"`
+}
+
+type UpdatePrivacy struct {
+	ShowUsername  *bool `json:"show_username"`
+	ShowAvatar    *bool `json:"show_avatar"`
+	ShowRating    *bool `json:"show_rating"`
+	ShowRoles     *bool `json:"show_roles"`
+	ShowDivision  *bool `json:"show_division"`
+	AllowQQAvatar *bool `json:"allow_qq_avatar"`
+	AllowGravatar *bool `json:"allow_gravatar"`
+}
+
+// PublicUserInfo 对所有登录用户公开的资料, 未被用户设置为公开的内容不返回
+type PublicUserInfo struct {
+	Id           uint              `json:"id"`
+	Cid          uint              `json:"cid"`
+	DisplayName  string            `json:"display_name"`
+	Username     *string           `json:"username,omitempty"`
+	AvatarUrl    *string           `json:"avatar_url,omitempty"`
+	Rating       *int              `json:"rating,omitempty"`
+	Roles        []string          `json:"roles,omitempty"`
+	Division     *DivisionInfo     `json:"division,omitempty"`
+	CustomFields map[string]string `json:"custom_fields"`
+}
+
+func (u *PublicUserInfo) FromUserEntity(user *entity.User, profile *Entity.UserProfile) *PublicUserInfo {
+	if profile == nil {
+		profile = Entity.DefaultUserProfile(user.ID)
+	}
+	u.Id = user.ID
+	u.Cid = user.Cid
+	u.DisplayName = profile.DisplayName
+	u.CustomFields = make(map[string]string)
+	if profile.ShowUsername {
+		u.Username = &user.Username
+	}
+	if profile.ShowAvatar {
+		avatarUrl := avatarResolver(user, profile)
+		u.AvatarUrl = &avatarUrl
+	}
+	if profile.ShowRating {
+		u.Rating = &user.Rating
+	}
+	if profile.ShowRoles {
+		u.Roles = make([]string, 0, len(user.Roles))
+		utils.ForEach(user.Roles, func(_ int, role *entity.UserRole) {
+			if role.Role != nil {
+				u.Roles = append(u.Roles, role.Role.Name)
+			}
+		})
+	}
+	if profile.ShowDivision && profile.Division != nil {
+		u.Division = (&DivisionInfo{}).FromDivisionEntity(profile.Division)
+	}
+	for _, value := range profile.Fields {
+		if value.Field != nil && Entity.CanView(value.Field.Visibility, Entity.VisibilityPublic) {
+			u.CustomFields[value.Field.Key] = value.Value
+		}
+	}
+	return u
+}
+
+// GetPublicProfile 按用户 ID 或呼号获取公开资料
+type GetPublicProfile struct {
+	dto.HttpContent
+	jwt.Content
+	Id  uint `param:"id"`
+	Cid uint `param:"cid"`
+}
+
+// BatchGetPublicProfile 批量获取公开资料, 不存在的用户不在结果中
+type BatchGetPublicProfile struct {
+	dto.HttpContent
+	jwt.Content
+	Ids  []uint `json:"ids"`
+	Cids []uint `json:"cids"`
+}
+
+type PublicProfileList struct {
+	Profiles []*PublicUserInfo `json:"profiles"`
 }
 
 type UpdateUserPassword struct {
@@ -164,9 +365,12 @@ type UpdateUserData struct {
 	jwt.Content
 	Id       uint   `param:"id" valid:"required,min=0;exclude"`
 	Username string `json:"username" valid:"max=64,regex=^[A-Za-z_-][\\w-]*$"`
-	Email    string `json:"email" valid:"max=128,regex=^[\\w-]+@[\\w-]+(\\.[\\w-]+)+$"`
+	Email    string `json:"email" valid:"max=128"`
 	QQ       string `json:"qq" valid:"max=16,regex=^[1-9][0-9]*$"`
 	Password string `json:"password"`
+	// RequirePasswordChange 要求用户在下次登录后修改密码, 可以代替或配合 Password 使用
+	RequirePasswordChange bool `json:"require_password_change"`
+	UpdateProfile
 }
 
 type BanUser struct {
@@ -182,3 +386,41 @@ type UnbanUser struct {
 	jwt.Content
 	Id uint `param:"id" valid:"required,min=0;exclude"`
 }
+
+type RequestAccountDeletion struct {
+	dto.HttpContent
+	jwt.Content
+	Password  string `json:"password" valid:"required"`
+	EmailCode string `json:"email_code" valid:"required,length=6"`
+	Reason    string `json:"reason" valid:"max=255"`
+}
+
+type GetAccountDeletion struct {
+	dto.HttpContent
+	jwt.Content
+}
+
+type CancelAccountDeletion struct {
+	dto.HttpContent
+	jwt.Content
+}
+
+type AccountDeletionInfo struct {
+	Status      string    `json:"status"`
+	RequestedAt time.Time `json:"requested_at"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+func (a *AccountDeletionInfo) FromEntity(deletion *Entity.AccountDeletion) *AccountDeletionInfo {
+	a.Status = deletion.Status
+	a.RequestedAt = deletion.CreatedAt
+	a.ScheduledAt = deletion.ScheduledAt
+	return a
+}
+
+type DeleteUser struct {
+	dto.HttpContent
+	jwt.Content
+	Id     uint   `param:"id" valid:"required,min=0;exclude"`
+	Reason string `json:"reason" valid:"required,max=255"`
+}
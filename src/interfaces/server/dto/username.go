@@ -0,0 +1,49 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package dto
+package dto
+
+import (
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+)
+
+type UsernameChangeInfo struct {
+	Id            uint      `json:"id"`
+	OldUsername   string    `json:"old_username"`
+	NewUsername   string    `json:"new_username"`
+	OperatorCid   uint      `json:"operator_cid"`
+	ReservedUntil time.Time `json:"reserved_until"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (info *UsernameChangeInfo) FromEntity(change *Entity.UsernameChange) *UsernameChangeInfo {
+	info.Id = change.ID
+	info.OldUsername = change.OldUsername
+	info.NewUsername = change.NewUsername
+	info.OperatorCid = change.OperatorCid
+	info.ReservedUntil = change.ReservedUntil
+	info.CreatedAt = change.CreatedAt
+	return info
+}
+
+// UsernameHistory 用户名修改历史, NextChangeAt 为用户下次可以自行修改用户名的时间
+type UsernameHistory struct {
+	History      []*UsernameChangeInfo `json:"history"`
+	NextChangeAt *time.Time            `json:"next_change_at,omitempty"`
+}
+
+type GetSelfUsernameHistory struct {
+	dto.HttpContent
+	jwt.Content
+}
+
+type GetUsernameHistory struct {
+	dto.HttpContent
+	jwt.Content
+	Id uint `param:"id" valid:"required,min=0;exclude"`
+}
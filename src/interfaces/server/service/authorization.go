@@ -0,0 +1,18 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+)
+
+type AuthorizationInterface interface {
+	Evaluate(request *DTO.AuthorizationRequest) (*DTO.AuthorizationDecision, error)
+	EvaluateBatch(requests []*DTO.AuthorizationRequest) ([]*DTO.AuthorizationDecision, error)
+	CheckPermission(data *DTO.CheckPermission) *dto.ApiResponse[*DTO.AuthorizationDecision]
+	BatchCheckPermission(data *DTO.BatchCheckPermission) *dto.ApiResponse[*DTO.BatchCheckPermissionResponse]
+}
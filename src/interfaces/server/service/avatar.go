@@ -0,0 +1,22 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+)
+
+type AvatarInterface interface {
+	Upload(data *DTO.UploadAvatar) *dto.ApiResponse[*DTO.AvatarInfo]
+	GetSelfAvatars(data *DTO.GetSelfAvatars) *dto.ApiResponse[*DTO.AvatarList]
+	SelectAvatar(data *DTO.SelectAvatar) *dto.ApiResponse[bool]
+	ClearAvatar(data *DTO.ClearAvatar) *dto.ApiResponse[bool]
+	GetUserAvatars(data *DTO.GetUserAvatars) *dto.ApiResponse[*DTO.AvatarList]
+	RemoveAvatar(data *DTO.RemoveAvatar) *dto.ApiResponse[bool]
+	GetFile(data *DTO.GetAvatarFile) (*DTO.AvatarFile, *dto.ApiResponse[bool])
+	GetIdenticon(data *DTO.GetIdenticon) (*DTO.AvatarFile, *dto.ApiResponse[bool])
+}
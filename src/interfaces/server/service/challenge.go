@@ -0,0 +1,15 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+)
+
+type ChallengeInterface interface {
+	GetChallenge(data *DTO.GetChallenge) *dto.ApiResponse[*DTO.ChallengeInfo]
+}
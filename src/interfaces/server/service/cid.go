@@ -0,0 +1,18 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+)
+
+type CidInterface interface {
+	GetReservations(data *DTO.GetCidReservations) *dto.ApiResponse[*DTO.CidAllocationInfo]
+	Reserve(data *DTO.ReserveCid) *dto.ApiResponse[bool]
+	Release(data *DTO.ReleaseCid) *dto.ApiResponse[bool]
+	AssignCid(data *DTO.AssignCid) *dto.ApiResponse[bool]
+}
@@ -0,0 +1,22 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+)
+
+type DivisionInterface interface {
+	GetPages(page *DTO.GetDivisionPage) *dto.ApiResponse[*DTO.GetDivisionPageResponse]
+	GetById(data *DTO.GetDivisionDetail) *dto.ApiResponse[*DTO.DivisionInfo]
+	Create(data *DTO.CreateDivision) *dto.ApiResponse[bool]
+	Update(data *DTO.UpdateDivision) *dto.ApiResponse[bool]
+	Delete(data *DTO.DeleteDivision) *dto.ApiResponse[bool]
+	AddMembers(data *DTO.EditDivisionMember) *dto.ApiResponse[bool]
+	RemoveMembers(data *DTO.EditDivisionMember) *dto.ApiResponse[bool]
+	GetUserDivisions(data *DTO.GetUserDivisions) *dto.ApiResponse[*DTO.UserDivisionsResponse]
+}
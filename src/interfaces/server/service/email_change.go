@@ -0,0 +1,18 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+)
+
+type EmailChangeInterface interface {
+	GetEmailChange(data *DTO.GetEmailChange) *dto.ApiResponse[*DTO.EmailChangeInfo]
+	CancelEmailChange(data *DTO.CancelEmailChange) *dto.ApiResponse[bool]
+	ConfirmEmailChange(data *DTO.ConfirmEmailChange) *dto.ApiResponse[bool]
+	RevertEmailChange(data *DTO.RevertEmailChange) *dto.ApiResponse[bool]
+}
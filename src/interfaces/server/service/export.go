@@ -0,0 +1,18 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+)
+
+type DataExportInterface interface {
+	RequestExport(data *DTO.RequestDataExport) *dto.ApiResponse[*DTO.DataExportInfo]
+	GetExport(data *DTO.GetDataExport) *dto.ApiResponse[*DTO.DataExportInfo]
+	Download(data *DTO.DownloadDataExport) (*DTO.DataExportFile, *dto.ApiResponse[bool])
+	PurgeExpired() (int64, error)
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+)
+
+type InstructorInterface interface {
+	GetSelfInstructor(data *DTO.GetSelfInstructor) *dto.ApiResponse[*DTO.InstructorHistory]
+	GetSelfTrainees(data *DTO.GetSelfTrainees) *dto.ApiResponse[*DTO.TraineeList]
+	GetUserInstructor(data *DTO.GetUserInstructor) *dto.ApiResponse[*DTO.InstructorHistory]
+	AssignInstructor(data *DTO.AssignInstructor) *dto.ApiResponse[bool]
+	UnassignInstructor(data *DTO.UnassignInstructor) *dto.ApiResponse[bool]
+}
@@ -17,4 +17,9 @@ type PermissionInterface interface {
 	RevokeUserRole(data *DTO.RevokeUserRole) *dto.ApiResponse[bool]
 	GrantRoleUser(data *DTO.GrantRoleUser) *dto.ApiResponse[bool]
 	RevokeRoleUser(data *DTO.RevokeRoleUser) *dto.ApiResponse[bool]
+	SetRoleUsers(data *DTO.SetRoleUsers) *dto.ApiResponse[bool]
+	GrantScopedRole(data *DTO.GrantScopedRole) *dto.ApiResponse[bool]
+	RevokeScopedRole(data *DTO.RevokeScopedRole) *dto.ApiResponse[bool]
+	GetCatalog(data *DTO.GetPermissionCatalog) (etag string, res *dto.ApiResponse[*DTO.PermissionCatalog])
+	DecodePermission(data *DTO.DecodePermission) *dto.ApiResponse[*DTO.DecodedPermission]
 }
@@ -0,0 +1,18 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+)
+
+type ProfileFieldInterface interface {
+	GetFields(data *DTO.GetProfileFields) *dto.ApiResponse[*DTO.ProfileFieldList]
+	Create(data *DTO.CreateProfileField) *dto.ApiResponse[bool]
+	Update(data *DTO.UpdateProfileField) *dto.ApiResponse[bool]
+	Delete(data *DTO.DeleteProfileField) *dto.ApiResponse[bool]
+}
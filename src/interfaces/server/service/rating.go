@@ -0,0 +1,16 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+)
+
+type RatingInterface interface {
+	GetRating(data *DTO.GetRating) *dto.ApiResponse[*DTO.RatingInfo]
+	ChangeRating(data *DTO.ChangeRating) *dto.ApiResponse[bool]
+}
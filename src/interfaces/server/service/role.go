@@ -13,7 +13,11 @@ import (
 type RoleInterface interface {
 	GetPages(page *DTO.GetRolePage) *dto.ApiResponse[*DTO.GetRolePageResponse]
 	GetById(data *DTO.GetRoleDetail) *dto.ApiResponse[*DTO.RoleInfo]
+	GetUsers(data *DTO.GetRoleUserPage) *dto.ApiResponse[*DTO.GetRoleUserPageResponse]
 	Create(role *DTO.CreateRole) *dto.ApiResponse[bool]
 	Update(role *DTO.UpdateRole) *dto.ApiResponse[bool]
 	Delete(role *DTO.DeleteRole) *dto.ApiResponse[bool]
+	GetDeletionImpact(data *DTO.GetRoleDeletionImpact) *dto.ApiResponse[*DTO.RoleDeletionImpact]
+	Export(data *DTO.ExportRoles) (*DTO.RoleTemplateFile, *dto.ApiResponse[bool])
+	Import(data *DTO.ImportRoles) *dto.ApiResponse[*DTO.ImportRolesResponse]
 }
@@ -0,0 +1,16 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+)
+
+type SessionInterface interface {
+	// CheckSession 会话有效时返回 nil
+	CheckSession(data *DTO.CheckSession) *dto.ApiStatus
+}
@@ -11,8 +11,8 @@ import (
 )
 
 type UserInterface interface {
-	Register(data *DTO.UserRegister) *dto.ApiResponse[bool]
-	CheckAvailability(data *DTO.UserCheckAvailability) *dto.ApiResponse[bool]
+	Register(data *DTO.UserRegister) *dto.ApiResponse[*DTO.RegisterResult]
+	CheckAvailability(data *DTO.UserCheckAvailability) *dto.ApiResponse[*DTO.AvailabilityResult]
 	ResetPassword(data *DTO.UserResetPassword) *dto.ApiResponse[bool]
 	GetPages(data *DTO.GetUserPage) *dto.ApiResponse[*DTO.GetUserPageResponse]
 	GetSelfData(data *DTO.GetCurrentUserData) *dto.ApiResponse[*DTO.UserInfo]
@@ -22,4 +22,11 @@ type UserInterface interface {
 	UpdatePassword(data *DTO.UpdateUserPassword) *dto.ApiResponse[bool]
 	Ban(data *DTO.BanUser) *dto.ApiResponse[bool]
 	Unban(data *DTO.UnbanUser) *dto.ApiResponse[bool]
+	RequestDeletion(data *DTO.RequestAccountDeletion) *dto.ApiResponse[*DTO.AccountDeletionInfo]
+	GetDeletion(data *DTO.GetAccountDeletion) *dto.ApiResponse[*DTO.AccountDeletionInfo]
+	CancelDeletion(data *DTO.CancelAccountDeletion) *dto.ApiResponse[bool]
+	Delete(data *DTO.DeleteUser) *dto.ApiResponse[bool]
+	GetPublicProfile(data *DTO.GetPublicProfile) *dto.ApiResponse[*DTO.PublicUserInfo]
+	BatchGetPublicProfile(data *DTO.BatchGetPublicProfile) *dto.ApiResponse[*DTO.PublicProfileList]
+	ProcessDueDeletions() (int, error)
 }
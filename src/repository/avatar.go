@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"database/sql"
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/database"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type AvatarRepository struct {
+	*database.BaseRepository[*Entity.Avatar]
+}
+
+func NewAvatarRepository(
+	lg logger.Interface,
+	db *gorm.DB,
+	queryTimeout time.Duration,
+) *AvatarRepository {
+	return &AvatarRepository{
+		BaseRepository: database.NewBaseRepository[*Entity.Avatar](lg, "avatar-repository", db, queryTimeout),
+	}
+}
+
+// GetByUser 获取用户的头像历史, 按上传时间倒序排列
+func (repo *AvatarRepository) GetByUser(userId uint) (avatars []*Entity.Avatar, err error) {
+	avatars = make([]*Entity.Avatar, 0)
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("user_id = ?", userId).Order("id DESC").Find(&avatars).Error
+	})
+	return
+}
+
+func (repo *AvatarRepository) GetByKey(key string) (*Entity.Avatar, error) {
+	avatar := &Entity.Avatar{}
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Where(&Entity.Avatar{Key: key}).First(avatar).Error
+	})
+	return avatar, err
+}
+
+// Create 保存头像对应的图片与头像记录, 并设置为用户当前头像
+//
+// maxHistory 大于 0 时删除超出数量的最早的头像记录, 返回被删除的记录
+func (repo *AvatarRepository) Create(avatar *Entity.Avatar, url string, maxHistory int) (pruned []*Entity.Avatar, err error) {
+	pruned = make([]*Entity.Avatar, 0)
+	err = repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		image := &entity.Image{Url: url}
+		if err := tx.Create(image).Error; err != nil {
+			return err
+		}
+		avatar.ImageId = image.ID
+		if err := tx.Create(avatar).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.User{ID: avatar.UserId}).Update("image_id", image.ID).Error; err != nil {
+			return err
+		}
+		if maxHistory <= 0 {
+			return nil
+		}
+		err := tx.Where("user_id = ?", avatar.UserId).
+			Order("id DESC").
+			Offset(maxHistory).
+			Find(&pruned).
+			Error
+		if err != nil || len(pruned) == 0 {
+			return err
+		}
+		avatarIds := make([]uint, len(pruned))
+		imageIds := make([]uint, len(pruned))
+		for index, old := range pruned {
+			avatarIds[index] = old.ID
+			imageIds[index] = old.ImageId
+		}
+		if err := tx.Delete(&Entity.Avatar{}, "id IN ?", avatarIds).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Image{}, "id IN ?", imageIds).Error
+	})
+	return
+}
+
+// GetActiveByImage 获取用户图片对应的未移除的头像
+func (repo *AvatarRepository) GetActiveByImage(userId uint, imageId uint) (*Entity.Avatar, error) {
+	avatar := &Entity.Avatar{}
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("user_id = ? AND image_id = ? AND status = ?", userId, imageId, Entity.AvatarStatusActive).
+			First(avatar).
+			Error
+	})
+	return avatar, err
+}
+
+// SetCurrent 设置用户当前头像, imageId 为 nil 时使用默认头像
+func (repo *AvatarRepository) SetCurrent(userId uint, imageId *uint) error {
+	return repo.Query(func(tx *gorm.DB) error {
+		return tx.Model(&entity.User{ID: userId}).Update("image_id", imageId).Error
+	})
+}
+
+// Remove 标记头像已移除, 如果该头像是用户当前头像则恢复为默认头像
+func (repo *AvatarRepository) Remove(avatar *Entity.Avatar, operatorId uint, reason string) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		err := tx.Model(avatar).Updates(map[string]interface{}{
+			"status":         Entity.AvatarStatusRemoved,
+			"removed_by":     operatorId,
+			"removed_reason": reason,
+			"removed_at":     sql.NullTime{Valid: true, Time: time.Now()},
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&entity.User{}).
+			Where("id = ? AND image_id = ?", avatar.UserId, avatar.ImageId).
+			Update("image_id", nil).
+			Error
+	})
+}
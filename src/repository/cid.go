@@ -0,0 +1,167 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+	Repository "user-service/src/interfaces/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"half-nothing.cn/service-core/database"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type CidRepository struct {
+	*database.BaseRepository[*Entity.CidReservation]
+}
+
+func NewCidRepository(
+	lg logger.Interface,
+	db *gorm.DB,
+	queryTimeout time.Duration,
+) *CidRepository {
+	return &CidRepository{
+		BaseRepository: database.NewBaseRepository[*Entity.CidReservation](lg, "cid-repository", db, queryTimeout),
+	}
+}
+
+func (repo *CidRepository) GetReservations() (reservations []*Entity.CidReservation, err error) {
+	reservations = make([]*Entity.CidReservation, 0)
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Order("cid").Find(&reservations).Error
+	})
+	return
+}
+
+func (repo *CidRepository) GetReservation(cid uint) (*Entity.CidReservation, error) {
+	reservation := &Entity.CidReservation{}
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Where(&Entity.CidReservation{Cid: cid}).First(reservation).Error
+	})
+	return reservation, err
+}
+
+// cidUsed 判断呼号是否已被用户使用或已被保留
+func cidUsed(tx *gorm.DB, cid uint) (bool, error) {
+	var count int64
+	if err := tx.Model(&entity.User{}).Where("cid = ?", cid).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	if err := tx.Model(&Entity.CidReservation{}).Where("cid = ?", cid).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (repo *CidRepository) IsUsed(cid uint) (used bool, err error) {
+	err = repo.Query(func(tx *gorm.DB) error {
+		used, err = cidUsed(tx, cid)
+		return err
+	})
+	return
+}
+
+// Release 释放尚未分配给用户的保留呼号
+func (repo *CidRepository) Release(cid uint) error {
+	return repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("cid = ? AND user_id IS NULL", cid).Delete(&Entity.CidReservation{}).Error
+	})
+}
+
+// CreateUser 在同一事务中为用户分配呼号并创建用户
+//
+// 分配时锁定呼号序列, 从上次分配的位置开始查找第一个满足 allocatable 且未被使用的呼号,
+// canonicalEmail 为用户邮箱的规范化形式, 与用户在同一事务中保存
+func (repo *CidRepository) CreateUser(user *entity.User, canonicalEmail string, minCid uint, maxCid uint, allocatable func(cid uint) bool) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		sequence := &Entity.CidSequence{ID: Entity.CidSequenceId, Next: minCid}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(sequence).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(sequence, Entity.CidSequenceId).Error; err != nil {
+			return err
+		}
+		cid := max(sequence.Next, minCid)
+		for ; cid <= maxCid; cid++ {
+			if !allocatable(cid) {
+				continue
+			}
+			used, err := cidUsed(tx, cid)
+			if err != nil {
+				return err
+			}
+			if !used {
+				break
+			}
+		}
+		if cid > maxCid {
+			return Repository.ErrCidExhausted
+		}
+		user.Cid = cid
+		if err := tx.Create(user).Error; err != nil {
+			if isDuplicateKey(err) {
+				return Repository.ErrDuplicated
+			}
+			return err
+		}
+		if err := saveUserEmail(tx, user.ID, canonicalEmail); err != nil {
+			return err
+		}
+		if err := saveUserSkeleton(tx, user.ID, user.Username); err != nil {
+			return err
+		}
+		return tx.Model(sequence).Update("next", cid+1).Error
+	})
+}
+
+// AssignCid 为用户指定呼号, 呼号为保留呼号时将保留记录标记为已分配
+//
+// 用户原呼号如果来自保留记录, 该记录恢复为未分配状态, 仍然保留且可以被再次分配或释放
+func (repo *CidRepository) AssignCid(userId uint, cid uint) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entity.User{}).Where("cid = ?", cid).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return Repository.ErrCidTaken
+		}
+		err := tx.Model(&Entity.CidReservation{}).
+			Where("cid = ? AND user_id IS NOT NULL AND user_id <> ?", cid, userId).
+			Count(&count).
+			Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return Repository.ErrCidTaken
+		}
+		err = tx.Model(&Entity.CidReservation{}).
+			Where("user_id = ? AND cid <> ?", userId, cid).
+			Update("user_id", nil).
+			Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&Entity.CidReservation{}).
+			Where("cid = ? AND user_id IS NULL", cid).
+			Update("user_id", userId).
+			Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&entity.User{ID: userId}).Update("cid", cid).Error
+		if err != nil && isDuplicateKey(err) {
+			return Repository.ErrCidTaken
+		}
+		return err
+	})
+}
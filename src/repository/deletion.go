@@ -0,0 +1,170 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"database/sql"
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+	Repository "user-service/src/interfaces/repository"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/database"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type DeletionRepository struct {
+	*database.BaseRepository[*Entity.AccountDeletion]
+}
+
+func NewDeletionRepository(
+	lg logger.Interface,
+	db *gorm.DB,
+	queryTimeout time.Duration,
+) *DeletionRepository {
+	return &DeletionRepository{
+		BaseRepository: database.NewBaseRepository[*Entity.AccountDeletion](lg, "deletion-repository", db, queryTimeout),
+	}
+}
+
+// GetLatest 获取用户最近一次的注销请求
+func (repo *DeletionRepository) GetLatest(userId uint) (*Entity.AccountDeletion, error) {
+	deletion := &Entity.AccountDeletion{}
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("user_id = ?", userId).
+			Order("id DESC").
+			First(deletion).
+			Error
+	})
+	return deletion, err
+}
+
+func (repo *DeletionRepository) GetByUser(userId uint) (deletions []*Entity.AccountDeletion, err error) {
+	deletions = make([]*Entity.AccountDeletion, 0)
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("user_id = ?", userId).
+			Order("id").
+			Find(&deletions).
+			Error
+	})
+	return
+}
+
+// GetDue 获取宽限期已结束但尚未处理的注销请求
+func (repo *DeletionRepository) GetDue(now time.Time, limit int) (deletions []*Entity.AccountDeletion, err error) {
+	deletions = make([]*Entity.AccountDeletion, 0)
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("status = ? AND scheduled_at <= ?", Entity.DeletionStatusPending, now).
+			Order("scheduled_at").
+			Limit(limit).
+			Find(&deletions).
+			Error
+	})
+	return
+}
+
+func (repo *DeletionRepository) Cancel(deletionId uint) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		return tx.Model(&Entity.AccountDeletion{}).
+			Where("id = ? AND status = ?", deletionId, Entity.DeletionStatusPending).
+			Update("status", Entity.DeletionStatusCancelled).
+			Error
+	})
+}
+
+// Complete 匿名化用户数据并移除其扩展资料、角色与分区, 同时将注销请求标记为已完成
+//
+// 用户记录本身(包括呼号)会被保留, 以保证审计记录仍然可以关联到该用户
+func (repo *DeletionRepository) Complete(deletion *Entity.AccountDeletion, anonymized map[string]interface{}) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		// 先将请求标记为已完成, 并发处理同一请求时后到的事务会在此等待并因状态已改变而放弃
+		now := sql.NullTime{Valid: true, Time: time.Now()}
+		result := tx.Model(&Entity.AccountDeletion{}).
+			Where("id = ? AND status = ?", deletion.ID, Entity.DeletionStatusPending).
+			Updates(map[string]interface{}{
+				"status":       Entity.DeletionStatusCompleted,
+				"completed_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return Repository.ErrDeletionClosed
+		}
+		if err := tx.Model(&entity.User{ID: deletion.UserId}).Updates(anonymized).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity.UserRole{}, "user_id = ?", deletion.UserId).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&Entity.DivisionRole{}, "user_id = ?", deletion.UserId).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&Entity.UserDivision{}, "user_id = ?", deletion.UserId).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&Entity.UserFieldValue{}, "user_id = ?", deletion.UserId).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&Entity.UserProfile{}, "user_id = ?", deletion.UserId).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&Entity.UsernameChange{}, "user_id = ?", deletion.UserId).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&Entity.UserEmail{}, "user_id = ?", deletion.UserId).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&Entity.UserSkeleton{}, "user_id = ?", deletion.UserId).Error; err != nil {
+			return err
+		}
+		// 导出归档包含注销前的完整资料
+		if err := tx.Delete(&Entity.DataExport{}, "user_id = ?", deletion.UserId).Error; err != nil {
+			return err
+		}
+		// 结束该用户作为学员或教员的所有指派
+		err := tx.Model(&Entity.InstructorAssignment{}).
+			Where("(trainee_id = ? OR instructor_id = ?) AND ended_at IS NULL", deletion.UserId, deletion.UserId).
+			Updates(map[string]interface{}{
+				"end_reason": "账户已注销",
+				"ended_at":   sql.NullTime{Valid: true, Time: time.Now()},
+			}).
+			Error
+		if err != nil {
+			return err
+		}
+		// 已上传的头像不再对外提供访问
+		err = tx.Model(&Entity.Avatar{}).
+			Where("user_id = ? AND status = ?", deletion.UserId, Entity.AvatarStatusActive).
+			Updates(map[string]interface{}{
+				"status":     Entity.AvatarStatusRemoved,
+				"removed_at": sql.NullTime{Valid: true, Time: time.Now()},
+			}).
+			Error
+		if err != nil {
+			return err
+		}
+		// 匿名化后原邮箱不再属于该用户, 尚未确认的邮箱修改请求一并取消
+		if err := cancelPendingEmailChanges(tx, deletion.UserId); err != nil {
+			return err
+		}
+		// 已确认的修改请求的撤销链接立即过期, 避免原邮箱被写回已匿名化的用户
+		err = tx.Model(&Entity.EmailChange{}).
+			Where("user_id = ? AND status = ? AND expires_at > ?", deletion.UserId, Entity.EmailChangeStatusConfirmed, now.Time).
+			Update("expires_at", now.Time).
+			Error
+		if err != nil {
+			return err
+		}
+		// 已签发的会话全部失效
+		if err := revokeUserSessions(tx, deletion.UserId); err != nil {
+			return err
+		}
+		deletion.Status = Entity.DeletionStatusCompleted
+		deletion.CompletedAt = now
+		return nil
+	})
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"half-nothing.cn/service-core/database"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type DivisionRepository struct {
+	*database.BaseRepository[*Entity.Division]
+	pageReq database.PageableInterface[*Entity.Division]
+}
+
+func NewDivisionRepository(
+	lg logger.Interface,
+	db *gorm.DB,
+	queryTimeout time.Duration,
+) *DivisionRepository {
+	return &DivisionRepository{
+		BaseRepository: database.NewBaseRepository[*Entity.Division](lg, "division-repository", db, queryTimeout),
+		pageReq:        database.NewPageRequest[*Entity.Division](db),
+	}
+}
+
+func (repo *DivisionRepository) GetPages(pageNum int, pageSize int, search string) (divisions []*Entity.Division, total int64, err error) {
+	divisions = make([]*Entity.Division, 0, pageSize)
+	var queryFunc func(tx *gorm.DB) *gorm.DB
+	if search != "" {
+		queryFunc = func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("code LIKE ? OR name LIKE ?", "%"+search+"%", "%"+search+"%")
+		}
+	} else {
+		queryFunc = nil
+	}
+	total, err = repo.QueryWithPagination(repo.pageReq, database.NewPage[*Entity.Division](pageNum, pageSize, &divisions, &Entity.Division{}, queryFunc))
+	return
+}
+
+func (repo *DivisionRepository) DeleteDivision(divisionId uint) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&Entity.DivisionRole{}, "division_id = ?", divisionId).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&Entity.UserDivision{}, "division_id = ?", divisionId).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&Entity.Division{ID: divisionId}).Error
+	})
+}
+
+func (repo *DivisionRepository) CountMembers(divisionId uint) (total int64, err error) {
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Model(&Entity.UserDivision{}).
+			Where("division_id = ?", divisionId).
+			Count(&total).
+			Error
+	})
+	return
+}
+
+func (repo *DivisionRepository) GetUserDivisionIds(userId uint) (divisionIds []uint, err error) {
+	divisionIds = make([]uint, 0)
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Model(&Entity.UserDivision{}).
+			Where("user_id = ?", userId).
+			Pluck("division_id", &divisionIds).
+			Error
+	})
+	return
+}
+
+func (repo *DivisionRepository) GetUserDivisions(userId uint) (divisions []*Entity.Division, err error) {
+	divisions = make([]*Entity.Division, 0)
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Model(&Entity.Division{}).
+			Joins("JOIN user_divisions ON user_divisions.division_id = divisions.id").
+			Where("user_divisions.user_id = ?", userId).
+			Order("divisions.code").
+			Find(&divisions).
+			Error
+	})
+	return
+}
+
+func (repo *DivisionRepository) AddMembers(divisionId uint, userIds []uint) error {
+	userDivisions := make([]*Entity.UserDivision, len(userIds))
+	for i, userId := range userIds {
+		userDivisions[i] = &Entity.UserDivision{UserId: userId, DivisionId: divisionId}
+	}
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(userDivisions).Error
+	})
+}
+
+func (repo *DivisionRepository) RemoveMembers(divisionId uint, userIds []uint) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&Entity.DivisionRole{}, "division_id = ? AND user_id IN ?", divisionId, userIds).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&Entity.UserDivision{}, "division_id = ? AND user_id IN ?", divisionId, userIds).Error
+	})
+}
+
+func (repo *DivisionRepository) GetScopedRoles(userId uint) (divisionRoles []*Entity.DivisionRole, err error) {
+	divisionRoles = make([]*Entity.DivisionRole, 0)
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Model(&Entity.DivisionRole{}).
+			Where("user_id = ?", userId).
+			Preload("Role").
+			Preload("Division").
+			Find(&divisionRoles).
+			Error
+	})
+	return
+}
+
+func (repo *DivisionRepository) GrantScopedRole(divisionId uint, userId uint, roleIds []uint) error {
+	divisionRoles := make([]*Entity.DivisionRole, len(roleIds))
+	for i, roleId := range roleIds {
+		divisionRoles[i] = &Entity.DivisionRole{UserId: userId, RoleId: roleId, DivisionId: divisionId}
+	}
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(divisionRoles).Error
+	})
+}
+
+func (repo *DivisionRepository) RevokeScopedRole(divisionId uint, userId uint, roleIds []uint) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		return tx.Delete(&Entity.DivisionRole{}, "division_id = ? AND user_id = ? AND role_id IN ?", divisionId, userId, roleIds).Error
+	})
+}
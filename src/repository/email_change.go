@@ -0,0 +1,190 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"database/sql"
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+	Repository "user-service/src/interfaces/repository"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/database"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type EmailChangeRepository struct {
+	*database.BaseRepository[*Entity.EmailChange]
+}
+
+func NewEmailChangeRepository(
+	lg logger.Interface,
+	db *gorm.DB,
+	queryTimeout time.Duration,
+) *EmailChangeRepository {
+	return &EmailChangeRepository{
+		BaseRepository: database.NewBaseRepository[*Entity.EmailChange](lg, "email-change-repository", db, queryTimeout),
+	}
+}
+
+// GetPending 获取用户尚未确认且未过期的邮箱修改请求
+func (repo *EmailChangeRepository) GetPending(userId uint) (*Entity.EmailChange, error) {
+	change := &Entity.EmailChange{}
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("user_id = ? AND status = ? AND expires_at > ?", userId, Entity.EmailChangeStatusPending, time.Now()).
+			Order("id DESC").
+			First(change).
+			Error
+	})
+	return change, err
+}
+
+// GetByUser 获取用户的全部邮箱修改请求, 按请求时间倒序排列
+func (repo *EmailChangeRepository) GetByUser(userId uint) (changes []*Entity.EmailChange, err error) {
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("user_id = ?", userId).Order("id DESC").Find(&changes).Error
+	})
+	return
+}
+
+func (repo *EmailChangeRepository) GetByConfirmToken(tokenHash string) (*Entity.EmailChange, error) {
+	change := &Entity.EmailChange{}
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("confirm_token_hash = ?", tokenHash).First(change).Error
+	})
+	return change, err
+}
+
+func (repo *EmailChangeRepository) GetByRevertToken(tokenHash string) (*Entity.EmailChange, error) {
+	change := &Entity.EmailChange{}
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("revert_token_hash = ?", tokenHash).First(change).Error
+	})
+	return change, err
+}
+
+func (repo *EmailChangeRepository) Request(change *Entity.EmailChange) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		if err := cancelPendingEmailChanges(tx, change.UserId); err != nil {
+			return err
+		}
+		return tx.Create(change).Error
+	})
+}
+
+func (repo *EmailChangeRepository) Confirm(change *Entity.EmailChange, canonicalEmail string) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		now := sql.NullTime{Valid: true, Time: time.Now()}
+		result := tx.Model(&Entity.EmailChange{}).
+			Where("id = ? AND status = ?", change.ID, Entity.EmailChangeStatusPending).
+			Updates(map[string]interface{}{
+				"status":       Entity.EmailChangeStatusConfirmed,
+				"confirmed_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return Repository.ErrEmailChangeClosed
+		}
+		result = tx.Model(&entity.User{}).
+			Where("id = ? AND email = ?", change.UserId, change.OldEmail).
+			Update("email", change.NewEmail)
+		if result.Error != nil {
+			if isDuplicateKey(result.Error) {
+				return Repository.ErrDuplicated
+			}
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return Repository.ErrEmailChanged
+		}
+		if err := saveUserEmail(tx, change.UserId, canonicalEmail); err != nil {
+			return err
+		}
+		change.Status = Entity.EmailChangeStatusConfirmed
+		change.ConfirmedAt = now
+		return nil
+	})
+}
+
+func (repo *EmailChangeRepository) Cancel(change *Entity.EmailChange, revokeSessions bool) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		if err := closeEmailChange(tx, change, Entity.EmailChangeStatusPending, Entity.EmailChangeStatusCancelled); err != nil {
+			return err
+		}
+		if revokeSessions {
+			return revokeUserSessions(tx, change.UserId)
+		}
+		return nil
+	})
+}
+
+// Revert 原邮箱的持有者已通过撤销链接证明身份, 因此无论用户当前邮箱是什么都会恢复为原邮箱,
+// 并取消该用户其他尚未确认的修改请求, 用户已注销时返回 ErrEmailChangeClosed
+func (repo *EmailChangeRepository) Revert(change *Entity.EmailChange, canonicalEmail string) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		var deleted int64
+		err := tx.Model(&Entity.AccountDeletion{}).
+			Where("user_id = ? AND status = ?", change.UserId, Entity.DeletionStatusCompleted).
+			Count(&deleted).
+			Error
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			return Repository.ErrEmailChangeClosed
+		}
+		if err := closeEmailChange(tx, change, Entity.EmailChangeStatusConfirmed, Entity.EmailChangeStatusReverted); err != nil {
+			return err
+		}
+		err = tx.Model(&entity.User{ID: change.UserId}).Update("email", change.OldEmail).Error
+		if err != nil {
+			if isDuplicateKey(err) {
+				return Repository.ErrDuplicated
+			}
+			return err
+		}
+		if err := saveUserEmail(tx, change.UserId, canonicalEmail); err != nil {
+			return err
+		}
+		if err := cancelPendingEmailChanges(tx, change.UserId); err != nil {
+			return err
+		}
+		return revokeUserSessions(tx, change.UserId)
+	})
+}
+
+// closeEmailChange 将处于 from 状态的请求改为 to 状态
+func closeEmailChange(tx *gorm.DB, change *Entity.EmailChange, from string, to string) error {
+	now := sql.NullTime{Valid: true, Time: time.Now()}
+	result := tx.Model(&Entity.EmailChange{}).
+		Where("id = ? AND status = ?", change.ID, from).
+		Updates(map[string]interface{}{
+			"status":    to,
+			"closed_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return Repository.ErrEmailChangeClosed
+	}
+	change.Status = to
+	change.ClosedAt = now
+	return nil
+}
+
+// cancelPendingEmailChanges 取消用户所有尚未确认的邮箱修改请求
+func cancelPendingEmailChanges(tx *gorm.DB, userId uint) error {
+	return tx.Model(&Entity.EmailChange{}).
+		Where("user_id = ? AND status = ?", userId, Entity.EmailChangeStatusPending).
+		Updates(map[string]interface{}{
+			"status":    Entity.EmailChangeStatusCancelled,
+			"closed_at": sql.NullTime{Valid: true, Time: time.Now()},
+		}).
+		Error
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"database/sql"
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/database"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type ExportRepository struct {
+	*database.BaseRepository[*Entity.DataExport]
+}
+
+func NewExportRepository(
+	lg logger.Interface,
+	db *gorm.DB,
+	queryTimeout time.Duration,
+) *ExportRepository {
+	return &ExportRepository{
+		BaseRepository: database.NewBaseRepository[*Entity.DataExport](lg, "export-repository", db, queryTimeout),
+	}
+}
+
+// GetLatest 获取用户最近一次的导出任务, 不加载归档内容
+func (repo *ExportRepository) GetLatest(userId uint) (*Entity.DataExport, error) {
+	export := &Entity.DataExport{}
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Omit("data").
+			Where("user_id = ?", userId).
+			Order("id DESC").
+			First(export).
+			Error
+	})
+	return export, err
+}
+
+func (repo *ExportRepository) GetWithData(exportId uint) (*Entity.DataExport, error) {
+	export := &Entity.DataExport{ID: exportId}
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.First(export).Error
+	})
+	return export, err
+}
+
+// Complete 保存生成的归档, 并清除该用户更早导出任务的归档内容
+func (repo *ExportRepository) Complete(exportId uint, data []byte, expiresAt time.Time) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		export := &Entity.DataExport{ID: exportId}
+		if err := tx.Omit("data").First(export).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&Entity.DataExport{}).
+			Where("user_id = ? AND id < ?", export.UserId, exportId).
+			Update("data", nil).
+			Error; err != nil {
+			return err
+		}
+		return tx.Model(&Entity.DataExport{}).
+			Where("id = ?", exportId).
+			Updates(map[string]interface{}{
+				"status":       Entity.ExportStatusCompleted,
+				"data":         data,
+				"completed_at": sql.NullTime{Valid: true, Time: time.Now()},
+				"expires_at":   sql.NullTime{Valid: true, Time: expiresAt},
+			}).
+			Error
+	})
+}
+
+// PurgeExpired 清除已过期导出任务的归档内容, 任务记录保留用于频率限制, 返回清除的数量
+func (repo *ExportRepository) PurgeExpired(now time.Time) (int64, error) {
+	var purged int64
+	err := repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Entity.DataExport{}).
+			Where("expires_at < ? AND data IS NOT NULL", now).
+			Update("data", nil)
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
+
+func (repo *ExportRepository) Fail(exportId uint) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		return tx.Model(&Entity.DataExport{}).
+			Where("id = ?", exportId).
+			Update("status", Entity.ExportStatusFailed).
+			Error
+	})
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"database/sql"
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+	Repository "user-service/src/interfaces/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"half-nothing.cn/service-core/database"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type InstructorRepository struct {
+	*database.BaseRepository[*Entity.InstructorAssignment]
+}
+
+func NewInstructorRepository(
+	lg logger.Interface,
+	db *gorm.DB,
+	queryTimeout time.Duration,
+) *InstructorRepository {
+	return &InstructorRepository{
+		BaseRepository: database.NewBaseRepository[*Entity.InstructorAssignment](lg, "instructor-repository", db, queryTimeout),
+	}
+}
+
+// GetCurrent 获取学员当前的教员指派
+func (repo *InstructorRepository) GetCurrent(traineeId uint) (*Entity.InstructorAssignment, error) {
+	assignment := &Entity.InstructorAssignment{}
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("trainee_id = ? AND ended_at IS NULL", traineeId).First(assignment).Error
+	})
+	return assignment, err
+}
+
+// GetByTrainee 获取学员的教员指派历史, 按指派时间倒序排列
+func (repo *InstructorRepository) GetByTrainee(traineeId uint) (assignments []*Entity.InstructorAssignment, err error) {
+	assignments = make([]*Entity.InstructorAssignment, 0)
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("trainee_id = ?", traineeId).Order("id DESC").Find(&assignments).Error
+	})
+	return
+}
+
+// GetTrainees 获取教员当前指导的学员
+func (repo *InstructorRepository) GetTrainees(instructorId uint) (assignments []*Entity.InstructorAssignment, err error) {
+	assignments = make([]*Entity.InstructorAssignment, 0)
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("instructor_id = ? AND ended_at IS NULL", instructorId).Order("id").Find(&assignments).Error
+	})
+	return
+}
+
+// Assign 结束学员当前的指派并创建新的指派
+//
+// 按 ID 顺序锁定学员与教员的用户记录, 同一学员或同一教员的指派依次进行, 避免产生多个未结束的指派或超出学员上限
+func (repo *InstructorRepository) Assign(assignment *Entity.InstructorAssignment, maxTrainees int) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		var userIds []uint
+		err := tx.Model(&entity.User{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", []uint{assignment.TraineeId, assignment.InstructorId}).
+			Order("id").
+			Pluck("id", &userIds).
+			Error
+		if err != nil {
+			return err
+		}
+		if maxTrainees > 0 {
+			var count int64
+			err := tx.Model(&Entity.InstructorAssignment{}).
+				Where("instructor_id = ? AND trainee_id <> ? AND ended_at IS NULL", assignment.InstructorId, assignment.TraineeId).
+				Count(&count).
+				Error
+			if err != nil {
+				return err
+			}
+			if count >= int64(maxTrainees) {
+				return Repository.ErrInstructorFull
+			}
+		}
+		err = tx.Model(&Entity.InstructorAssignment{}).
+			Where("trainee_id = ? AND ended_at IS NULL", assignment.TraineeId).
+			Updates(map[string]interface{}{
+				"ended_by":   assignment.AssignedBy,
+				"end_reason": assignment.Reason,
+				"ended_at":   sql.NullTime{Valid: true, Time: time.Now()},
+			}).
+			Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(assignment).Error
+	})
+}
+
+func (repo *InstructorRepository) Unassign(assignment *Entity.InstructorAssignment, operatorId uint, reason string) error {
+	return repo.Query(func(tx *gorm.DB) error {
+		return tx.Model(assignment).Updates(map[string]interface{}{
+			"ended_by":   operatorId,
+			"end_reason": reason,
+			"ended_at":   sql.NullTime{Valid: true, Time: time.Now()},
+		}).Error
+	})
+}
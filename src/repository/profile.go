@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"half-nothing.cn/service-core/database"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type ProfileRepository struct {
+	*database.BaseRepository[*Entity.UserProfile]
+}
+
+func NewProfileRepository(
+	lg logger.Interface,
+	db *gorm.DB,
+	queryTimeout time.Duration,
+) *ProfileRepository {
+	return &ProfileRepository{
+		BaseRepository: database.NewBaseRepository[*Entity.UserProfile](lg, "profile-repository", db, queryTimeout),
+	}
+}
+
+func (repo *ProfileRepository) GetByUserId(userId uint) (*Entity.UserProfile, error) {
+	profile := &Entity.UserProfile{}
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Preload("Division").
+			Preload("Fields.Field").
+			Where("user_id = ?", userId).
+			First(profile).
+			Error
+	})
+	return profile, err
+}
+
+// GetByUserIds 批量获取用户扩展资料, 返回用户 ID 到资料的映射, 没有资料的用户不在映射中
+func (repo *ProfileRepository) GetByUserIds(userIds []uint) (map[uint]*Entity.UserProfile, error) {
+	profiles := make([]*Entity.UserProfile, 0, len(userIds))
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Preload("Division").
+			Preload("Fields.Field").
+			Where("user_id IN ?", userIds).
+			Find(&profiles).
+			Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint]*Entity.UserProfile, len(profiles))
+	for _, profile := range profiles {
+		result[profile.UserId] = profile
+	}
+	return result, nil
+}
+
+// updateProfile 在事务中更新用户扩展资料与自定义字段值, 资料不存在时先创建
+//
+// fieldValues 为字段 ID 到字段值的映射, 值为空字符串时删除该字段值
+func updateProfile(tx *gorm.DB, userId uint, updates map[string]interface{}, fieldValues map[uint]string) error {
+	profile := &Entity.UserProfile{}
+	if err := tx.Where(Entity.UserProfile{UserId: userId}).FirstOrCreate(profile).Error; err != nil {
+		return err
+	}
+	if len(updates) > 0 {
+		if err := tx.Model(profile).Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+	for fieldId, value := range fieldValues {
+		if value == "" {
+			if err := tx.Delete(&Entity.UserFieldValue{}, "user_id = ? AND field_id = ?", userId, fieldId).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "field_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+		}).Create(&Entity.UserFieldValue{UserId: userId, FieldId: fieldId, Value: value}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+	Repository "user-service/src/interfaces/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"half-nothing.cn/service-core/database"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type ProfileFieldRepository struct {
+	*database.BaseRepository[*Entity.ProfileField]
+}
+
+func NewProfileFieldRepository(
+	lg logger.Interface,
+	db *gorm.DB,
+	queryTimeout time.Duration,
+) *ProfileFieldRepository {
+	return &ProfileFieldRepository{
+		BaseRepository: database.NewBaseRepository[*Entity.ProfileField](lg, "profile-field-repository", db, queryTimeout),
+	}
+}
+
+func (repo *ProfileFieldRepository) GetAll() (fields []*Entity.ProfileField, err error) {
+	fields = make([]*Entity.ProfileField, 0)
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Order("id").Find(&fields).Error
+	})
+	return
+}
+
+func (repo *ProfileFieldRepository) GetByKey(key string) (*Entity.ProfileField, error) {
+	field := &Entity.ProfileField{}
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Where(&Entity.ProfileField{Key: key}).First(field).Error
+	})
+	return field, err
+}
+
+func (repo *ProfileFieldRepository) UpdateField(field *Entity.ProfileField, check func(value string) bool) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		if check != nil {
+			values := make([]string, 0)
+			err := tx.Model(&Entity.UserFieldValue{}).
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("field_id = ?", field.ID).
+				Pluck("value", &values).
+				Error
+			if err != nil {
+				return err
+			}
+			for _, value := range values {
+				if !check(value) {
+					return Repository.ErrFieldValuesMismatch
+				}
+			}
+		}
+		return tx.Save(field).Error
+	})
+}
+
+// DeleteField 删除字段及所有用户的字段值
+func (repo *ProfileFieldRepository) DeleteField(fieldId uint) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&Entity.UserFieldValue{}, "field_id = ?", fieldId).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&Entity.ProfileField{ID: fieldId}).Error
+	})
+}
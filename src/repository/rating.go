@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+	Repository "user-service/src/interfaces/repository"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/database"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type RatingRepository struct {
+	*database.BaseRepository[*Entity.RatingChange]
+}
+
+func NewRatingRepository(
+	lg logger.Interface,
+	db *gorm.DB,
+	queryTimeout time.Duration,
+) *RatingRepository {
+	return &RatingRepository{
+		BaseRepository: database.NewBaseRepository[*Entity.RatingChange](lg, "rating-repository", db, queryTimeout),
+	}
+}
+
+// GetByUser 获取用户的等级变更记录, 按变更时间倒序排列
+func (repo *RatingRepository) GetByUser(userId uint) (changes []*Entity.RatingChange, err error) {
+	changes = make([]*Entity.RatingChange, 0)
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("user_id = ?", userId).Order("id DESC").Find(&changes).Error
+	})
+	return
+}
+
+func (repo *RatingRepository) GetLatest(userId uint) (*Entity.RatingChange, error) {
+	change := &Entity.RatingChange{}
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("user_id = ?", userId).Order("id DESC").First(change).Error
+	})
+	return change, err
+}
+
+// ChangeRating 修改用户等级并保存变更记录
+// 仅当用户当前等级仍为 change.OldRating 时才会修改, 否则返回 ErrRatingChanged
+func (repo *RatingRepository) ChangeRating(change *Entity.RatingChange) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.User{}).
+			Where("id = ? AND rating = ?", change.UserId, change.OldRating).
+			Update("rating", change.NewRating)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return Repository.ErrRatingChanged
+		}
+		return tx.Create(change).Error
+	})
+}
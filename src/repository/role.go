@@ -5,7 +5,10 @@
 package repository
 
 import (
+	"slices"
 	"time"
+	Entity "user-service/src/interfaces/database/entity"
+	"user-service/src/interfaces/repository"
 
 	"gorm.io/gorm"
 	"half-nothing.cn/service-core/database"
@@ -62,12 +65,44 @@ func (repo *RoleRepository) GetRoleUsers(roleId uint) (userRoles []*entity.UserR
 	return
 }
 
-func (repo *RoleRepository) DeleteRole(roleId uint) error {
+func (repo *RoleRepository) CountRoleUsers(roleId uint) (count int64, err error) {
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Model(&entity.UserRole{}).Where("role_id = ?", roleId).Count(&count).Error
+	})
+	return
+}
+
+// DeleteRole 删除角色及其所有授予记录, replacementId 不为 0 时先将成员转移到替代角色
+func (repo *RoleRepository) DeleteRole(roleId uint, replacementId uint) error {
 	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		if replacementId != 0 {
+			memberIds := make([]uint, 0)
+			err := tx.Model(&entity.UserRole{}).
+				Where("role_id = ? AND user_id NOT IN (?)", roleId,
+					tx.Model(&entity.UserRole{}).Select("user_id").Where("role_id = ?", replacementId)).
+				Pluck("user_id", &memberIds).
+				Error
+			if err != nil {
+				return err
+			}
+			if len(memberIds) > 0 {
+				userRoles := make([]*entity.UserRole, len(memberIds))
+				for i, userId := range memberIds {
+					userRoles[i] = &entity.UserRole{UserId: userId, RoleId: replacementId}
+				}
+				if err := tx.Create(userRoles).Error; err != nil {
+					return err
+				}
+			}
+		}
 		err := tx.Delete(&entity.UserRole{}, "role_id = ?", roleId).Error
 		if err != nil {
 			return err
 		}
+		err = tx.Delete(&Entity.DivisionRole{}, "role_id = ?", roleId).Error
+		if err != nil {
+			return err
+		}
 		return tx.Delete(&entity.Role{ID: roleId}).Error
 	})
 }
@@ -80,18 +115,107 @@ func (repo *RoleRepository) GetByIds(roleIds []uint) (roles []*entity.Role, err
 	return
 }
 
+// GrantUser 将角色授予用户, 已持有该角色的用户会被跳过
 func (repo *RoleRepository) GrantUser(roleId uint, userIds []uint) error {
-	userRoles := make([]*entity.UserRole, len(userIds))
-	for i, userId := range userIds {
-		userRoles[i] = &entity.UserRole{UserId: userId, RoleId: roleId}
-	}
 	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
-		return tx.Create(userRoles).Error
+		return grantUsers(tx, roleId, userIds)
 	})
 }
 
+func grantUsers(tx *gorm.DB, roleId uint, userIds []uint) error {
+	existing := make([]uint, 0)
+	if err := tx.Model(&entity.UserRole{}).Where("role_id = ?", roleId).Pluck("user_id", &existing).Error; err != nil {
+		return err
+	}
+	userRoles := make([]*entity.UserRole, 0, len(userIds))
+	for _, userId := range userIds {
+		if !slices.Contains(existing, userId) {
+			existing = append(existing, userId)
+			userRoles = append(userRoles, &entity.UserRole{UserId: userId, RoleId: roleId})
+		}
+	}
+	if len(userRoles) == 0 {
+		return nil
+	}
+	return tx.Create(userRoles).Error
+}
+
 func (repo *RoleRepository) RevokeUser(roleId uint, userIds []uint) error {
 	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
 		return tx.Delete(&entity.UserRole{}, "role_id = ? AND user_id IN ?", roleId, userIds).Error
 	})
 }
+
+// SyncUsers 在同一个事务中为角色添加与移除成员
+func (repo *RoleRepository) SyncUsers(roleId uint, addIds []uint, removeIds []uint) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		if len(removeIds) > 0 {
+			if err := tx.Delete(&entity.UserRole{}, "role_id = ? AND user_id IN ?", roleId, removeIds).Error; err != nil {
+				return err
+			}
+		}
+		if len(addIds) == 0 {
+			return nil
+		}
+		return grantUsers(tx, roleId, addIds)
+	})
+}
+
+func (repo *RoleRepository) GetAll() (roles []*entity.Role, err error) {
+	roles = make([]*entity.Role, 0)
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Order("name").Find(&roles).Error
+	})
+	return
+}
+
+func (repo *RoleRepository) GetAllUserRoles() (userRoles []*entity.UserRole, err error) {
+	userRoles = make([]*entity.UserRole, 0)
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Model(&entity.UserRole{}).
+			Joins("User").
+			Find(&userRoles).
+			Error
+	})
+	return
+}
+
+// ImportRoles 在同一个事务中创建或更新角色、同步角色成员并删除角色
+func (repo *RoleRepository) ImportRoles(upserts []*repository.RoleImport, removeIds []uint) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		for _, upsert := range upserts {
+			if upsert.Role.ID == 0 {
+				if err := tx.Create(upsert.Role).Error; err != nil {
+					return err
+				}
+			} else {
+				if err := tx.Model(upsert.Role).Select("Name", "Comment", "Permission").Updates(upsert.Role).Error; err != nil {
+					return err
+				}
+			}
+			if upsert.Members == nil {
+				continue
+			}
+			query := tx.Where("role_id = ?", upsert.Role.ID)
+			if len(upsert.Members) > 0 {
+				query = query.Where("user_id NOT IN ?", upsert.Members)
+			}
+			if err := query.Delete(&entity.UserRole{}).Error; err != nil {
+				return err
+			}
+			if err := grantUsers(tx, upsert.Role.ID, upsert.Members); err != nil {
+				return err
+			}
+		}
+		if len(removeIds) == 0 {
+			return nil
+		}
+		if err := tx.Delete(&entity.UserRole{}, "role_id IN ?", removeIds).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&Entity.DivisionRole{}, "role_id IN ?", removeIds).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Role{}, removeIds).Error
+	})
+}
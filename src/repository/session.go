@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"errors"
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"half-nothing.cn/service-core/database"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type SessionRepository struct {
+	*database.BaseRepository[*Entity.SessionRevocation]
+}
+
+func NewSessionRepository(
+	lg logger.Interface,
+	db *gorm.DB,
+	queryTimeout time.Duration,
+) *SessionRepository {
+	return &SessionRepository{
+		BaseRepository: database.NewBaseRepository[*Entity.SessionRevocation](lg, "session-repository", db, queryTimeout),
+	}
+}
+
+func (repo *SessionRepository) GetRevokedAt(userId uint) (time.Time, error) {
+	revocation := &Entity.SessionRevocation{}
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("user_id = ?", userId).First(revocation).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	return revocation.RevokedAt, err
+}
+
+func (repo *SessionRepository) Revoke(userId uint) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		return revokeUserSessions(tx, userId)
+	})
+}
+
+func (repo *SessionRepository) IsPasswordChangeRequired(userId uint) (bool, error) {
+	var count int64
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Model(&Entity.PasswordChangeRequirement{}).Where("user_id = ?", userId).Count(&count).Error
+	})
+	return count > 0, err
+}
+
+// requirePasswordChange 在事务中要求用户修改密码, 并使用户已签发的会话全部失效
+func requirePasswordChange(tx *gorm.DB, requirement *Entity.PasswordChangeRequirement) error {
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"operator_cid", "created_at"}),
+	}).Create(requirement).Error
+	if err != nil {
+		return err
+	}
+	return revokeUserSessions(tx, requirement.UserId)
+}
+
+func (repo *SessionRepository) ClearPasswordChange(userId uint) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		return tx.Delete(&Entity.PasswordChangeRequirement{}, "user_id = ?", userId).Error
+	})
+}
+
+// revokeUserSessions 在事务中记录用户会话失效的时间
+func revokeUserSessions(tx *gorm.DB, userId uint) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_at"}),
+	}).Create(&Entity.SessionRevocation{UserId: userId, RevokedAt: time.Now()}).Error
+}
@@ -3,15 +3,169 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
+	Entity "user-service/src/interfaces/database/entity"
+	Repository "user-service/src/interfaces/repository"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"half-nothing.cn/service-core/database"
 	"half-nothing.cn/service-core/interfaces/database/entity"
 	"half-nothing.cn/service-core/interfaces/database/repository"
 	"half-nothing.cn/service-core/interfaces/logger"
 )
 
+// mysqlDuplicateEntry MySQL 违反唯一约束时的错误码
+const mysqlDuplicateEntry = "Error 1062"
+
+// isDuplicateKey 判断错误是否由违反唯一约束引起
+func isDuplicateKey(err error) bool {
+	return errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), mysqlDuplicateEntry)
+}
+
+// userCaseInsensitiveIndexes 用户名与邮箱不区分大小写的唯一索引
+var userCaseInsensitiveIndexes = map[string]string{
+	"idx_users_username_ci": "username",
+	"idx_users_email_ci":    "email",
+}
+
+// UserDuplicate 忽略大小写后相同的用户名或邮箱
+type UserDuplicate struct {
+	Column string
+	Value  string
+	Cids   []uint
+}
+
+// findCaseDuplicates 查找忽略大小写后相同的列值及使用该值的用户呼号
+func findCaseDuplicates(db *gorm.DB, column string) ([]*UserDuplicate, error) {
+	var values []string
+	err := db.Model(&entity.User{}).
+		Select(fmt.Sprintf("LOWER(%s)", column)).
+		Group(fmt.Sprintf("LOWER(%s)", column)).
+		Having("COUNT(*) > 1").
+		Pluck(fmt.Sprintf("LOWER(%s)", column), &values).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	duplicates := make([]*UserDuplicate, 0, len(values))
+	for _, value := range values {
+		duplicate := &UserDuplicate{Column: column, Value: value}
+		err := db.Model(&entity.User{}).
+			Where(fmt.Sprintf("LOWER(%s) = ?", column), value).
+			Order("cid").
+			Pluck("cid", &duplicate.Cids).
+			Error
+		if err != nil {
+			return nil, err
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+	return duplicates, nil
+}
+
+// userCheckTables 启动检查依赖的表
+var userCheckTables = []interface{}{&Entity.UserEmail{}, &Entity.UserSkeleton{}, &Entity.AccountDeletion{}}
+
+// EnsureUserTables 创建启动检查依赖但尚不存在的表, 已存在的表不做修改
+//
+// 每次启动时在其他检查之前执行, 未开启自动迁移的数据库升级后也可以正常启动
+func EnsureUserTables(db *gorm.DB) error {
+	for _, table := range userCheckTables {
+		if db.Migrator().HasTable(table) {
+			continue
+		}
+		if err := db.Migrator().CreateTable(table); err != nil {
+			// 多个实例同时启动时表可能已由其他实例创建
+			if db.Migrator().HasTable(table) {
+				continue
+			}
+			return fmt.Errorf("fail to create table for %T: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// EnsureUserIndexes 创建用户名与邮箱不区分大小写的唯一索引, 需要 MySQL 8.0.13 及以上版本
+//
+// 每次启动时执行, 已有数据中存在忽略大小写后重复的值时不创建对应索引并返回重复的值,
+// 管理员修改重复的用户名或邮箱后, 下次启动时会重新尝试创建
+func EnsureUserIndexes(db *gorm.DB) ([]*UserDuplicate, error) {
+	result := make([]*UserDuplicate, 0)
+	for name, column := range userCaseInsensitiveIndexes {
+		if db.Migrator().HasIndex(&entity.User{}, name) {
+			continue
+		}
+		duplicates, err := findCaseDuplicates(db, column)
+		if err != nil {
+			return nil, fmt.Errorf("fail to find duplicated %s: %w", column, err)
+		}
+		if len(duplicates) > 0 {
+			result = append(result, duplicates...)
+			continue
+		}
+		if err := db.Exec(fmt.Sprintf("CREATE UNIQUE INDEX %s ON users ((LOWER(%s)))", name, column)).Error; err != nil {
+			// 多个实例同时启动时索引可能已由其他实例创建
+			if db.Migrator().HasIndex(&entity.User{}, name) {
+				continue
+			}
+			return nil, fmt.Errorf("fail to create index %s: %w", name, err)
+		}
+	}
+	return result, nil
+}
+
+// EnsureUserEmails 为尚未保存规范化邮箱的用户补充记录
+//
+// 每次启动时执行, 规范化后与其他用户重复的邮箱会被跳过并返回, Cids 中第一个为已占用该邮箱的用户,
+// 管理员修改跳过的用户的邮箱后, 下次启动时会重新补充
+func EnsureUserEmails(db *gorm.DB, canonical func(email string) string) (skipped []*UserDuplicate, err error) {
+	skipped = make([]*UserDuplicate, 0)
+	var users []*entity.User
+	err = db.Select("id", "cid", "email").
+		Where("id NOT IN (SELECT user_id FROM user_emails)").
+		FindInBatches(&users, 500, func(tx *gorm.DB, batch int) error {
+			for _, user := range users {
+				value := canonical(user.Email)
+				err := saveUserEmail(db, user.ID, value)
+				if err == nil {
+					continue
+				}
+				if !errors.Is(err, Repository.ErrDuplicated) {
+					return err
+				}
+				var owner uint
+				err = db.Model(&entity.User{}).
+					Where("id = (SELECT user_id FROM user_emails WHERE canonical = ?)", value).
+					Pluck("cid", &owner).
+					Error
+				if err != nil {
+					return err
+				}
+				skipped = append(skipped, &UserDuplicate{Column: "email", Value: value, Cids: []uint{owner, user.Cid}})
+			}
+			return nil
+		}).
+		Error
+	return
+}
+
+// saveUserEmail 保存用户邮箱的规范化形式, 已被其他用户使用时返回 ErrDuplicated
+func saveUserEmail(tx *gorm.DB, userId uint, canonical string) error {
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"canonical"}),
+	}).Create(&Entity.UserEmail{UserId: userId, Canonical: canonical}).Error
+	if err != nil && isDuplicateKey(err) {
+		return Repository.ErrDuplicated
+	}
+	return err
+}
+
 type UserRepository struct {
 	*database.BaseRepository[*entity.User]
 	pageReq database.PageableInterface[*entity.User]
@@ -82,7 +236,7 @@ func (repo *UserRepository) CheckCidUsernameAndEmail(cid uint, username string,
 	var count int64
 	err := repo.Query(func(tx *gorm.DB) error {
 		return tx.Model(&entity.User{}).
-			Where("users.cid = ? OR users.username = ? OR users.email = ?", cid, username, email).
+			Where("users.cid = ? OR LOWER(users.username) = LOWER(?) OR LOWER(users.email) = LOWER(?)", cid, username, email).
 			Count(&count).Error
 	})
 	if err != nil {
@@ -91,24 +245,102 @@ func (repo *UserRepository) CheckCidUsernameAndEmail(cid uint, username string,
 	return count == 0, err
 }
 
-func (repo *UserRepository) GetPages(pageNum int, pageSize int, search string) (users []*entity.User, total int64, err error) {
+// FindConflict 查找与给定呼号、用户名或邮箱重复的用户, 用户名与邮箱不区分大小写, 空值不参与比较
+func (repo *UserRepository) FindConflict(cid uint, username string, email string) (Repository.UserConflict, error) {
+	users := make([]*entity.User, 0)
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Select("cid", "username", "email").
+			Where("(users.cid = ? AND ? > 0) OR (LOWER(users.username) = LOWER(?) AND ? <> '') OR (LOWER(users.email) = LOWER(?) AND ? <> '')",
+				cid, cid, username, username, email, email).
+			Find(&users).Error
+	})
+	if err != nil {
+		return Repository.UserConflictNone, err
+	}
+	conflict := Repository.UserConflictNone
+	for _, user := range users {
+		switch {
+		case cid > 0 && user.Cid == cid:
+			return Repository.UserConflictCid, nil
+		case username != "" && strings.EqualFold(user.Username, username):
+			conflict = Repository.UserConflictUsername
+		case email != "" && strings.EqualFold(user.Email, email) && conflict == Repository.UserConflictNone:
+			conflict = Repository.UserConflictEmail
+		}
+	}
+	return conflict, nil
+}
+
+func (repo *UserRepository) IsEmailUsed(canonical string, excludeUserId uint) (used bool, err error) {
+	err = repo.Query(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&Entity.UserEmail{}).
+			Where("canonical = ? AND user_id <> ?", canonical, excludeUserId).
+			Count(&count).
+			Error
+		used = count > 0
+		return err
+	})
+	return
+}
+
+// UpdateWithProfile 在同一事务中修改用户信息、邮箱的规范化形式、用户名修改记录与扩展资料
+func (repo *UserRepository) UpdateWithProfile(user *entity.User, update *Repository.UserUpdate) error {
+	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		if len(update.Updates) > 0 {
+			if err := tx.Model(user).Updates(update.Updates).Error; err != nil {
+				if isDuplicateKey(err) {
+					return Repository.ErrDuplicated
+				}
+				return err
+			}
+		}
+		if update.CanonicalEmail != "" {
+			if err := saveUserEmail(tx, user.ID, update.CanonicalEmail); err != nil {
+				return err
+			}
+		}
+		if update.UsernameChange != nil {
+			if err := tx.Create(update.UsernameChange).Error; err != nil {
+				return err
+			}
+			if err := saveUserSkeleton(tx, user.ID, update.UsernameChange.NewUsername); err != nil {
+				return err
+			}
+		}
+		if update.PasswordChange != nil {
+			if err := requirePasswordChange(tx, update.PasswordChange); err != nil {
+				return err
+			}
+		} else if update.RevokeSessions {
+			if err := revokeUserSessions(tx, user.ID); err != nil {
+				return err
+			}
+		}
+		if len(update.Profile) > 0 || len(update.FieldValues) > 0 {
+			return updateProfile(tx, user.ID, update.Profile, update.FieldValues)
+		}
+		return nil
+	})
+}
+
+func (repo *UserRepository) GetPages(pageNum int, pageSize int, search string, divisionIds []uint, fieldFilter *Repository.FieldFilter) (users []*entity.User, total int64, err error) {
 	users = make([]*entity.User, 0, pageSize)
-	var queryFunc func(tx *gorm.DB) *gorm.DB
-	if search != "" {
-		queryFunc = func(tx *gorm.DB) *gorm.DB {
-			return tx.Where("username LIKE ? OR email LIKE ?", "%"+search+"%", "%"+search+"%").
-				Preload("Roles").
-				Preload("Roles.Role").
-				Joins("CurrentAvatar").
-				Order("users.cid")
-		}
-	} else {
-		queryFunc = func(tx *gorm.DB) *gorm.DB {
-			return tx.Preload("Roles").
-				Preload("Roles.Role").
-				Joins("CurrentAvatar").
-				Order("users.cid")
+	queryFunc := func(tx *gorm.DB) *gorm.DB {
+		if search != "" {
+			tx = tx.Where("username LIKE ? OR email LIKE ?", "%"+search+"%", "%"+search+"%")
+		}
+		// divisionIds 为 nil 时不限制分区
+		if divisionIds != nil {
+			tx = tx.Where("users.id IN (SELECT user_id FROM user_divisions WHERE division_id IN ?)", divisionIds)
 		}
+		if fieldFilter != nil {
+			tx = tx.Where("users.id IN (SELECT user_id FROM user_field_values WHERE field_id = ? AND value = ?)", fieldFilter.FieldId, fieldFilter.Value)
+		}
+		return tx.Preload("Roles").
+			Preload("Roles.Role").
+			Joins("CurrentAvatar").
+			Order("users.cid")
 	}
 	page := database.NewPage[*entity.User](pageNum, pageSize, &users, &entity.User{}, queryFunc)
 	page.SetCountColumn("users.id")
@@ -116,12 +348,39 @@ func (repo *UserRepository) GetPages(pageNum int, pageSize int, search string) (
 	return
 }
 
-func (repo *UserRepository) GrantRole(userId uint, roleIds []uint) error {
-	userRoles := make([]*entity.UserRole, len(roleIds))
-	for i, roleId := range roleIds {
-		userRoles[i] = &entity.UserRole{UserId: userId, RoleId: roleId}
+func (repo *UserRepository) GetRoleUserPages(roleId uint, pageNum int, pageSize int, search string) (users []*entity.User, total int64, err error) {
+	users = make([]*entity.User, 0, pageSize)
+	queryFunc := func(tx *gorm.DB) *gorm.DB {
+		tx = tx.Where("users.id IN (SELECT user_id FROM user_roles WHERE role_id = ?)", roleId)
+		if search != "" {
+			tx = tx.Where("users.username LIKE ? OR users.email LIKE ?", "%"+search+"%", "%"+search+"%")
+		}
+		return tx.Joins("CurrentAvatar").
+			Order("users.cid")
 	}
+	page := database.NewPage[*entity.User](pageNum, pageSize, &users, &entity.User{}, queryFunc)
+	page.SetCountColumn("users.id")
+	total, err = repo.QueryWithPagination(repo.pageReq, page)
+	return
+}
+
+// GrantRole 授予用户角色, 用户已持有的角色会被跳过
+func (repo *UserRepository) GrantRole(userId uint, roleIds []uint) error {
 	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
+		existing := make([]uint, 0)
+		if err := tx.Model(&entity.UserRole{}).Where("user_id = ?", userId).Pluck("role_id", &existing).Error; err != nil {
+			return err
+		}
+		userRoles := make([]*entity.UserRole, 0, len(roleIds))
+		for _, roleId := range roleIds {
+			if !slices.Contains(existing, roleId) {
+				existing = append(existing, roleId)
+				userRoles = append(userRoles, &entity.UserRole{UserId: userId, RoleId: roleId})
+			}
+		}
+		if len(userRoles) == 0 {
+			return nil
+		}
 		return tx.Create(userRoles).Error
 	})
 }
@@ -140,6 +399,52 @@ func (repo *UserRepository) GetByIds(userIds []uint) (users []*entity.User, err
 	return
 }
 
+func (repo *UserRepository) GetByCids(cids []uint) (users []*entity.User, err error) {
+	users = make([]*entity.User, 0, len(cids))
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("cid IN ?", cids).Find(&users).Error
+	})
+	return
+}
+
+// GetByIdsOrCids 按用户 ID 或呼号批量获取用户, 同时加载角色与头像, 已注销的用户不在结果中
+func (repo *UserRepository) GetByIdsOrCids(userIds []uint, cids []uint) (users []*entity.User, err error) {
+	users = make([]*entity.User, 0, len(userIds)+len(cids))
+	if len(userIds) == 0 && len(cids) == 0 {
+		return
+	}
+	err = repo.Query(func(tx *gorm.DB) error {
+		condition := tx.Session(&gorm.Session{NewDB: true})
+		if len(userIds) > 0 {
+			condition = condition.Or("users.id IN ?", userIds)
+		}
+		if len(cids) > 0 {
+			condition = condition.Or("users.cid IN ?", cids)
+		}
+		return tx.Preload("Roles").
+			Preload("Roles.Role").
+			Joins("CurrentAvatar").
+			Where(condition).
+			Where("users.id NOT IN (SELECT user_id FROM account_deletions WHERE status = ?)", Entity.DeletionStatusCompleted).
+			Order("users.cid").
+			Find(&users).
+			Error
+	})
+	return
+}
+
+func (repo *UserRepository) GetByRole(roleId uint) (users []*entity.User, err error) {
+	users = make([]*entity.User, 0)
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Preload("Roles").
+			Preload("Roles.Role").
+			Where("users.id IN (?)", tx.Model(&entity.UserRole{}).Select("user_id").Where("role_id = ?", roleId)).
+			Find(&users).
+			Error
+	})
+	return
+}
+
 func (repo *UserRepository) Ban(userId uint, time sql.NullTime) error {
 	return repo.QueryWithTransaction(func(tx *gorm.DB) error {
 		return tx.Model(&entity.User{ID: userId}).Updates(map[string]interface{}{"banned": true, "banned_until": time}).Error
@@ -0,0 +1,118 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package repository
+package repository
+
+import (
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+	Repository "user-service/src/interfaces/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"half-nothing.cn/service-core/database"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+// staffSkeletonMinLength 管理人员用户名骨架的最小长度, 更短的骨架不参与包含检查, 避免误伤大量用户名
+const staffSkeletonMinLength = 4
+
+type UsernameRepository struct {
+	*database.BaseRepository[*Entity.UsernameChange]
+}
+
+func NewUsernameRepository(
+	lg logger.Interface,
+	db *gorm.DB,
+	queryTimeout time.Duration,
+) *UsernameRepository {
+	return &UsernameRepository{
+		BaseRepository: database.NewBaseRepository[*Entity.UsernameChange](lg, "username-repository", db, queryTimeout),
+	}
+}
+
+func (repo *UsernameRepository) GetByUser(userId uint) (changes []*Entity.UsernameChange, err error) {
+	changes = make([]*Entity.UsernameChange, 0)
+	err = repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("user_id = ?", userId).
+			Order("id DESC").
+			Find(&changes).
+			Error
+	})
+	return
+}
+
+func (repo *UsernameRepository) GetLatestSelfService(userId uint) (*Entity.UsernameChange, error) {
+	change := &Entity.UsernameChange{}
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Where("user_id = ? AND self_service = ?", userId, true).
+			Order("id DESC").
+			First(change).
+			Error
+	})
+	return change, err
+}
+
+func (repo *UsernameRepository) IsReserved(skeleton string, userId uint, now time.Time) (bool, error) {
+	var count int64
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Model(&Entity.UsernameChange{}).
+			Where("old_skeleton = ? AND user_id <> ? AND reserved_until > ?", skeleton, userId, now).
+			Count(&count).
+			Error
+	})
+	return count > 0, err
+}
+
+func (repo *UsernameRepository) HasSimilar(skeleton string, userId uint) (bool, error) {
+	var count int64
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Model(&Entity.UserSkeleton{}).
+			Where("skeleton = ? AND user_id <> ?", skeleton, userId).
+			Count(&count).
+			Error
+	})
+	return count > 0, err
+}
+
+// ContainsStaff 拥有权限、角色或分区角色的用户视为管理人员, 骨架只包含字母和数字, 因此可以直接用于 LIKE
+func (repo *UsernameRepository) ContainsStaff(skeleton string, userId uint) (bool, error) {
+	var count int64
+	err := repo.Query(func(tx *gorm.DB) error {
+		return tx.Model(&Entity.UserSkeleton{}).
+			Where("user_id <> ? AND CHAR_LENGTH(skeleton) >= ? AND ? LIKE CONCAT('%', skeleton, '%')", userId, staffSkeletonMinLength, skeleton).
+			Where("(user_id IN (SELECT id FROM users WHERE permission <> 0) OR " +
+				"user_id IN (SELECT user_id FROM user_roles) OR " +
+				"user_id IN (SELECT user_id FROM division_roles))").
+			Count(&count).
+			Error
+	})
+	return count > 0, err
+}
+
+// saveUserSkeleton 在事务中保存用户名的骨架
+func saveUserSkeleton(tx *gorm.DB, userId uint, username string) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"skeleton"}),
+	}).Create(&Entity.UserSkeleton{UserId: userId, Skeleton: Repository.UsernameSkeleton(username)}).Error
+}
+
+// EnsureUserSkeletons 为尚未保存用户名骨架的用户补充记录, 已注销的用户除外
+func EnsureUserSkeletons(db *gorm.DB) error {
+	var users []*entity.User
+	return db.Select("id", "username").
+		Where("id NOT IN (SELECT user_id FROM user_skeletons)").
+		Where("id NOT IN (SELECT user_id FROM account_deletions WHERE status = ?)", Entity.DeletionStatusCompleted).
+		FindInBatches(&users, 500, func(tx *gorm.DB, batch int) error {
+			for _, user := range users {
+				if err := saveUserSkeleton(db, user.ID, user.Username); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).
+		Error
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+	"user-service/src/interfaces/server/service"
+
+	"github.com/labstack/echo/v4"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type AuthorizationController struct {
+	logger  logger.Interface
+	service service.AuthorizationInterface
+}
+
+func NewAuthorizationController(
+	lg logger.Interface,
+	service service.AuthorizationInterface,
+) *AuthorizationController {
+	return &AuthorizationController{
+		logger:  logger.NewLoggerAdapter(lg, "authorization-controller"),
+		service: service,
+	}
+}
+
+func (controller *AuthorizationController) CheckPermission(ctx echo.Context) error {
+	data := &DTO.CheckPermission{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("CheckPermission handle fail, bind argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	if data.SubjectId == 0 || len(data.Permissions) == 0 {
+		controller.logger.Errorf("CheckPermission handle fail, subject or permissions is empty")
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("CheckPermission handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("CheckPermission with argument %#v", data)
+	return controller.service.CheckPermission(data).Response(ctx)
+}
+
+func (controller *AuthorizationController) BatchCheckPermission(ctx echo.Context) error {
+	data := &DTO.BatchCheckPermission{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("BatchCheckPermission handle fail, bind argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	if len(data.Requests) == 0 {
+		controller.logger.Errorf("BatchCheckPermission handle fail, argument Requests is empty")
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("BatchCheckPermission handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("BatchCheckPermission with argument %#v", data)
+	return controller.service.BatchCheckPermission(data).Response(ctx)
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import (
+	"io"
+	"net/http"
+	DTO "user-service/src/interfaces/server/dto"
+	"user-service/src/interfaces/server/service"
+
+	"github.com/labstack/echo/v4"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+// avatarCacheControl 头像文件的键在内容变化时会改变, 可以长期缓存
+const (
+	avatarCacheControl    = "public, max-age=31536000, immutable"
+	identiconCacheControl = "public, max-age=86400"
+)
+
+type AvatarController struct {
+	logger  logger.Interface
+	service service.AvatarInterface
+}
+
+func NewAvatarController(
+	lg logger.Interface,
+	service service.AvatarInterface,
+) *AvatarController {
+	return &AvatarController{
+		logger:  logger.NewLoggerAdapter(lg, "avatar-controller"),
+		service: service,
+	}
+}
+
+func (controller *AvatarController) Upload(ctx echo.Context) error {
+	data := &DTO.UploadAvatar{}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("Upload handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		controller.logger.Errorf("Upload handle fail, parse file fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	file, err := header.Open()
+	if err != nil {
+		controller.logger.Errorf("Upload handle fail, open file fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	defer func() { _ = file.Close() }()
+	if data.Data, err = io.ReadAll(file); err != nil {
+		controller.logger.Errorf("Upload handle fail, read file fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	controller.logger.Debugf("Upload with file %s (%d bytes)", header.Filename, len(data.Data))
+	return controller.service.Upload(data).Response(ctx)
+}
+
+func (controller *AvatarController) GetSelfAvatars(ctx echo.Context) error {
+	data := &DTO.GetSelfAvatars{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetSelfAvatars handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetSelfAvatars handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetSelfAvatars handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetSelfAvatars handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetSelfAvatars with argument %#v", data)
+	return controller.service.GetSelfAvatars(data).Response(ctx)
+}
+
+func (controller *AvatarController) SelectAvatar(ctx echo.Context) error {
+	data := &DTO.SelectAvatar{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("SelectAvatar handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("SelectAvatar handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("SelectAvatar handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("SelectAvatar handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("SelectAvatar with argument %#v", data)
+	return controller.service.SelectAvatar(data).Response(ctx)
+}
+
+func (controller *AvatarController) ClearAvatar(ctx echo.Context) error {
+	data := &DTO.ClearAvatar{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("ClearAvatar handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("ClearAvatar handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("ClearAvatar handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("ClearAvatar handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("ClearAvatar with argument %#v", data)
+	return controller.service.ClearAvatar(data).Response(ctx)
+}
+
+func (controller *AvatarController) GetUserAvatars(ctx echo.Context) error {
+	data := &DTO.GetUserAvatars{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetUserAvatars handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetUserAvatars handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetUserAvatars handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetUserAvatars handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetUserAvatars with argument %#v", data)
+	return controller.service.GetUserAvatars(data).Response(ctx)
+}
+
+func (controller *AvatarController) RemoveAvatar(ctx echo.Context) error {
+	data := &DTO.RemoveAvatar{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("RemoveAvatar handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("RemoveAvatar handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("RemoveAvatar handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("RemoveAvatar handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("RemoveAvatar with argument %#v", data)
+	return controller.service.RemoveAvatar(data).Response(ctx)
+}
+
+func (controller *AvatarController) GetFile(ctx echo.Context) error {
+	data := &DTO.GetAvatarFile{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetFile handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetFile handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetFile handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	file, response := controller.service.GetFile(data)
+	if response != nil {
+		return response.Response(ctx)
+	}
+	ctx.Response().Header().Set("Cache-Control", avatarCacheControl)
+	return ctx.Blob(http.StatusOK, file.ContentType, file.Content)
+}
+
+func (controller *AvatarController) GetIdenticon(ctx echo.Context) error {
+	data := &DTO.GetIdenticon{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetIdenticon handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetIdenticon handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetIdenticon handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	file, response := controller.service.GetIdenticon(data)
+	if response != nil {
+		return response.Response(ctx)
+	}
+	ctx.Response().Header().Set("Cache-Control", identiconCacheControl)
+	return ctx.Blob(http.StatusOK, file.ContentType, file.Content)
+}
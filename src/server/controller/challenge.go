@@ -0,0 +1,49 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+	"user-service/src/interfaces/server/service"
+
+	"github.com/labstack/echo/v4"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type ChallengeController struct {
+	logger  logger.Interface
+	service service.ChallengeInterface
+}
+
+func NewChallengeController(
+	lg logger.Interface,
+	service service.ChallengeInterface,
+) *ChallengeController {
+	return &ChallengeController{
+		logger:  logger.NewLoggerAdapter(lg, "challenge-controller"),
+		service: service,
+	}
+}
+
+func (controller *ChallengeController) GetChallenge(ctx echo.Context) error {
+	data := &DTO.GetChallenge{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetChallenge handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetChallenge handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetChallenge handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	controller.logger.Debugf("GetChallenge with argument %#v", data)
+	return controller.service.GetChallenge(data).Response(ctx)
+}
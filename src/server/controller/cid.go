@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+	"user-service/src/interfaces/server/service"
+
+	"github.com/labstack/echo/v4"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type CidController struct {
+	logger  logger.Interface
+	service service.CidInterface
+}
+
+func NewCidController(
+	lg logger.Interface,
+	service service.CidInterface,
+) *CidController {
+	return &CidController{
+		logger:  logger.NewLoggerAdapter(lg, "cid-controller"),
+		service: service,
+	}
+}
+
+func (controller *CidController) GetReservations(ctx echo.Context) error {
+	data := &DTO.GetCidReservations{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetReservations handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetReservations handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetReservations handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetReservations handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetReservations with argument %#v", data)
+	return controller.service.GetReservations(data).Response(ctx)
+}
+
+func (controller *CidController) Reserve(ctx echo.Context) error {
+	data := &DTO.ReserveCid{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("Reserve handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("Reserve handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("Reserve handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("Reserve handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("Reserve with argument %#v", data)
+	return controller.service.Reserve(data).Response(ctx)
+}
+
+func (controller *CidController) Release(ctx echo.Context) error {
+	data := &DTO.ReleaseCid{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("Release handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("Release handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("Release handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("Release handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("Release with argument %#v", data)
+	return controller.service.Release(data).Response(ctx)
+}
+
+func (controller *CidController) AssignCid(ctx echo.Context) error {
+	data := &DTO.AssignCid{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("AssignCid handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("AssignCid handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("AssignCid handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("AssignCid handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("AssignCid with argument %#v", data)
+	return controller.service.AssignCid(data).Response(ctx)
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+	"user-service/src/interfaces/server/service"
+
+	"github.com/labstack/echo/v4"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type DivisionController struct {
+	logger  logger.Interface
+	service service.DivisionInterface
+}
+
+func NewDivisionController(
+	lg logger.Interface,
+	service service.DivisionInterface,
+) *DivisionController {
+	return &DivisionController{
+		logger:  logger.NewLoggerAdapter(lg, "division-controller"),
+		service: service,
+	}
+}
+
+func (controller *DivisionController) GetPages(ctx echo.Context) error {
+	data := &DTO.GetDivisionPage{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetPages handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetPages handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetPages handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetPages handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetPages with argument %#v", data)
+	return controller.service.GetPages(data).Response(ctx)
+}
+
+func (controller *DivisionController) GetById(ctx echo.Context) error {
+	data := &DTO.GetDivisionDetail{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetById handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetById handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetById handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetById handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetById with argument %#v", data)
+	return controller.service.GetById(data).Response(ctx)
+}
+
+func (controller *DivisionController) Create(ctx echo.Context) error {
+	data := &DTO.CreateDivision{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("Create handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("Create handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("Create handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("Create handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("Create with argument %#v", data)
+	return controller.service.Create(data).Response(ctx)
+}
+
+func (controller *DivisionController) Update(ctx echo.Context) error {
+	data := &DTO.UpdateDivision{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("Update handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	if data.Name == "" && data.Description == "" {
+		controller.logger.Error("Update handle fail, nothing to update")
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("Update handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("Update handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("Update handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("Update with argument %#v", data)
+	return controller.service.Update(data).Response(ctx)
+}
+
+func (controller *DivisionController) Delete(ctx echo.Context) error {
+	data := &DTO.DeleteDivision{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("Delete handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("Delete handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("Delete handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("Delete handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("Delete with argument %#v", data)
+	return controller.service.Delete(data).Response(ctx)
+}
+
+func (controller *DivisionController) AddMembers(ctx echo.Context) error {
+	data := &DTO.EditDivisionMember{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("AddMembers handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("AddMembers handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("AddMembers handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("AddMembers handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("AddMembers with argument %#v", data)
+	return controller.service.AddMembers(data).Response(ctx)
+}
+
+func (controller *DivisionController) RemoveMembers(ctx echo.Context) error {
+	data := &DTO.EditDivisionMember{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("RemoveMembers handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("RemoveMembers handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("RemoveMembers handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("RemoveMembers handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("RemoveMembers with argument %#v", data)
+	return controller.service.RemoveMembers(data).Response(ctx)
+}
+
+func (controller *DivisionController) GetUserDivisions(ctx echo.Context) error {
+	data := &DTO.GetUserDivisions{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetUserDivisions handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetUserDivisions handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetUserDivisions handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetUserDivisions handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetUserDivisions with argument %#v", data)
+	return controller.service.GetUserDivisions(data).Response(ctx)
+}
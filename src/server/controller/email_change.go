@@ -0,0 +1,118 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+	"user-service/src/interfaces/server/service"
+
+	"github.com/labstack/echo/v4"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type EmailChangeController struct {
+	logger  logger.Interface
+	service service.EmailChangeInterface
+}
+
+func NewEmailChangeController(
+	lg logger.Interface,
+	service service.EmailChangeInterface,
+) *EmailChangeController {
+	return &EmailChangeController{
+		logger:  logger.NewLoggerAdapter(lg, "email-change-controller"),
+		service: service,
+	}
+}
+
+func (controller *EmailChangeController) GetEmailChange(ctx echo.Context) error {
+	data := &DTO.GetEmailChange{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetEmailChange handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetEmailChange handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetEmailChange handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetEmailChange handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetEmailChange with argument %#v", data)
+	return controller.service.GetEmailChange(data).Response(ctx)
+}
+
+func (controller *EmailChangeController) CancelEmailChange(ctx echo.Context) error {
+	data := &DTO.CancelEmailChange{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("CancelEmailChange handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("CancelEmailChange handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("CancelEmailChange handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("CancelEmailChange handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("CancelEmailChange with argument %#v", data)
+	return controller.service.CancelEmailChange(data).Response(ctx)
+}
+
+func (controller *EmailChangeController) ConfirmEmailChange(ctx echo.Context) error {
+	data := &DTO.ConfirmEmailChange{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("ConfirmEmailChange handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("ConfirmEmailChange handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("ConfirmEmailChange handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	controller.logger.Debugf("ConfirmEmailChange with argument %#v", data)
+	return controller.service.ConfirmEmailChange(data).Response(ctx)
+}
+
+func (controller *EmailChangeController) RevertEmailChange(ctx echo.Context) error {
+	data := &DTO.RevertEmailChange{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("RevertEmailChange handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("RevertEmailChange handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("RevertEmailChange handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	controller.logger.Debugf("RevertEmailChange with argument %#v", data)
+	return controller.service.RevertEmailChange(data).Response(ctx)
+}
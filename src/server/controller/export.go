@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	Entity "user-service/src/interfaces/database/entity"
+	DTO "user-service/src/interfaces/server/dto"
+	"user-service/src/interfaces/server/service"
+
+	"github.com/labstack/echo/v4"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+const mimeApplicationZip = "application/zip"
+
+type DataExportController struct {
+	logger  logger.Interface
+	service service.DataExportInterface
+}
+
+func NewDataExportController(
+	lg logger.Interface,
+	service service.DataExportInterface,
+) *DataExportController {
+	return &DataExportController{
+		logger:  logger.NewLoggerAdapter(lg, "data-export-controller"),
+		service: service,
+	}
+}
+
+func (controller *DataExportController) RequestExport(ctx echo.Context) error {
+	data := &DTO.RequestDataExport{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("RequestExport handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("RequestExport handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("RequestExport handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("RequestExport handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("RequestExport with argument %#v", data)
+	return controller.service.RequestExport(data).Response(ctx)
+}
+
+func (controller *DataExportController) GetExport(ctx echo.Context) error {
+	data := &DTO.GetDataExport{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetExport handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetExport handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetExport handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetExport handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetExport with argument %#v", data)
+	return controller.service.GetExport(data).Response(ctx)
+}
+
+func (controller *DataExportController) Download(ctx echo.Context) error {
+	data := &DTO.DownloadDataExport{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("Download handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("Download handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("Download handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("Download handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("Download with argument %#v", data)
+	file, response := controller.service.Download(data)
+	if response != nil {
+		return response.Response(ctx)
+	}
+	contentType := echo.MIMEApplicationJSONCharsetUTF8
+	if file.Format == Entity.ExportFormatZip {
+		contentType = mimeApplicationZip
+	}
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"%s\"", file.Name))
+	return ctx.Blob(http.StatusOK, contentType, file.Content)
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+	"user-service/src/interfaces/server/service"
+
+	"github.com/labstack/echo/v4"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type InstructorController struct {
+	logger  logger.Interface
+	service service.InstructorInterface
+}
+
+func NewInstructorController(
+	lg logger.Interface,
+	service service.InstructorInterface,
+) *InstructorController {
+	return &InstructorController{
+		logger:  logger.NewLoggerAdapter(lg, "instructor-controller"),
+		service: service,
+	}
+}
+
+func (controller *InstructorController) GetSelfInstructor(ctx echo.Context) error {
+	data := &DTO.GetSelfInstructor{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetSelfInstructor handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetSelfInstructor handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetSelfInstructor handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetSelfInstructor handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetSelfInstructor with argument %#v", data)
+	return controller.service.GetSelfInstructor(data).Response(ctx)
+}
+
+func (controller *InstructorController) GetSelfTrainees(ctx echo.Context) error {
+	data := &DTO.GetSelfTrainees{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetSelfTrainees handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetSelfTrainees handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetSelfTrainees handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetSelfTrainees handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetSelfTrainees with argument %#v", data)
+	return controller.service.GetSelfTrainees(data).Response(ctx)
+}
+
+func (controller *InstructorController) GetUserInstructor(ctx echo.Context) error {
+	data := &DTO.GetUserInstructor{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetUserInstructor handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetUserInstructor handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetUserInstructor handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetUserInstructor handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetUserInstructor with argument %#v", data)
+	return controller.service.GetUserInstructor(data).Response(ctx)
+}
+
+func (controller *InstructorController) AssignInstructor(ctx echo.Context) error {
+	data := &DTO.AssignInstructor{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("AssignInstructor handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("AssignInstructor handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("AssignInstructor handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("AssignInstructor handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("AssignInstructor with argument %#v", data)
+	return controller.service.AssignInstructor(data).Response(ctx)
+}
+
+func (controller *InstructorController) UnassignInstructor(ctx echo.Context) error {
+	data := &DTO.UnassignInstructor{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("UnassignInstructor handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("UnassignInstructor handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("UnassignInstructor handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("UnassignInstructor handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("UnassignInstructor with argument %#v", data)
+	return controller.service.UnassignInstructor(data).Response(ctx)
+}
@@ -5,6 +5,7 @@
 package controller
 
 import (
+	"net/http"
 	DTO "user-service/src/interfaces/server/dto"
 	"user-service/src/interfaces/server/service"
 
@@ -14,6 +15,12 @@ import (
 	"half-nothing.cn/service-core/interfaces/logger"
 )
 
+const (
+	headerETag           = "ETag"
+	headerAcceptLanguage = "Accept-Language"
+	headerIfNoneMatch    = "If-None-Match"
+)
+
 type PermissionController struct {
 	logger  logger.Interface
 	service service.PermissionInterface
@@ -180,3 +187,108 @@ func (controller *PermissionController) RevokeRoleUser(ctx echo.Context) error {
 	controller.logger.Debugf("RevokeRoleUser with argument %#v", data)
 	return controller.service.RevokeRoleUser(data).Response(ctx)
 }
+
+func (controller *PermissionController) SetRoleUsers(ctx echo.Context) error {
+	data := &DTO.SetRoleUsers{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("SetRoleUsers handle fail, bind argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("SetRoleUsers handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("SetRoleUsers handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	if data.UserIds == nil {
+		controller.logger.Errorf("SetRoleUsers handle fail, missing user ids")
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("SetRoleUsers handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("SetRoleUsers with argument %#v", data)
+	return controller.service.SetRoleUsers(data).Response(ctx)
+}
+
+func (controller *PermissionController) GrantScopedRole(ctx echo.Context) error {
+	data := &DTO.GrantScopedRole{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GrantScopedRole handle fail, bind argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GrantScopedRole handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GrantScopedRole handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GrantScopedRole handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GrantScopedRole with argument %#v", data)
+	return controller.service.GrantScopedRole(data).Response(ctx)
+}
+
+func (controller *PermissionController) RevokeScopedRole(ctx echo.Context) error {
+	data := &DTO.RevokeScopedRole{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("RevokeScopedRole handle fail, bind argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("RevokeScopedRole handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("RevokeScopedRole handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("RevokeScopedRole handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("RevokeScopedRole with argument %#v", data)
+	return controller.service.RevokeScopedRole(data).Response(ctx)
+}
+
+func (controller *PermissionController) GetCatalog(ctx echo.Context) error {
+	data := &DTO.GetPermissionCatalog{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetCatalog handle fail, bind argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	if data.Lang == "" {
+		data.Lang = ctx.Request().Header.Get(headerAcceptLanguage)
+	}
+	controller.logger.Debugf("GetCatalog with argument %#v", data)
+	etag, res := controller.service.GetCatalog(data)
+	ctx.Response().Header().Set(echo.HeaderVary, headerAcceptLanguage)
+	ctx.Response().Header().Set(headerETag, etag)
+	if ctx.Request().Header.Get(headerIfNoneMatch) == etag {
+		return ctx.NoContent(http.StatusNotModified)
+	}
+	return res.Response(ctx)
+}
+
+func (controller *PermissionController) DecodePermission(ctx echo.Context) error {
+	data := &DTO.DecodePermission{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("DecodePermission handle fail, bind argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	controller.logger.Debugf("DecodePermission with argument %#v", data)
+	return controller.service.DecodePermission(data).Response(ctx)
+}
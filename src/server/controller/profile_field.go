@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+	"user-service/src/interfaces/server/service"
+
+	"github.com/labstack/echo/v4"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type ProfileFieldController struct {
+	logger  logger.Interface
+	service service.ProfileFieldInterface
+}
+
+func NewProfileFieldController(
+	lg logger.Interface,
+	service service.ProfileFieldInterface,
+) *ProfileFieldController {
+	return &ProfileFieldController{
+		logger:  logger.NewLoggerAdapter(lg, "profile-field-controller"),
+		service: service,
+	}
+}
+
+func (controller *ProfileFieldController) GetFields(ctx echo.Context) error {
+	data := &DTO.GetProfileFields{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetFields handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetFields handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetFields handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetFields handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetFields with argument %#v", data)
+	return controller.service.GetFields(data).Response(ctx)
+}
+
+func (controller *ProfileFieldController) Create(ctx echo.Context) error {
+	data := &DTO.CreateProfileField{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("Create handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("Create handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("Create handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("Create handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("Create with argument %#v", data)
+	return controller.service.Create(data).Response(ctx)
+}
+
+func (controller *ProfileFieldController) Update(ctx echo.Context) error {
+	data := &DTO.UpdateProfileField{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("Update handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("Update handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("Update handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("Update handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("Update with argument %#v", data)
+	return controller.service.Update(data).Response(ctx)
+}
+
+func (controller *ProfileFieldController) Delete(ctx echo.Context) error {
+	data := &DTO.DeleteProfileField{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("Delete handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("Delete handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("Delete handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("Delete handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("Delete with argument %#v", data)
+	return controller.service.Delete(data).Response(ctx)
+}
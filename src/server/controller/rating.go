@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+	"user-service/src/interfaces/server/service"
+
+	"github.com/labstack/echo/v4"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type RatingController struct {
+	logger  logger.Interface
+	service service.RatingInterface
+}
+
+func NewRatingController(
+	lg logger.Interface,
+	service service.RatingInterface,
+) *RatingController {
+	return &RatingController{
+		logger:  logger.NewLoggerAdapter(lg, "rating-controller"),
+		service: service,
+	}
+}
+
+func (controller *RatingController) GetRating(ctx echo.Context) error {
+	data := &DTO.GetRating{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetRating handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetRating handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetRating handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetRating handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetRating with argument %#v", data)
+	return controller.service.GetRating(data).Response(ctx)
+}
+
+func (controller *RatingController) ChangeRating(ctx echo.Context) error {
+	data := &DTO.ChangeRating{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("ChangeRating handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("ChangeRating handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("ChangeRating handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("ChangeRating handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("ChangeRating with argument %#v", data)
+	return controller.service.ChangeRating(data).Response(ctx)
+}
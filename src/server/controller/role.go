@@ -5,15 +5,27 @@
 package controller
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	DTO "user-service/src/interfaces/server/dto"
 	"user-service/src/interfaces/server/service"
 
 	"github.com/labstack/echo/v4"
+	"go.yaml.in/yaml/v3"
 	"half-nothing.cn/service-core/interfaces/http/dto"
 	"half-nothing.cn/service-core/interfaces/http/jwt"
 	"half-nothing.cn/service-core/interfaces/logger"
 )
 
+const (
+	roleTemplateFormatYaml = "yaml"
+	roleTemplateFormatJson = "json"
+	mimeApplicationYaml    = "application/yaml"
+)
+
 type RoleController struct {
 	logger  logger.Interface
 	service service.RoleInterface
@@ -77,6 +89,30 @@ func (controller *RoleController) GetById(ctx echo.Context) error {
 	return controller.service.GetById(data).Response(ctx)
 }
 
+func (controller *RoleController) GetUsers(ctx echo.Context) error {
+	data := &DTO.GetRoleUserPage{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetUsers handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetUsers handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetUsers handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetUsers handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetUsers with argument %#v", data)
+	return controller.service.GetUsers(data).Response(ctx)
+}
+
 func (controller *RoleController) Create(ctx echo.Context) error {
 	data := &DTO.CreateRole{}
 	if err := ctx.Bind(data); err != nil {
@@ -152,3 +188,97 @@ func (controller *RoleController) Delete(ctx echo.Context) error {
 	controller.logger.Debugf("Delete with argument %#v", data)
 	return controller.service.Delete(data).Response(ctx)
 }
+
+func (controller *RoleController) GetDeletionImpact(ctx echo.Context) error {
+	data := &DTO.GetRoleDeletionImpact{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetDeletionImpact handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetDeletionImpact handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetDeletionImpact handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetDeletionImpact handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetDeletionImpact with argument %#v", data)
+	return controller.service.GetDeletionImpact(data).Response(ctx)
+}
+
+func (controller *RoleController) Export(ctx echo.Context) error {
+	data := &DTO.ExportRoles{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("Export handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	if data.Format == "" {
+		data.Format = roleTemplateFormatYaml
+	}
+	if data.Format != roleTemplateFormatYaml && data.Format != roleTemplateFormatJson {
+		controller.logger.Errorf("Export handle fail, unsupported format %s", data.Format)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("Export handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("Export with argument %#v", data)
+	file, res := controller.service.Export(data)
+	if res != nil {
+		return res.Response(ctx)
+	}
+	var content []byte
+	var err error
+	contentType := echo.MIMEApplicationJSONCharsetUTF8
+	if data.Format == roleTemplateFormatJson {
+		content, err = json.MarshalIndent(file, "", "  ")
+	} else {
+		content, err = yaml.Marshal(file)
+		contentType = mimeApplicationYaml
+	}
+	if err != nil {
+		controller.logger.Errorf("Export handle fail, marshal role template err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"roles.%s\"", data.Format))
+	return ctx.Blob(http.StatusOK, contentType, content)
+}
+
+func (controller *RoleController) Import(ctx echo.Context) error {
+	data := &DTO.ImportRoles{}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(ctx, data); err != nil {
+		controller.logger.Errorf("Import handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		controller.logger.Errorf("Import handle fail, read body err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	data.File = &DTO.RoleTemplateFile{}
+	if strings.Contains(ctx.Request().Header.Get(echo.HeaderContentType), roleTemplateFormatJson) {
+		err = json.Unmarshal(body, data.File)
+	} else {
+		err = yaml.Unmarshal(body, data.File)
+	}
+	if err != nil {
+		controller.logger.Errorf("Import handle fail, parse role template err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("Import handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("Import with argument %#v", data)
+	return controller.service.Import(data).Response(ctx)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+	"user-service/src/interfaces/server/service"
+
+	"github.com/labstack/echo/v4"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type SessionController struct {
+	logger  logger.Interface
+	service service.SessionInterface
+}
+
+func NewSessionController(
+	lg logger.Interface,
+	service service.SessionInterface,
+) *SessionController {
+	return &SessionController{
+		logger:  logger.NewLoggerAdapter(lg, "session-controller"),
+		service: service,
+	}
+}
+
+func (controller *SessionController) RequireSession(next echo.HandlerFunc) echo.HandlerFunc {
+	return controller.requireSession(next, false)
+}
+
+func (controller *SessionController) RequirePasswordSession(next echo.HandlerFunc) echo.HandlerFunc {
+	return controller.requireSession(next, true)
+}
+
+func (controller *SessionController) requireSession(next echo.HandlerFunc, passwordChange bool) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		data := &DTO.CheckSession{PasswordChange: passwordChange}
+		if err := jwt.SetJwtContent(data, ctx); err != nil {
+			controller.logger.Errorf("RequireSession handle fail, set jwt content err, %v", err)
+			return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+		}
+		if status := controller.service.CheckSession(data); status != nil {
+			return dto.ErrorResponse(ctx, status)
+		}
+		return next(ctx)
+	}
+}
@@ -35,6 +35,7 @@ func (controller *UserController) Register(ctx echo.Context) error {
 		controller.logger.Errorf("Register handle fail, parse argument fail, %v", err)
 		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
 	}
+	data.Normalize()
 	res, err := dto.ValidStruct(data)
 	if err != nil {
 		controller.logger.Errorf("Register handle fail, validate err, %v", err)
@@ -55,6 +56,7 @@ func (controller *UserController) CheckAvailability(ctx echo.Context) error {
 		controller.logger.Errorf("CheckAvailability handle fail, parse argument fail, %v", err)
 		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
 	}
+	data.Normalize()
 	res, err := dto.ValidStruct(data)
 	if err != nil {
 		controller.logger.Errorf("CheckAvailability handle fail, validate err, %v", err)
@@ -64,10 +66,11 @@ func (controller *UserController) CheckAvailability(ctx echo.Context) error {
 		controller.logger.Errorf("CheckAvailability handle fail, validate argument fail, %v", res)
 		return dto.ErrorResponse(ctx, res)
 	}
-	if data.Cid <= 0 && data.Email == "" && data.Username == "" {
+	if data.Email == "" && data.Username == "" {
 		controller.logger.Errorf("CheckAvailability handle fail, validate argument fail, %v", res)
 		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
 	}
+	dto.SetHttpContent(data, ctx)
 	controller.logger.Debugf("CheckAvailability with argument %#v", data)
 	return controller.service.CheckAvailability(data).Response(ctx)
 }
@@ -174,10 +177,6 @@ func (controller *UserController) UpdateSelfData(ctx echo.Context) error {
 		controller.logger.Errorf("UpdateSelfData handle fail, nothing need to update")
 		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
 	}
-	if data.Email != "" && data.EmailCode == "" {
-		controller.logger.Errorf("UpdateSelfData handle fail, no email code provided")
-		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
-	}
 	res, err := dto.ValidStruct(data)
 	if err != nil {
 		controller.logger.Errorf("UpdateSelfData handle fail, validate err, %v", err)
@@ -202,7 +201,7 @@ func (controller *UserController) UpdateData(ctx echo.Context) error {
 		controller.logger.Errorf("UpdateData handle fail, parse argument fail, %v", err)
 		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
 	}
-	if data.Email == "" && data.Username == "" && data.QQ == "" && data.Password == "" {
+	if data.Email == "" && data.Username == "" && data.QQ == "" && data.Password == "" && !data.RequirePasswordChange {
 		controller.logger.Errorf("UpdateSelfData handle fail, nothing need to update")
 		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
 	}
@@ -301,3 +300,147 @@ func (controller *UserController) Unban(ctx echo.Context) error {
 	controller.logger.Debugf("Unban with argument %#v", data)
 	return controller.service.Unban(data).Response(ctx)
 }
+
+func (controller *UserController) RequestDeletion(ctx echo.Context) error {
+	data := &DTO.RequestAccountDeletion{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("RequestDeletion handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("RequestDeletion handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("RequestDeletion handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("RequestDeletion handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("RequestDeletion with argument %#v", data)
+	return controller.service.RequestDeletion(data).Response(ctx)
+}
+
+func (controller *UserController) GetDeletion(ctx echo.Context) error {
+	data := &DTO.GetAccountDeletion{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetDeletion handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetDeletion handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetDeletion handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetDeletion handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetDeletion with argument %#v", data)
+	return controller.service.GetDeletion(data).Response(ctx)
+}
+
+func (controller *UserController) CancelDeletion(ctx echo.Context) error {
+	data := &DTO.CancelAccountDeletion{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("CancelDeletion handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("CancelDeletion handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("CancelDeletion handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("CancelDeletion handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("CancelDeletion with argument %#v", data)
+	return controller.service.CancelDeletion(data).Response(ctx)
+}
+
+func (controller *UserController) Delete(ctx echo.Context) error {
+	data := &DTO.DeleteUser{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("Delete handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("Delete handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("Delete handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("Delete handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("Delete with argument %#v", data)
+	return controller.service.Delete(data).Response(ctx)
+}
+
+func (controller *UserController) GetPublicProfile(ctx echo.Context) error {
+	data := &DTO.GetPublicProfile{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetPublicProfile handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetPublicProfile handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetPublicProfile handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetPublicProfile handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetPublicProfile with argument %#v", data)
+	return controller.service.GetPublicProfile(data).Response(ctx)
+}
+
+func (controller *UserController) BatchGetPublicProfile(ctx echo.Context) error {
+	data := &DTO.BatchGetPublicProfile{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("BatchGetPublicProfile handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("BatchGetPublicProfile handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("BatchGetPublicProfile handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("BatchGetPublicProfile handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("BatchGetPublicProfile with argument %#v", data)
+	return controller.service.BatchGetPublicProfile(data).Response(ctx)
+}
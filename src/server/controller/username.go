@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package controller
+package controller
+
+import (
+	DTO "user-service/src/interfaces/server/dto"
+	"user-service/src/interfaces/server/service"
+
+	"github.com/labstack/echo/v4"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+type UsernameController struct {
+	logger  logger.Interface
+	service service.UsernameInterface
+}
+
+func NewUsernameController(
+	lg logger.Interface,
+	service service.UsernameInterface,
+) *UsernameController {
+	return &UsernameController{
+		logger:  logger.NewLoggerAdapter(lg, "username-controller"),
+		service: service,
+	}
+}
+
+func (controller *UsernameController) GetSelfUsernameHistory(ctx echo.Context) error {
+	data := &DTO.GetSelfUsernameHistory{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetSelfUsernameHistory handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetSelfUsernameHistory handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetSelfUsernameHistory handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetSelfUsernameHistory handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetSelfUsernameHistory with argument %#v", data)
+	return controller.service.GetSelfUsernameHistory(data).Response(ctx)
+}
+
+func (controller *UsernameController) GetUsernameHistory(ctx echo.Context) error {
+	data := &DTO.GetUsernameHistory{}
+	if err := ctx.Bind(data); err != nil {
+		controller.logger.Errorf("GetUsernameHistory handle fail, parse argument fail, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrErrorParam)
+	}
+	res, err := dto.ValidStruct(data)
+	if err != nil {
+		controller.logger.Errorf("GetUsernameHistory handle fail, validate err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrServerError)
+	}
+	if res != nil {
+		controller.logger.Errorf("GetUsernameHistory handle fail, validate argument fail, %v", res)
+		return dto.ErrorResponse(ctx, res)
+	}
+	dto.SetHttpContent(data, ctx)
+	if err := jwt.SetJwtContent(data, ctx); err != nil {
+		controller.logger.Errorf("GetUsernameHistory handle fail, set jwt content err, %v", err)
+		return dto.ErrorResponse(ctx, dto.ErrUnknownJwtError)
+	}
+	controller.logger.Debugf("GetUsernameHistory with argument %#v", data)
+	return controller.service.GetUsernameHistory(data).Response(ctx)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package server
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"user-service/src/interfaces/content"
+	pb "user-service/src/interfaces/grpc"
+	DTO "user-service/src/interfaces/server/dto"
+	"user-service/src/server/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+// AuthorizationServer 为其他服务提供鉴权决策的 gRPC 接口
+type AuthorizationServer struct {
+	pb.UnimplementedAuthorizationServer
+	logger  logger.Interface
+	service *service.AuthorizationService
+}
+
+func NewAuthorizationServer(lg logger.Interface, service *service.AuthorizationService) *AuthorizationServer {
+	return &AuthorizationServer{
+		logger:  logger.NewLoggerAdapter(lg, "authorization-grpc"),
+		service: service,
+	}
+}
+
+func fromCheckRequest(request *pb.CheckPermissionRequest) *DTO.AuthorizationRequest {
+	return &DTO.AuthorizationRequest{
+		SubjectId:    uint(request.GetSubjectId()),
+		Permissions:  request.GetPermissions(),
+		TargetUserId: uint(request.GetTargetUserId()),
+		Resource:     request.GetResource(),
+	}
+}
+
+func toCheckResponse(decision *DTO.AuthorizationDecision) *pb.CheckPermissionResponse {
+	return &pb.CheckPermissionResponse{
+		Allowed:    decision.Allowed,
+		Reason:     decision.Reason,
+		Missing:    decision.Missing,
+		DivisionId: uint64(decision.DivisionId),
+	}
+}
+
+func (server *AuthorizationServer) CheckPermission(_ context.Context, request *pb.CheckPermissionRequest) (*pb.CheckPermissionResponse, error) {
+	decision, err := server.service.Evaluate(fromCheckRequest(request))
+	if err != nil {
+		server.logger.Errorf("CheckPermission handle fail, evaluate err, %v", err)
+		return nil, status.Error(codes.Internal, "fail to evaluate permission")
+	}
+	return toCheckResponse(decision), nil
+}
+
+func (server *AuthorizationServer) BatchCheckPermission(_ context.Context, request *pb.BatchCheckPermissionRequest) (*pb.BatchCheckPermissionResponse, error) {
+	requests := make([]*DTO.AuthorizationRequest, 0, len(request.GetRequests()))
+	for _, req := range request.GetRequests() {
+		requests = append(requests, fromCheckRequest(req))
+	}
+	decisions, err := server.service.EvaluateBatch(requests)
+	if err != nil {
+		if errors.Is(err, service.ErrBatchSizeExceeded) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		server.logger.Errorf("BatchCheckPermission handle fail, evaluate err, %v", err)
+		return nil, status.Error(codes.Internal, "fail to evaluate permission")
+	}
+	response := &pb.BatchCheckPermissionResponse{Responses: make([]*pb.CheckPermissionResponse, 0, len(decisions))}
+	for _, decision := range decisions {
+		response.Responses = append(response.Responses, toCheckResponse(decision))
+	}
+	return response, nil
+}
+
+// serviceTokenInterceptor 校验调用方在 metadata 中携带的服务令牌, 健康检查接口除外
+//
+// 调用方需要设置 "authorization: Bearer <token>", 令牌为 authorization.service_tokens 中的任意一个
+func serviceTokenInterceptor(tokens []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if strings.HasPrefix(info.FullMethod, "/grpc.health.v1.Health/") {
+			return handler(ctx, req)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, value := range md.Get("authorization") {
+			token, ok := strings.CutPrefix(value, "Bearer ")
+			if !ok {
+				continue
+			}
+			for _, expected := range tokens {
+				if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+					return handler(ctx, req)
+				}
+			}
+		}
+		return nil, status.Error(codes.Unauthenticated, "invalid service token")
+	}
+}
+
+func StartGrpcServer(content *content.ApplicationContent) {
+	c := content.ConfigManager().GetConfig()
+	lg := logger.NewLoggerAdapter(content.Logger(), "grpc-server")
+
+	lg.Info("Grpc server initializing...")
+	address := fmt.Sprintf("%s:%d", c.ServerConfig.GrpcServerConfig.Host, c.ServerConfig.GrpcServerConfig.Port)
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		lg.Fatalf("fail to listen on %s: %v", address, err)
+		return
+	}
+
+	s := grpc.NewServer(grpc.UnaryInterceptor(serviceTokenInterceptor(c.AuthorizationConfig.ServiceTokens)))
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	// 鉴权接口可以查询任意用户的权限, 未配置服务令牌时不对外提供
+	if len(c.AuthorizationConfig.ServiceTokens) > 0 {
+		pb.RegisterAuthorizationServer(s, NewAuthorizationServer(
+			content.Logger(),
+			service.NewAuthorizationService(
+				content.Logger(),
+				c.AuthorizationConfig,
+				content.UserRepo(),
+				content.DivisionRepo(),
+			),
+		))
+	} else {
+		lg.Warn("no authorization service token configured, grpc authorization service disabled")
+	}
+
+	content.Cleaner().Add("GrpcServer", func(ctx context.Context) error {
+		s.GracefulStop()
+		return nil
+	})
+
+	lg.Infof("Grpc server listening on %s", address)
+	if err := s.Serve(listener); err != nil {
+		lg.Errorf("grpc server stopped: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package server
+package server
+
+import (
+	"context"
+	"time"
+	"user-service/src/interfaces/content"
+	"user-service/src/interfaces/server/service"
+
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+// StartDeletionJob 定期匿名化宽限期已结束的注销账户, 并清除已过期的导出归档
+//
+// 使用与 Http 服务相同的 UserService, 多个实例同时运行时由数据库保证每个注销请求只被处理一次
+func StartDeletionJob(
+	content *content.ApplicationContent,
+	userService service.UserInterface,
+	exportService service.DataExportInterface,
+) {
+	c := content.ConfigManager().GetConfig()
+	lg := logger.NewLoggerAdapter(content.Logger(), "deletion-job")
+
+	ticker := time.NewTicker(c.DeletionConfig.CheckIntervalDuration)
+	stop := make(chan struct{})
+	content.Cleaner().Add("DeletionJob", func(ctx context.Context) error {
+		ticker.Stop()
+		close(stop)
+		return nil
+	})
+
+	process := func() {
+		if processed, err := userService.ProcessDueDeletions(); err != nil {
+			lg.Errorf("fail to process account deletions: %v", err)
+		} else if processed > 0 {
+			lg.Infof("%d account(s) anonymized", processed)
+		}
+		if purged, err := exportService.PurgeExpired(); err != nil {
+			lg.Errorf("fail to purge expired data exports: %v", err)
+		} else if purged > 0 {
+			lg.Infof("%d expired data export(s) purged", purged)
+		}
+	}
+
+	lg.Infof("Deletion job started, check interval %s", c.DeletionConfig.CheckIntervalDuration)
+	process()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			process()
+		}
+	}
+}
@@ -7,6 +7,7 @@ package server
 import (
 	"io"
 	"user-service/src/interfaces/content"
+	DTO "user-service/src/interfaces/server/dto"
 	"user-service/src/server/controller"
 	"user-service/src/server/service"
 
@@ -25,28 +26,110 @@ func StartServer(content *content.ApplicationContent) {
 	e.Logger.SetOutput(io.Discard)
 	e.Logger.SetLevel(log.OFF)
 
+	DTO.SetAvatarResolver(service.NewAvatarResolver(c.AvatarConfig))
+
 	http.SetEchoConfig(lg, e, c.ServerConfig.HttpServerConfig, nil)
 	jwtMidware, requireNoRefresh, requireRefresh := http.GetJWTMiddleware(content.ClaimFactory())
 	if c.TelemetryConfig.HttpServerTrace {
 		http.SetTelemetry(e, c.TelemetryConfig, http.SkipperHealthCheck)
 	}
 
+	sessionService := service.NewSessionService(
+		content.Logger(),
+		content.SessionRepo(),
+	)
+
+	sessionController := controller.NewSessionController(
+		content.Logger(),
+		sessionService,
+	)
+	// 会话失效前签发的访问令牌在过期前同样不能使用
+	requireSession := sessionController.RequireSession
+	requirePasswordSession := sessionController.RequirePasswordSession
+
+	challengeService := service.NewChallengeService(
+		content.Logger(),
+		c.ChallengeConfig,
+		content.Challenge(),
+	)
+
+	challengeController := controller.NewChallengeController(
+		content.Logger(),
+		challengeService,
+	)
+
+	emailChangeService := service.NewEmailChangeService(
+		content.Logger(),
+		c.EmailChangeConfig,
+		c.EmailConfig,
+		content.EmailChangeRepo(),
+		content.UserRepo(),
+		content.GrpcClientManager(),
+	)
+
+	emailChangeController := controller.NewEmailChangeController(
+		content.Logger(),
+		emailChangeService,
+	)
+
+	usernameService := service.NewUsernameService(
+		content.Logger(),
+		c.UsernameConfig,
+		content.UsernameRepo(),
+		content.UserRepo(),
+		content.DivisionRepo(),
+	)
+
+	usernameController := controller.NewUsernameController(
+		content.Logger(),
+		usernameService,
+	)
+
 	authController := controller.NewAuthController(
 		content.Logger(),
 		service.NewAuthService(
 			content.Logger(),
 			content.UserRepo(),
+			content.ProfileRepo(),
+			content.SessionRepo(),
 			content.ClaimFactory(),
+			challengeService,
 		),
 	)
 
+	avatarService := service.NewAvatarService(
+		content.Logger(),
+		c.AvatarConfig,
+		content.AvatarRepo(),
+		content.UserRepo(),
+		content.DivisionRepo(),
+		content.AvatarStorage(),
+		content.GrpcClientManager(),
+	)
+
+	userService := service.NewUserService(
+		content.Logger(),
+		c.DeletionConfig,
+		c.CidConfig,
+		c.AvailabilityConfig,
+		c.EmailConfig,
+		content.UserRepo(),
+		content.DivisionRepo(),
+		content.DeletionRepo(),
+		content.ProfileRepo(),
+		content.ProfileFieldRepo(),
+		content.CidRepo(),
+		content.SessionRepo(),
+		challengeService,
+		emailChangeService,
+		usernameService,
+		avatarService,
+		content.GrpcClientManager(),
+	)
+
 	userController := controller.NewUserController(
 		content.Logger(),
-		service.NewUserService(
-			content.Logger(),
-			content.UserRepo(),
-			content.GrpcClientManager(),
-		),
+		userService,
 	)
 
 	roleController := controller.NewRoleController(
@@ -54,6 +137,8 @@ func StartServer(content *content.ApplicationContent) {
 		service.NewRoleService(
 			content.Logger(),
 			content.RoleRepo(),
+			content.UserRepo(),
+			content.ProfileRepo(),
 			content.GrpcClientManager(),
 		),
 	)
@@ -64,6 +149,101 @@ func StartServer(content *content.ApplicationContent) {
 			content.Logger(),
 			content.UserRepo(),
 			content.RoleRepo(),
+			content.DivisionRepo(),
+			content.GrpcClientManager(),
+		),
+	)
+
+	divisionController := controller.NewDivisionController(
+		content.Logger(),
+		service.NewDivisionService(
+			content.Logger(),
+			content.DivisionRepo(),
+			content.UserRepo(),
+			content.GrpcClientManager(),
+		),
+	)
+
+	authorizationController := controller.NewAuthorizationController(
+		content.Logger(),
+		service.NewAuthorizationService(
+			content.Logger(),
+			c.AuthorizationConfig,
+			content.UserRepo(),
+			content.DivisionRepo(),
+		),
+	)
+
+	profileFieldController := controller.NewProfileFieldController(
+		content.Logger(),
+		service.NewProfileFieldService(
+			content.Logger(),
+			content.ProfileFieldRepo(),
+			content.GrpcClientManager(),
+		),
+	)
+
+	avatarController := controller.NewAvatarController(
+		content.Logger(),
+		avatarService,
+	)
+
+	ratingService := service.NewRatingService(
+		content.Logger(),
+		c.RatingConfig,
+		content.RatingRepo(),
+		content.UserRepo(),
+		content.DivisionRepo(),
+		content.GrpcClientManager(),
+	)
+
+	ratingController := controller.NewRatingController(
+		content.Logger(),
+		ratingService,
+	)
+
+	instructorService := service.NewInstructorService(
+		content.Logger(),
+		c.InstructorConfig,
+		content.InstructorRepo(),
+		content.UserRepo(),
+		content.DivisionRepo(),
+		content.GrpcClientManager(),
+	)
+
+	instructorController := controller.NewInstructorController(
+		content.Logger(),
+		instructorService,
+	)
+
+	dataExportService := service.NewDataExportService(
+		content.Logger(),
+		c.ExportConfig,
+		content.ExportRepo(),
+		content.UserRepo(),
+		content.DivisionRepo(),
+		content.DeletionRepo(),
+		content.ProfileRepo(),
+		content.EmailChangeRepo(),
+		avatarService,
+		ratingService,
+		instructorService,
+		usernameService,
+		content.GrpcClientManager(),
+	)
+
+	dataExportController := controller.NewDataExportController(
+		content.Logger(),
+		dataExportService,
+	)
+
+	cidController := controller.NewCidController(
+		content.Logger(),
+		service.NewCidService(
+			content.Logger(),
+			c.CidConfig,
+			content.CidRepo(),
+			content.UserRepo(),
 			content.GrpcClientManager(),
 		),
 	)
@@ -76,41 +256,122 @@ func StartServer(content *content.ApplicationContent) {
 	userGroup.POST("/token/fsd", authController.UserFsdLogin)
 	userGroup.GET("/token", authController.RefreshToken, jwtMidware, requireRefresh)
 
+	// 人机验证接口
+	challengeGroup := apiGroup.Group("/challenges")
+	challengeGroup.GET("/:endpoint", challengeController.GetChallenge)
+
 	// 用户接口
 	userGroup.POST("", userController.Register)
-	userGroup.GET("", userController.GetPages, jwtMidware, requireNoRefresh)
+	userGroup.GET("", userController.GetPages, jwtMidware, requireNoRefresh, requireSession)
 	userGroup.GET("/availability", userController.CheckAvailability)
 	userGroup.POST("/password", userController.ResetPassword)
-	userGroup.PUT("/password", userController.UpdatePassword, jwtMidware, requireNoRefresh)
-	userGroup.PUT("/:id/ban", userController.Ban, jwtMidware, requireNoRefresh)
-	userGroup.DELETE("/:id/ban", userController.Unban, jwtMidware, requireNoRefresh)
+	userGroup.PUT("/password", userController.UpdatePassword, jwtMidware, requireNoRefresh, requirePasswordSession)
+	userGroup.GET("/email", emailChangeController.GetEmailChange, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.DELETE("/email", emailChangeController.CancelEmailChange, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.POST("/email/confirm", emailChangeController.ConfirmEmailChange)
+	userGroup.POST("/email/revert", emailChangeController.RevertEmailChange)
+	userGroup.PUT("/:id/ban", userController.Ban, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.DELETE("/:id/ban", userController.Unban, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.DELETE("/:id", userController.Delete, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.GET("/:id/rating", ratingController.GetRating, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.PUT("/:id/rating", ratingController.ChangeRating, jwtMidware, requireNoRefresh, requireSession)
 
 	profileGroup := userGroup.Group("/profiles")
-	profileGroup.GET("/self", userController.GetSelfData, jwtMidware, requireNoRefresh)
-	profileGroup.GET("/:id", userController.GetData, jwtMidware, requireNoRefresh)
-	profileGroup.PATCH("/self", userController.UpdateSelfData, jwtMidware, requireNoRefresh)
-	profileGroup.PATCH("/:id", userController.UpdateData, jwtMidware, requireNoRefresh)
+	profileGroup.POST("/public", userController.BatchGetPublicProfile, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.GET("/public/:id", userController.GetPublicProfile, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.GET("/public/cid/:cid", userController.GetPublicProfile, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.GET("/self", userController.GetSelfData, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.GET("/:id", userController.GetData, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.PATCH("/self", userController.UpdateSelfData, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.PATCH("/:id", userController.UpdateData, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.POST("/self/deletion", userController.RequestDeletion, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.GET("/self/deletion", userController.GetDeletion, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.DELETE("/self/deletion", userController.CancelDeletion, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.POST("/self/export", dataExportController.RequestExport, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.GET("/self/export", dataExportController.GetExport, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.GET("/self/export/download", dataExportController.Download, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.GET("/self/usernames", usernameController.GetSelfUsernameHistory, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.GET("/:id/usernames", usernameController.GetUsernameHistory, jwtMidware, requireNoRefresh, requireSession)
+
+	// 头像接口
+	profileGroup.POST("/self/avatar", avatarController.Upload, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.DELETE("/self/avatar", avatarController.ClearAvatar, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.GET("/self/avatars", avatarController.GetSelfAvatars, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.PUT("/self/avatars/:id", avatarController.SelectAvatar, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.GET("/:id/avatars", avatarController.GetUserAvatars, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.DELETE("/:id/avatars/:avatar_id", avatarController.RemoveAvatar, jwtMidware, requireNoRefresh, requireSession)
+
+	avatarGroup := apiGroup.Group("/avatars")
+	avatarGroup.GET("/identicon/:cid", avatarController.GetIdenticon)
+	avatarGroup.GET("/:file", avatarController.GetFile)
+
+	// 教员接口
+	profileGroup.GET("/self/instructor", instructorController.GetSelfInstructor, jwtMidware, requireNoRefresh, requireSession)
+	profileGroup.GET("/self/trainees", instructorController.GetSelfTrainees, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.GET("/:id/instructor", instructorController.GetUserInstructor, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.PUT("/:id/instructor", instructorController.AssignInstructor, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.DELETE("/:id/instructor", instructorController.UnassignInstructor, jwtMidware, requireNoRefresh, requireSession)
+
+	// 呼号接口
+	cidGroup := apiGroup.Group("/cids")
+	cidGroup.GET("/reservations", cidController.GetReservations, jwtMidware, requireNoRefresh, requireSession)
+	cidGroup.POST("/reservations", cidController.Reserve, jwtMidware, requireNoRefresh, requireSession)
+	cidGroup.DELETE("/reservations/:cid", cidController.Release, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.PUT("/:id/cid", cidController.AssignCid, jwtMidware, requireNoRefresh, requireSession)
 
 	// 角色接口
 	roleGroup := apiGroup.Group("/roles")
-	roleGroup.GET("", roleController.GetPages, jwtMidware, requireNoRefresh)
-	roleGroup.GET("/:id", roleController.GetById, jwtMidware, requireNoRefresh)
-	roleGroup.POST("", roleController.Create, jwtMidware, requireNoRefresh)
-	roleGroup.PATCH("/:id", roleController.Update, jwtMidware, requireNoRefresh)
-	roleGroup.DELETE("/:id", roleController.Delete, jwtMidware, requireNoRefresh)
+	roleGroup.GET("", roleController.GetPages, jwtMidware, requireNoRefresh, requireSession)
+	roleGroup.GET("/export", roleController.Export, jwtMidware, requireNoRefresh, requireSession)
+	roleGroup.POST("/import", roleController.Import, jwtMidware, requireNoRefresh, requireSession)
+	roleGroup.GET("/:id", roleController.GetById, jwtMidware, requireNoRefresh, requireSession)
+	roleGroup.GET("/:id/users", roleController.GetUsers, jwtMidware, requireNoRefresh, requireSession)
+	roleGroup.POST("", roleController.Create, jwtMidware, requireNoRefresh, requireSession)
+	roleGroup.PATCH("/:id", roleController.Update, jwtMidware, requireNoRefresh, requireSession)
+	roleGroup.DELETE("/:id", roleController.Delete, jwtMidware, requireNoRefresh, requireSession)
+	roleGroup.GET("/:id/deletion-impact", roleController.GetDeletionImpact, jwtMidware, requireNoRefresh, requireSession)
 
 	// 权限接口
-	userGroup.PATCH("/:id/permissions", permissionController.EditUserPermission, jwtMidware, requireNoRefresh)
-	userGroup.PATCH("/:id/roles", permissionController.GrantUserRole, jwtMidware, requireNoRefresh)
-	userGroup.DELETE("/:id/roles", permissionController.RevokeUserRole, jwtMidware, requireNoRefresh)
+	userGroup.PATCH("/:id/permissions", permissionController.EditUserPermission, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.PATCH("/:id/roles", permissionController.GrantUserRole, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.DELETE("/:id/roles", permissionController.RevokeUserRole, jwtMidware, requireNoRefresh, requireSession)
+
+	roleGroup.PATCH("/:id/permissions", permissionController.EditRolePermission, jwtMidware, requireNoRefresh, requireSession)
+	roleGroup.PATCH("/:id/users", permissionController.GrantRoleUser, jwtMidware, requireNoRefresh, requireSession)
+	roleGroup.DELETE("/:id/users", permissionController.RevokeRoleUser, jwtMidware, requireNoRefresh, requireSession)
+	roleGroup.PUT("/:id/users", permissionController.SetRoleUsers, jwtMidware, requireNoRefresh, requireSession)
+
+	permissionGroup := apiGroup.Group("/permissions")
+	permissionGroup.GET("", permissionController.GetCatalog)
+	permissionGroup.GET("/decode", permissionController.DecodePermission)
+	permissionGroup.POST("/check", authorizationController.CheckPermission, jwtMidware, requireNoRefresh, requireSession)
+	permissionGroup.POST("/check/batch", authorizationController.BatchCheckPermission, jwtMidware, requireNoRefresh, requireSession)
 
-	roleGroup.PATCH("/:id/permissions", permissionController.EditRolePermission, jwtMidware, requireNoRefresh)
-	roleGroup.PATCH("/:id/users", permissionController.GrantRoleUser, jwtMidware, requireNoRefresh)
-	roleGroup.DELETE("/:id/users", permissionController.RevokeRoleUser, jwtMidware, requireNoRefresh)
+	// 分区接口
+	divisionGroup := apiGroup.Group("/divisions")
+	divisionGroup.GET("", divisionController.GetPages, jwtMidware, requireNoRefresh, requireSession)
+	divisionGroup.GET("/:id", divisionController.GetById, jwtMidware, requireNoRefresh, requireSession)
+	divisionGroup.POST("", divisionController.Create, jwtMidware, requireNoRefresh, requireSession)
+	divisionGroup.PATCH("/:id", divisionController.Update, jwtMidware, requireNoRefresh, requireSession)
+	divisionGroup.DELETE("/:id", divisionController.Delete, jwtMidware, requireNoRefresh, requireSession)
+	divisionGroup.PATCH("/:id/users", divisionController.AddMembers, jwtMidware, requireNoRefresh, requireSession)
+	divisionGroup.DELETE("/:id/users", divisionController.RemoveMembers, jwtMidware, requireNoRefresh, requireSession)
+	divisionGroup.PATCH("/:id/users/:user_id/roles", permissionController.GrantScopedRole, jwtMidware, requireNoRefresh, requireSession)
+	divisionGroup.DELETE("/:id/users/:user_id/roles", permissionController.RevokeScopedRole, jwtMidware, requireNoRefresh, requireSession)
+	userGroup.GET("/:id/divisions", divisionController.GetUserDivisions, jwtMidware, requireNoRefresh, requireSession)
+
+	// 自定义资料字段接口
+	profileFieldGroup := apiGroup.Group("/profile-fields")
+	profileFieldGroup.GET("", profileFieldController.GetFields, jwtMidware, requireNoRefresh, requireSession)
+	profileFieldGroup.POST("", profileFieldController.Create, jwtMidware, requireNoRefresh, requireSession)
+	profileFieldGroup.PATCH("/:id", profileFieldController.Update, jwtMidware, requireNoRefresh, requireSession)
+	profileFieldGroup.DELETE("/:id", profileFieldController.Delete, jwtMidware, requireNoRefresh, requireSession)
 
 	http.SetHealthPoint(e)
 	http.SetUnmatchedRoute(e)
 	http.SetCleaner(content.Cleaner(), e)
 
+	go StartDeletionJob(content, userService, dataExportService)
+
 	http.Serve(lg, e, c.ServerConfig.HttpServerConfig)
 }
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"time"
+	c "user-service/src/interfaces/config"
 	"user-service/src/interfaces/repository"
 	DTO "user-service/src/interfaces/server/dto"
 	"user-service/src/interfaces/server/service"
@@ -19,30 +20,48 @@ import (
 	"half-nothing.cn/service-core/interfaces/logger"
 )
 
+var (
+	ErrSessionRevoked = dto.NewApiStatus("SESSION_REVOKED", "登录状态已失效, 请重新登录", dto.HttpCodeUnauthorized)
+)
+
 type AuthService struct {
 	logger       logger.Interface
 	userRepo     repository.UserInterface
+	profileRepo  repository.ProfileInterface
+	sessionRepo  repository.SessionInterface
 	claimFactory jwt.ClaimFactoryInterface
+	challenge    *ChallengeService
 }
 
 func NewAuthService(
 	lg logger.Interface,
 	userRepo repository.UserInterface,
+	profileRepo repository.ProfileInterface,
+	sessionRepo repository.SessionInterface,
 	claimFactory jwt.ClaimFactoryInterface,
+	challenge *ChallengeService,
 ) *AuthService {
 	return &AuthService{
 		logger:       logger.NewLoggerAdapter(lg, "user-service"),
 		userRepo:     userRepo,
+		profileRepo:  profileRepo,
+		sessionRepo:  sessionRepo,
 		claimFactory: claimFactory,
+		challenge:    challenge,
 	}
 }
 
 func (s *AuthService) Login(form *DTO.UserLogin) *dto.ApiResponse[*DTO.UserLoginResponse] {
+	if status := s.challenge.check(c.ChallengeEndpointLogin, form.Ip, form.Challenge); status != nil {
+		return dto.NewApiResponse[*DTO.UserLoginResponse](status, nil)
+	}
+
 	userId := repository.GetUserId(form.Username)
 	user, err := userId.GetUser(s.userRepo)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			s.logger.Errorf("UserLogin handle fail, %s user not found", form.Username)
+			s.challenge.fail(c.ChallengeEndpointLogin, form.Ip)
 			return dto.NewApiResponse[*DTO.UserLoginResponse](service.ErrUsernameOrPasswordError, nil)
 		}
 		s.logger.Errorf("UserLogin handle fail, get user err, %v", err)
@@ -74,6 +93,7 @@ func (s *AuthService) Login(form *DTO.UserLogin) *dto.ApiResponse[*DTO.UserLogin
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(form.Password)); err != nil {
+		s.challenge.fail(c.ChallengeEndpointLogin, form.Ip)
 		return dto.NewApiResponse[*DTO.UserLoginResponse](service.ErrUsernameOrPasswordError, nil)
 	}
 
@@ -98,16 +118,27 @@ func (s *AuthService) Login(form *DTO.UserLogin) *dto.ApiResponse[*DTO.UserLogin
 		s.logger.Errorf("UserLogin handle fail, generate refresh token err, %v", err)
 		return dto.NewApiResponse[*DTO.UserLoginResponse](dto.ErrServerError, nil)
 	}
+	profile, err := getProfile(s.profileRepo, user.ID)
+	if err != nil {
+		s.logger.Errorf("UserLogin handle fail, get profile err, %v", err)
+		return dto.NewApiResponse[*DTO.UserLoginResponse](dto.ErrServerError, nil)
+	}
+	passwordChangeRequired, err := s.sessionRepo.IsPasswordChangeRequired(user.ID)
+	if err != nil {
+		s.logger.Errorf("UserLogin handle fail, get password change requirement err, %v", err)
+		return dto.NewApiResponse[*DTO.UserLoginResponse](dto.ErrServerError, nil)
+	}
 	userModel := &DTO.UserInfo{}
-	userModel.FromUserEntity(user)
+	userModel.FromUserEntity(user, profile)
 
 	return dto.NewApiResponse[*DTO.UserLoginResponse](
 		dto.SuccessHandleRequest,
 		&DTO.UserLoginResponse{
-			User:         userModel,
-			Token:        token,
-			ExpiresIn:    int(s.claimFactory.GetJWTConfig().ExpireDuration / time.Second),
-			RefreshToken: refreshToken,
+			User:                   userModel,
+			Token:                  token,
+			ExpiresIn:              int(s.claimFactory.GetJWTConfig().ExpireDuration / time.Second),
+			RefreshToken:           refreshToken,
+			PasswordChangeRequired: passwordChangeRequired,
 		},
 	)
 }
@@ -148,6 +179,16 @@ func (s *AuthService) FsdLogin(form *DTO.UserFsdLogin) *DTO.UserFsdLoginResponse
 		return &DTO.UserFsdLoginResponse{Success: false, ErrorMsg: "username or password incorrect"}
 	}
 
+	// 需要修改密码的用户只能先登录网页修改密码
+	passwordChangeRequired, err := s.sessionRepo.IsPasswordChangeRequired(user.ID)
+	if err != nil {
+		s.logger.Errorf("FsdLogin handle fail, get password change requirement err, %v", err)
+		return &DTO.UserFsdLoginResponse{Success: false, ErrorMsg: "Server error"}
+	}
+	if passwordChangeRequired {
+		return &DTO.UserFsdLoginResponse{Success: false, ErrorMsg: "password change required, please change your password on the website"}
+	}
+
 	user.LastLoginTime.Valid = true
 	user.LastLoginTime.Time = time.Now()
 	user.LastLoginIP = &form.Ip
@@ -201,6 +242,16 @@ func (s *AuthService) RefreshToken(form *DTO.RefreshToken) *dto.ApiResponse[*DTO
 		return dto.NewApiResponse[*DTO.RefreshTokenResponse](service.ErrUserBanned, nil)
 	}
 
+	// 会话失效前签发的刷新令牌不能再用于刷新, JWT 的签发时间只精确到秒
+	revokedAt, err := s.sessionRepo.GetRevokedAt(user.ID)
+	if err != nil {
+		s.logger.Errorf("RefreshToken handle fail, get session revocation err, %v", err)
+		return dto.NewApiResponse[*DTO.RefreshTokenResponse](dto.ErrServerError, nil)
+	}
+	if form.Raw.IssuedAt != nil && form.Raw.IssuedAt.Before(revokedAt.Truncate(time.Second)) {
+		return dto.NewApiResponse[*DTO.RefreshTokenResponse](ErrSessionRevoked, nil)
+	}
+
 	if err := s.userRepo.Update(user, updates); err != nil {
 		s.logger.Errorf("RefreshToken handle fail, save user err, %v", err)
 		return dto.NewApiResponse[*DTO.RefreshTokenResponse](dto.ErrServerError, nil)
@@ -223,16 +274,27 @@ func (s *AuthService) RefreshToken(form *DTO.RefreshToken) *dto.ApiResponse[*DTO
 		}
 	}
 
+	profile, err := getProfile(s.profileRepo, user.ID)
+	if err != nil {
+		s.logger.Errorf("RefreshToken handle fail, get profile err, %v", err)
+		return dto.NewApiResponse[*DTO.RefreshTokenResponse](dto.ErrServerError, nil)
+	}
+	passwordChangeRequired, err := s.sessionRepo.IsPasswordChangeRequired(user.ID)
+	if err != nil {
+		s.logger.Errorf("RefreshToken handle fail, get password change requirement err, %v", err)
+		return dto.NewApiResponse[*DTO.RefreshTokenResponse](dto.ErrServerError, nil)
+	}
 	userModel := &DTO.UserInfo{}
-	userModel.FromUserEntity(user)
+	userModel.FromUserEntity(user, profile)
 
 	return dto.NewApiResponse[*DTO.RefreshTokenResponse](
 		dto.SuccessHandleRequest,
 		&DTO.RefreshTokenResponse{
-			User:         userModel,
-			Token:        token,
-			ExpiresIn:    int(s.claimFactory.GetJWTConfig().ExpireDuration / time.Second),
-			RefreshToken: refreshToken,
+			User:                   userModel,
+			Token:                  token,
+			ExpiresIn:              int(s.claimFactory.GetJWTConfig().ExpireDuration / time.Second),
+			RefreshToken:           refreshToken,
+			PasswordChangeRequired: passwordChangeRequired,
 		},
 	)
 }
@@ -0,0 +1,315 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	c "user-service/src/interfaces/config"
+	Permission "user-service/src/interfaces/permission"
+	"user-service/src/interfaces/repository"
+	DTO "user-service/src/interfaces/server/dto"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/logger"
+	"half-nothing.cn/service-core/permission"
+)
+
+// 鉴权决策原因
+const (
+	ReasonGranted           = "GRANTED"
+	ReasonGrantedInDivision = "GRANTED_IN_DIVISION"
+	ReasonInvalidRequest    = "INVALID_REQUEST"
+	ReasonInvalidResource   = "INVALID_RESOURCE"
+	ReasonUnknownNode       = "UNKNOWN_PERMISSION_NODE"
+	ReasonSubjectNotFound   = "SUBJECT_NOT_FOUND"
+	ReasonSubjectBanned     = "SUBJECT_BANNED"
+	ReasonMissingPermission = "MISSING_PERMISSION"
+)
+
+const resourceDivisionPrefix = "division:"
+
+var (
+	ErrBatchTooLarge = dto.NewApiStatus("BATCH_TOO_LARGE", "批量鉴权请求数量超出限制", dto.HttpCodeBadRequest)
+)
+
+var ErrBatchSizeExceeded = errors.New("batch size exceeded")
+
+type decisionCacheEntry struct {
+	decision *DTO.AuthorizationDecision
+	expireAt time.Time
+}
+
+// decisionCache 鉴权结果的短期缓存, 角色与权限变更最多延迟一个 TTL 生效
+type decisionCache struct {
+	ttl     time.Duration
+	size    int
+	lock    sync.RWMutex
+	entries map[string]*decisionCacheEntry
+}
+
+func newDecisionCache(ttl time.Duration, size int) *decisionCache {
+	return &decisionCache{
+		ttl:     ttl,
+		size:    size,
+		entries: make(map[string]*decisionCacheEntry),
+	}
+}
+
+func (cache *decisionCache) get(key string) (*DTO.AuthorizationDecision, bool) {
+	if cache.ttl <= 0 {
+		return nil, false
+	}
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+	entry, ok := cache.entries[key]
+	if !ok || entry.expireAt.Before(time.Now()) {
+		return nil, false
+	}
+	return entry.decision, true
+}
+
+func (cache *decisionCache) set(key string, decision *DTO.AuthorizationDecision) {
+	if cache.ttl <= 0 || cache.size <= 0 {
+		return
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	now := time.Now()
+	if len(cache.entries) >= cache.size {
+		for k, entry := range cache.entries {
+			if entry.expireAt.Before(now) {
+				delete(cache.entries, k)
+			}
+		}
+		if len(cache.entries) >= cache.size {
+			return
+		}
+	}
+	cache.entries[key] = &decisionCacheEntry{decision: decision, expireAt: now.Add(cache.ttl)}
+}
+
+type AuthorizationService struct {
+	logger       logger.Interface
+	config       *c.AuthorizationConfig
+	userRepo     repository.UserInterface
+	divisionRepo repository.DivisionInterface
+	scope        *scopeResolver
+	cache        *decisionCache
+}
+
+func NewAuthorizationService(
+	lg logger.Interface,
+	config *c.AuthorizationConfig,
+	userRepo repository.UserInterface,
+	divisionRepo repository.DivisionInterface,
+) *AuthorizationService {
+	adapter := logger.NewLoggerAdapter(lg, "authorization-service")
+	return &AuthorizationService{
+		logger:       adapter,
+		config:       config,
+		userRepo:     userRepo,
+		divisionRepo: divisionRepo,
+		scope:        newScopeResolver(adapter, divisionRepo),
+		cache:        newDecisionCache(config.CacheTTLDuration, config.CacheSize),
+	}
+}
+
+func deny(reason string, missing []string) *DTO.AuthorizationDecision {
+	if missing == nil {
+		missing = make([]string, 0)
+	}
+	return &DTO.AuthorizationDecision{Allowed: false, Reason: reason, Missing: missing}
+}
+
+func allow(reason string, divisionId uint) *DTO.AuthorizationDecision {
+	return &DTO.AuthorizationDecision{Allowed: true, Reason: reason, Missing: make([]string, 0), DivisionId: divisionId}
+}
+
+// isBanned 判断用户当前是否处于封禁状态, 已过期的限时封禁视为未封禁
+func isBanned(user *entity.User) bool {
+	if !user.Banned {
+		return false
+	}
+	return !user.BannedUntil.Valid || user.BannedUntil.Time.After(time.Now())
+}
+
+// parseResource 解析资源标识, 目前仅支持 division:<id>, 空字符串表示不限定资源
+func parseResource(resource string) (uint, bool) {
+	if resource == "" {
+		return 0, true
+	}
+	if !strings.HasPrefix(resource, resourceDivisionPrefix) {
+		return 0, false
+	}
+	divisionId, err := strconv.ParseUint(strings.TrimPrefix(resource, resourceDivisionPrefix), 10, 64)
+	if err != nil || divisionId == 0 {
+		return 0, false
+	}
+	return uint(divisionId), true
+}
+
+func decisionCacheKey(request *DTO.AuthorizationRequest) string {
+	nodes := slices.Clone(request.Permissions)
+	slices.Sort(nodes)
+	return fmt.Sprintf("%d|%d|%s|%s", request.SubjectId, request.TargetUserId, request.Resource, strings.Join(nodes, ","))
+}
+
+func (service *AuthorizationService) Evaluate(request *DTO.AuthorizationRequest) (*DTO.AuthorizationDecision, error) {
+	if request == nil || request.SubjectId == 0 || len(request.Permissions) == 0 {
+		return deny(ReasonInvalidRequest, nil), nil
+	}
+	key := decisionCacheKey(request)
+	if decision, ok := service.cache.get(key); ok {
+		return decision, nil
+	}
+	decision, err := service.evaluate(request)
+	if err != nil {
+		return nil, err
+	}
+	service.cache.set(key, decision)
+	return decision, nil
+}
+
+func (service *AuthorizationService) EvaluateBatch(requests []*DTO.AuthorizationRequest) ([]*DTO.AuthorizationDecision, error) {
+	if len(requests) > service.config.MaxBatchSize {
+		return nil, ErrBatchSizeExceeded
+	}
+	decisions := make([]*DTO.AuthorizationDecision, 0, len(requests))
+	for _, request := range requests {
+		decision, err := service.Evaluate(request)
+		if err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, decision)
+	}
+	return decisions, nil
+}
+
+// evaluate 根据当前数据库中的用户、角色与分区数据计算鉴权结果
+//
+// 全局权限(用户权限与全局角色)包含全部节点时直接允许;
+// 否则在资源指定的分区或目标用户所在的分区中, 依次检查全局权限与分区角色权限的并集
+func (service *AuthorizationService) evaluate(request *DTO.AuthorizationRequest) (*DTO.AuthorizationDecision, error) {
+	nodes := make([]permission.Permission, 0, len(request.Permissions))
+	var required permission.Permission
+	for _, name := range request.Permissions {
+		node, ok := Permission.GetNode(name)
+		if !ok {
+			return deny(ReasonUnknownNode, []string{name}), nil
+		}
+		nodes = append(nodes, node)
+		required.Grant(node)
+	}
+
+	resourceDivision, ok := parseResource(request.Resource)
+	if !ok {
+		return deny(ReasonInvalidResource, nil), nil
+	}
+
+	user, err := service.userRepo.GetById(request.SubjectId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return deny(ReasonSubjectNotFound, nil), nil
+		}
+		return nil, err
+	}
+	if isBanned(user) {
+		return deny(ReasonSubjectBanned, nil), nil
+	}
+
+	perm := Permission.Effective(user)
+	if Permission.Contains(perm, required) {
+		return allow(ReasonGranted, 0), nil
+	}
+
+	candidates := make([]uint, 0)
+	if request.TargetUserId != 0 {
+		divisionIds, err := service.divisionRepo.GetUserDivisionIds(request.TargetUserId)
+		if err != nil {
+			return nil, err
+		}
+		for _, divisionId := range divisionIds {
+			if resourceDivision == 0 || divisionId == resourceDivision {
+				candidates = append(candidates, divisionId)
+			}
+		}
+	} else if resourceDivision != 0 {
+		candidates = append(candidates, resourceDivision)
+	}
+
+	for _, divisionId := range candidates {
+		divisionPerm, err := service.scope.effectiveIn(user, perm, divisionId)
+		if err != nil {
+			return nil, err
+		}
+		if Permission.Contains(divisionPerm, required) {
+			return allow(ReasonGrantedInDivision, divisionId), nil
+		}
+	}
+
+	missing := make([]string, 0)
+	for i, node := range nodes {
+		if !perm.HasPermission(node) {
+			missing = append(missing, request.Permissions[i])
+		}
+	}
+	return deny(ReasonMissingPermission, missing), nil
+}
+
+// checkCaller 检查调用者能否查询目标主体的鉴权结果, 查询自己总是允许的
+func checkCaller[T comparable](resolver *scopeResolver, uid uint, globalPerm uint64, subjectId uint) *dto.ApiResponse[T] {
+	if uid == subjectId {
+		return nil
+	}
+	scope, res := checkScope[T](resolver, uid, globalPerm, permission.UserShowList)
+	if res != nil {
+		return res
+	}
+	return checkUserInScope[T](resolver, scope, subjectId)
+}
+
+func (service *AuthorizationService) CheckPermission(data *DTO.CheckPermission) *dto.ApiResponse[*DTO.AuthorizationDecision] {
+	if res := checkCaller[*DTO.AuthorizationDecision](service.scope, data.Uid, data.Permission, data.SubjectId); res != nil {
+		service.logger.Errorf("user %04d no permission to check permission of user %d", data.Cid, data.SubjectId)
+		return res
+	}
+	decision, err := service.Evaluate(&data.AuthorizationRequest)
+	if err != nil {
+		service.logger.Errorf("CheckPermission handle fail, evaluate err, %v", err)
+		return dto.NewApiResponse[*DTO.AuthorizationDecision](ErrDataBaseError, nil)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, decision)
+}
+
+func (service *AuthorizationService) BatchCheckPermission(data *DTO.BatchCheckPermission) *dto.ApiResponse[*DTO.BatchCheckPermissionResponse] {
+	if len(data.Requests) > service.config.MaxBatchSize {
+		return dto.NewApiResponse[*DTO.BatchCheckPermissionResponse](ErrBatchTooLarge, nil)
+	}
+	checked := make([]uint, 0)
+	for _, request := range data.Requests {
+		if request == nil || slices.Contains(checked, request.SubjectId) {
+			continue
+		}
+		if res := checkCaller[*DTO.BatchCheckPermissionResponse](service.scope, data.Uid, data.Permission, request.SubjectId); res != nil {
+			service.logger.Errorf("user %04d no permission to check permission of user %d", data.Cid, request.SubjectId)
+			return res
+		}
+		checked = append(checked, request.SubjectId)
+	}
+	decisions, err := service.EvaluateBatch(data.Requests)
+	if err != nil {
+		service.logger.Errorf("BatchCheckPermission handle fail, evaluate err, %v", err)
+		return dto.NewApiResponse[*DTO.BatchCheckPermissionResponse](ErrDataBaseError, nil)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, &DTO.BatchCheckPermissionResponse{Decisions: decisions})
+}
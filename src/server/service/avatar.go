@@ -0,0 +1,419 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	c "user-service/src/interfaces/config"
+	"user-service/src/interfaces/content"
+	Entity "user-service/src/interfaces/database/entity"
+	pb "user-service/src/interfaces/grpc"
+	"user-service/src/interfaces/repository"
+	DTO "user-service/src/interfaces/server/dto"
+	"user-service/src/interfaces/storage"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/logger"
+	"half-nothing.cn/service-core/permission"
+	"half-nothing.cn/service-core/utils"
+)
+
+const avatarContentType = "image/png"
+
+var (
+	ErrAvatarTooLarge     = dto.NewApiStatus("AVATAR_TOO_LARGE", "头像文件过大", dto.HttpCodeBadRequest)
+	ErrAvatarFormat       = dto.NewApiStatus("AVATAR_FORMAT_INVALID", "不支持的头像格式", dto.HttpCodeBadRequest)
+	ErrAvatarDimension    = dto.NewApiStatus("AVATAR_DIMENSION_TOO_LARGE", "头像尺寸过大", dto.HttpCodeBadRequest)
+	ErrAvatarNotFound     = dto.NewApiStatus("AVATAR_NOT_FOUND", "头像不存在", dto.HttpCodeNotFound)
+	ErrAvatarRemoved      = dto.NewApiStatus("AVATAR_REMOVED", "头像已被移除", dto.HttpCodeBadRequest)
+	ErrAvatarStorageError = dto.NewApiStatus("AVATAR_STORAGE_ERROR", "头像存储错误", dto.HttpCodeInternalError)
+)
+
+var avatarFileRegexp = regexp.MustCompile(`^([0-9a-f]{32})_(\d+)\.png$`)
+
+// NewAvatarResolver 根据配置生成头像解析链, 依次尝试每个来源, 返回第一个可用的头像地址
+//
+// Gravatar 对没有头像的邮箱也会返回默认图标, 因此排在其后的来源只在用户关闭 Gravatar 时生效
+func NewAvatarResolver(config *c.AvatarConfig) DTO.AvatarResolver {
+	providers := make([]DTO.AvatarResolver, 0, len(config.Providers))
+	for _, provider := range config.Providers {
+		switch provider {
+		case c.AvatarProviderUploaded:
+			providers = append(providers, func(user *entity.User, _ *Entity.UserProfile) string {
+				if user.CurrentAvatar == nil {
+					return ""
+				}
+				return user.CurrentAvatar.Url
+			})
+		case c.AvatarProviderQQ:
+			providers = append(providers, func(user *entity.User, profile *Entity.UserProfile) string {
+				if user.QQ == nil || *user.QQ == "" || !profile.AllowQQAvatar {
+					return ""
+				}
+				return fmt.Sprintf("https://q2.qlogo.cn/headimg_dl?dst_uin=%s&spec=100", *user.QQ)
+			})
+		case c.AvatarProviderGravatar:
+			providers = append(providers, func(user *entity.User, profile *Entity.UserProfile) string {
+				if user.Email == "" || !profile.AllowGravatar {
+					return ""
+				}
+				hash := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(user.Email))))
+				return fmt.Sprintf("https://www.gravatar.com/avatar/%x?s=%d&d=identicon", hash, config.Sizes[0])
+			})
+		case c.AvatarProviderIdenticon:
+			providers = append(providers, func(user *entity.User, _ *Entity.UserProfile) string {
+				return fmt.Sprintf("%s/identicon/%d", config.UrlPrefix, user.Cid)
+			})
+		}
+	}
+	return func(user *entity.User, profile *Entity.UserProfile) string {
+		if profile == nil {
+			profile = Entity.DefaultUserProfile(user.ID)
+		}
+		for _, provider := range providers {
+			if url := provider(user, profile); url != "" {
+				return url
+			}
+		}
+		return ""
+	}
+}
+
+type AvatarService struct {
+	logger   logger.Interface
+	config   *c.AvatarConfig
+	repo     repository.AvatarInterface
+	userRepo repository.UserInterface
+	storage  storage.Interface
+	scope    *scopeResolver
+	limiter  *rateLimiter
+	client   *content.GrpcClientManager
+}
+
+func NewAvatarService(
+	lg logger.Interface,
+	config *c.AvatarConfig,
+	repo repository.AvatarInterface,
+	userRepo repository.UserInterface,
+	divisionRepo repository.DivisionInterface,
+	storage storage.Interface,
+	client *content.GrpcClientManager,
+) *AvatarService {
+	adapter := logger.NewLoggerAdapter(lg, "avatar-service")
+	return &AvatarService{
+		logger:   adapter,
+		config:   config,
+		repo:     repo,
+		userRepo: userRepo,
+		storage:  storage,
+		scope:    newScopeResolver(adapter, divisionRepo),
+		limiter:  newRateLimiter(config.UploadLimit, time.Hour),
+		client:   client,
+	}
+}
+
+func avatarFileKey(key string, size int) string {
+	return fmt.Sprintf("%s_%d.png", key, size)
+}
+
+func (service *AvatarService) avatarInfo(avatar *Entity.Avatar, currentImageId *uint) *DTO.AvatarInfo {
+	info := &DTO.AvatarInfo{
+		Id:            avatar.ID,
+		Sizes:         make(map[string]string, len(service.config.Sizes)),
+		Current:       currentImageId != nil && *currentImageId == avatar.ImageId,
+		Status:        avatar.Status,
+		RemovedReason: avatar.RemovedReason,
+		CreatedAt:     avatar.CreatedAt,
+	}
+	if avatar.Status == Entity.AvatarStatusActive {
+		info.Url = fmt.Sprintf("%s/%s", service.config.UrlPrefix, avatarFileKey(avatar.Key, service.config.Sizes[0]))
+		for _, size := range service.config.Sizes {
+			info.Sizes[strconv.Itoa(size)] = fmt.Sprintf("%s/%s", service.config.UrlPrefix, avatarFileKey(avatar.Key, size))
+		}
+	}
+	return info
+}
+
+func (service *AvatarService) logAudit(event *Entity.AuditEvent, subject uint, object uint, ip string, userAgent string, oldValue string, newValue string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := service.client.AuditLogClient().Log(ctx, &pb.AuditLogRequest{
+		Event:     event.Value,
+		Subject:   fmt.Sprintf("%04d", subject),
+		Object:    fmt.Sprintf("%04d", object),
+		Ip:        ip,
+		UserAgent: userAgent,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+	})
+	if err != nil {
+		service.logger.Errorf("error occurred when log audit: %v", err)
+	}
+}
+
+// deleteFiles 删除头像的所有尺寸文件, 失败时只记录日志
+func (service *AvatarService) deleteFiles(key string) {
+	for _, size := range service.config.Sizes {
+		if err := service.storage.Delete(avatarFileKey(key, size)); err != nil {
+			service.logger.Errorf("error occurred when delete avatar file %s: %v", avatarFileKey(key, size), err)
+		}
+	}
+}
+
+func (service *AvatarService) getUser(userId uint) (*entity.User, *dto.ApiStatus) {
+	user, err := service.userRepo.GetById(userId)
+	if err != nil {
+		service.logger.Errorf("error occurred when get user: %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrDataBaseError
+	}
+	return user, nil
+}
+
+func (service *AvatarService) listAvatars(user *entity.User) (*DTO.AvatarList, *dto.ApiStatus) {
+	avatars, err := service.repo.GetByUser(user.ID)
+	if err != nil {
+		service.logger.Errorf("error occurred when get avatars: %v", err)
+		return nil, ErrDataBaseError
+	}
+	avatarInfos := make([]*DTO.AvatarInfo, len(avatars))
+	utils.ForEach(avatars, func(index int, avatar *Entity.Avatar) {
+		avatarInfos[index] = service.avatarInfo(avatar, user.ImageId)
+	})
+	return &DTO.AvatarList{Avatars: avatarInfos}, nil
+}
+
+// Upload 上传头像, 图片经过内容检测、裁剪缩放并重新编码后保存
+func (service *AvatarService) Upload(data *DTO.UploadAvatar) *dto.ApiResponse[*DTO.AvatarInfo] {
+	if !service.limiter.allow(strconv.Itoa(int(data.Uid))) {
+		return dto.NewApiResponse[*DTO.AvatarInfo](ErrTooManyRequests, nil)
+	}
+	if int64(len(data.Data)) > service.config.MaxSizeBytes {
+		return dto.NewApiResponse[*DTO.AvatarInfo](ErrAvatarTooLarge, nil)
+	}
+	img, err := decodeAvatar(data.Data, service.config.MaxDimension)
+	if err != nil {
+		service.logger.Errorf("Upload handle fail, decode image err, %v", err)
+		if errors.Is(err, errImageDimension) {
+			return dto.NewApiResponse[*DTO.AvatarInfo](ErrAvatarDimension, nil)
+		}
+		return dto.NewApiResponse[*DTO.AvatarInfo](ErrAvatarFormat, nil)
+	}
+	files, err := renderAvatar(img, service.config.Sizes)
+	if err != nil {
+		service.logger.Errorf("Upload handle fail, render image err, %v", err)
+		return dto.NewApiResponse[*DTO.AvatarInfo](ErrAvatarFormat, nil)
+	}
+
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		service.logger.Errorf("Upload handle fail, generate key err, %v", err)
+		return dto.NewApiResponse[*DTO.AvatarInfo](ErrAvatarStorageError, nil)
+	}
+	key := hex.EncodeToString(random)
+	for size, file := range files {
+		if err := service.storage.Put(avatarFileKey(key, size), file); err != nil {
+			service.logger.Errorf("Upload handle fail, save avatar file err, %v", err)
+			service.deleteFiles(key)
+			return dto.NewApiResponse[*DTO.AvatarInfo](ErrAvatarStorageError, nil)
+		}
+	}
+
+	avatar := &Entity.Avatar{
+		UserId:      data.Uid,
+		Key:         key,
+		ContentType: avatarContentType,
+		Size:        int64(len(files[service.config.Sizes[0]])),
+		Status:      Entity.AvatarStatusActive,
+	}
+	url := fmt.Sprintf("%s/%s", service.config.UrlPrefix, avatarFileKey(key, service.config.Sizes[0]))
+	pruned, err := service.repo.Create(avatar, url, service.config.MaxHistory)
+	if err != nil {
+		service.logger.Errorf("Upload handle fail, save avatar err, %v", err)
+		service.deleteFiles(key)
+		return dto.NewApiResponse[*DTO.AvatarInfo](ErrDataBaseError, nil)
+	}
+	for _, old := range pruned {
+		if old.Status == Entity.AvatarStatusActive {
+			service.deleteFiles(old.Key)
+		}
+	}
+
+	go service.logAudit(Entity.AuditEventAvatarUploaded, data.Cid, data.Cid, data.Ip, data.UserAgent, "", url)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, service.avatarInfo(avatar, &avatar.ImageId))
+}
+
+func (service *AvatarService) GetSelfAvatars(data *DTO.GetSelfAvatars) *dto.ApiResponse[*DTO.AvatarList] {
+	user, status := service.getUser(data.Uid)
+	if status != nil {
+		return dto.NewApiResponse[*DTO.AvatarList](status, nil)
+	}
+	list, status := service.listAvatars(user)
+	if status != nil {
+		return dto.NewApiResponse[*DTO.AvatarList](status, nil)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, list)
+}
+
+// checkImage 检查图片是否为用户自己上传且未被移除的头像
+func (service *AvatarService) checkImage(userId uint, imageId uint) *dto.ApiStatus {
+	if _, err := service.repo.GetActiveByImage(userId, imageId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAvatarNotFound
+		}
+		service.logger.Errorf("error occurred when get avatar by image: %v", err)
+		return ErrDataBaseError
+	}
+	return nil
+}
+
+// SelectAvatar 从头像历史中选择当前头像
+func (service *AvatarService) SelectAvatar(data *DTO.SelectAvatar) *dto.ApiResponse[bool] {
+	avatar, err := service.repo.GetById(data.Id)
+	if err != nil {
+		service.logger.Errorf("SelectAvatar handle fail, get avatar err, %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse(ErrAvatarNotFound, false)
+		}
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if avatar.UserId != data.Uid {
+		return dto.NewApiResponse(ErrAvatarNotFound, false)
+	}
+	if avatar.Status != Entity.AvatarStatusActive {
+		return dto.NewApiResponse(ErrAvatarRemoved, false)
+	}
+	if err := service.repo.SetCurrent(data.Uid, &avatar.ImageId); err != nil {
+		service.logger.Errorf("SelectAvatar handle fail, set current avatar err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+// ClearAvatar 取消当前头像, 恢复为默认头像
+func (service *AvatarService) ClearAvatar(data *DTO.ClearAvatar) *dto.ApiResponse[bool] {
+	if err := service.repo.SetCurrent(data.Uid, nil); err != nil {
+		service.logger.Errorf("ClearAvatar handle fail, clear current avatar err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+func (service *AvatarService) GetUserAvatars(data *DTO.GetUserAvatars) *dto.ApiResponse[*DTO.AvatarList] {
+	scope, res := checkScope[*DTO.AvatarList](service.scope, data.Uid, data.Permission, permission.UserShowList)
+	if res != nil {
+		service.logger.Errorf("user %04d no permission to get user avatars", data.Cid)
+		return res
+	}
+	user, status := service.getUser(data.Id)
+	if status != nil {
+		return dto.NewApiResponse[*DTO.AvatarList](status, nil)
+	}
+	if res := checkUserInScope[*DTO.AvatarList](service.scope, scope, user.ID); res != nil {
+		service.logger.Errorf("user %04d no permission to get avatars of user %04d", data.Cid, user.Cid)
+		return res
+	}
+	list, status := service.listAvatars(user)
+	if status != nil {
+		return dto.NewApiResponse[*DTO.AvatarList](status, nil)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, list)
+}
+
+// RemoveAvatar 管理员移除不当头像, 头像文件会被删除, 记录保留在历史中
+func (service *AvatarService) RemoveAvatar(data *DTO.RemoveAvatar) *dto.ApiResponse[bool] {
+	scope, res := checkScope[bool](service.scope, data.Uid, data.Permission, permission.UserEditInfo)
+	if res != nil {
+		service.logger.Errorf("user %04d no permission to remove avatar", data.Cid)
+		return res
+	}
+	user, status := service.getUser(data.Id)
+	if status != nil {
+		return dto.NewApiResponse(status, false)
+	}
+	if res := checkUserInScope[bool](service.scope, scope, user.ID); res != nil {
+		service.logger.Errorf("user %04d no permission to remove avatar of user %04d", data.Cid, user.Cid)
+		return res
+	}
+	avatar, err := service.repo.GetById(data.AvatarId)
+	if err != nil {
+		service.logger.Errorf("RemoveAvatar handle fail, get avatar err, %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse(ErrAvatarNotFound, false)
+		}
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if avatar.UserId != user.ID {
+		return dto.NewApiResponse(ErrAvatarNotFound, false)
+	}
+	if avatar.Status != Entity.AvatarStatusActive {
+		return dto.NewApiResponse(ErrAvatarRemoved, false)
+	}
+	if err := service.repo.Remove(avatar, data.Uid, data.Reason); err != nil {
+		service.logger.Errorf("RemoveAvatar handle fail, remove avatar err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	service.deleteFiles(avatar.Key)
+
+	go service.logAudit(Entity.AuditEventAvatarRemoved, data.Cid, user.Cid, data.Ip, data.UserAgent, avatar.Key, data.Reason)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+// GetFile 读取头像文件, 已移除的头像不再提供访问
+func (service *AvatarService) GetFile(data *DTO.GetAvatarFile) (*DTO.AvatarFile, *dto.ApiResponse[bool]) {
+	matches := avatarFileRegexp.FindStringSubmatch(data.File)
+	if matches == nil {
+		return nil, dto.NewApiResponse(ErrAvatarNotFound, false)
+	}
+	size, err := strconv.Atoi(matches[2])
+	if err != nil || !slices.Contains(service.config.Sizes, size) {
+		return nil, dto.NewApiResponse(ErrAvatarNotFound, false)
+	}
+	avatar, err := service.repo.GetByKey(matches[1])
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.NewApiResponse(ErrAvatarNotFound, false)
+		}
+		service.logger.Errorf("GetFile handle fail, get avatar err, %v", err)
+		return nil, dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if avatar.Status != Entity.AvatarStatusActive {
+		return nil, dto.NewApiResponse(ErrAvatarNotFound, false)
+	}
+	file, err := service.storage.Get(data.File)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, dto.NewApiResponse(ErrAvatarNotFound, false)
+		}
+		service.logger.Errorf("GetFile handle fail, read avatar file err, %v", err)
+		return nil, dto.NewApiResponse(ErrAvatarStorageError, false)
+	}
+	return &DTO.AvatarFile{ContentType: avatar.ContentType, Content: file}, nil
+}
+
+func (service *AvatarService) GetIdenticon(data *DTO.GetIdenticon) (*DTO.AvatarFile, *dto.ApiResponse[bool]) {
+	file, err := renderIdenticon(data.Cid, service.config.Sizes[0])
+	if err != nil {
+		service.logger.Errorf("GetIdenticon handle fail, render identicon err, %v", err)
+		return nil, dto.NewApiResponse(dto.ErrServerError, false)
+	}
+	return &DTO.AvatarFile{ContentType: avatarContentType, Content: file}, nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"context"
+	"user-service/src/interfaces/challenge"
+	c "user-service/src/interfaces/config"
+	DTO "user-service/src/interfaces/server/dto"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+var (
+	ErrChallengeRequired    = dto.NewApiStatus("CHALLENGE_REQUIRED", "请先完成人机验证", dto.HttpCodeBadRequest)
+	ErrChallengeInvalid     = dto.NewApiStatus("CHALLENGE_INVALID", "人机验证无效或已过期", dto.HttpCodeBadRequest)
+	ErrChallengeUnavailable = dto.NewApiStatus("CHALLENGE_UNAVAILABLE", "人机验证服务暂不可用, 请稍后再试", dto.HttpCodeInternalError)
+)
+
+// ChallengeService 按接口与风险信号决定是否要求人机验证
+//
+// 同一IP在窗口内的失败次数达到阈值后才要求验证, 阈值为 0 时每次请求都要求验证,
+// 成功的请求不会清除失败记录, 避免攻击者穿插使用自己的账户重置计数
+type ChallengeService struct {
+	logger   logger.Interface
+	config   *c.ChallengeConfig
+	verifier challenge.Interface
+	failures map[string]*failureCounter
+}
+
+// NewChallengeService 创建人机验证服务, verifier 为 nil 时不要求任何验证
+func NewChallengeService(
+	lg logger.Interface,
+	config *c.ChallengeConfig,
+	verifier challenge.Interface,
+) *ChallengeService {
+	failures := make(map[string]*failureCounter, len(config.Endpoints))
+	for name, endpoint := range config.Endpoints {
+		if endpoint.Enable && endpoint.FailureThreshold > 0 {
+			failures[name] = newFailureCounter(endpoint.FailureWindowDuration)
+		}
+	}
+	return &ChallengeService{
+		logger:   logger.NewLoggerAdapter(lg, "challenge-service"),
+		config:   config,
+		verifier: verifier,
+		failures: failures,
+	}
+}
+
+// required 判断该IP请求接口时是否需要人机验证
+func (service *ChallengeService) required(name string, ip string) bool {
+	endpoint, ok := service.config.Endpoints[name]
+	if service.verifier == nil || !ok || !endpoint.Enable {
+		return false
+	}
+	if endpoint.FailureThreshold == 0 {
+		return true
+	}
+	return service.failures[name].count(ip) >= endpoint.FailureThreshold
+}
+
+// check 在需要时校验人机验证令牌
+func (service *ChallengeService) check(name string, ip string, token string) *dto.ApiStatus {
+	if !service.required(name, ip) {
+		return nil
+	}
+	if token == "" {
+		return ErrChallengeRequired
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), service.config.TimeoutDuration)
+	defer cancel()
+	ok, err := service.verifier.Verify(ctx, token, ip)
+	if err != nil {
+		service.logger.Errorf("error occurred when verify challenge: %v", err)
+		return ErrChallengeUnavailable
+	}
+	if !ok {
+		return ErrChallengeInvalid
+	}
+	return nil
+}
+
+// fail 记录一次失败的请求
+func (service *ChallengeService) fail(name string, ip string) {
+	if counter, ok := service.failures[name]; ok {
+		counter.add(ip)
+	}
+}
+
+func (service *ChallengeService) GetChallenge(data *DTO.GetChallenge) *dto.ApiResponse[*DTO.ChallengeInfo] {
+	switch data.Endpoint {
+	case c.ChallengeEndpointRegister, c.ChallengeEndpointLogin, c.ChallengeEndpointResetPassword, c.ChallengeEndpointAvailability:
+	default:
+		return dto.NewApiResponse[*DTO.ChallengeInfo](dto.ErrErrorParam, nil)
+	}
+	info := &DTO.ChallengeInfo{
+		Required: service.required(data.Endpoint, data.Ip),
+		Provider: service.config.Provider,
+	}
+	if !info.Required {
+		return dto.NewApiResponse(dto.SuccessHandleRequest, info)
+	}
+	info.SiteKey = service.config.SiteKey
+	if issuer, ok := service.verifier.(challenge.Issuer); ok {
+		challenge, expiresAt := issuer.Issue()
+		info.Challenge = challenge
+		info.Difficulty = issuer.Difficulty()
+		info.ExpiresAt = &expiresAt
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, info)
+}
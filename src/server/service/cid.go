@@ -0,0 +1,181 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	c "user-service/src/interfaces/config"
+	"user-service/src/interfaces/content"
+	Entity "user-service/src/interfaces/database/entity"
+	pb "user-service/src/interfaces/grpc"
+	Permission "user-service/src/interfaces/permission"
+	"user-service/src/interfaces/repository"
+	DTO "user-service/src/interfaces/server/dto"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/logger"
+	"half-nothing.cn/service-core/permission"
+	"half-nothing.cn/service-core/utils"
+)
+
+var (
+	ErrCidTaken          = dto.NewApiStatus("CID_TAKEN", "呼号已被使用或已被保留", dto.HttpCodeConflict)
+	ErrCidNotReserved    = dto.NewApiStatus("CID_NOT_RESERVED", "呼号未被保留", dto.HttpCodeNotFound)
+	ErrCidReservationUse = dto.NewApiStatus("CID_RESERVATION_IN_USE", "保留呼号已分配给用户, 无法释放", dto.HttpCodeBadRequest)
+	ErrCidUnchanged      = dto.NewApiStatus("CID_UNCHANGED", "呼号未发生变化", dto.HttpCodeBadRequest)
+)
+
+type CidService struct {
+	logger   logger.Interface
+	config   *c.CidConfig
+	repo     repository.CidInterface
+	userRepo repository.UserInterface
+	client   *content.GrpcClientManager
+}
+
+func NewCidService(
+	lg logger.Interface,
+	config *c.CidConfig,
+	repo repository.CidInterface,
+	userRepo repository.UserInterface,
+	client *content.GrpcClientManager,
+) *CidService {
+	return &CidService{
+		logger:   logger.NewLoggerAdapter(lg, "cid-service"),
+		config:   config,
+		repo:     repo,
+		userRepo: userRepo,
+		client:   client,
+	}
+}
+
+func (service *CidService) logAudit(event *Entity.AuditEvent, subject uint, object uint, ip string, userAgent string, oldValue string, newValue string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := service.client.AuditLogClient().Log(ctx, &pb.AuditLogRequest{
+		Event:     event.Value,
+		Subject:   fmt.Sprintf("%04d", subject),
+		Object:    fmt.Sprintf("%04d", object),
+		Ip:        ip,
+		UserAgent: userAgent,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+	})
+	if err != nil {
+		service.logger.Errorf("error occurred when log audit: %v", err)
+	}
+}
+
+func (service *CidService) GetReservations(data *DTO.GetCidReservations) *dto.ApiResponse[*DTO.CidAllocationInfo] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(Permission.CidManage) {
+		service.logger.Errorf("user %04d no permission to get cid reservations", data.Cid)
+		return dto.NewApiResponse[*DTO.CidAllocationInfo](dto.ErrNoPermission, nil)
+	}
+	reservations, err := service.repo.GetReservations()
+	if err != nil {
+		service.logger.Errorf("GetReservations handle fail, get reservations err, %v", err)
+		return dto.NewApiResponse[*DTO.CidAllocationInfo](ErrDataBaseError, nil)
+	}
+	info := (&DTO.CidAllocationInfo{}).FromConfig(service.config)
+	info.Reservations = make([]*DTO.CidReservationInfo, len(reservations))
+	utils.ForEach(reservations, func(index int, reservation *Entity.CidReservation) {
+		info.Reservations[index] = (&DTO.CidReservationInfo{}).FromEntity(reservation)
+	})
+	return dto.NewApiResponse(dto.SuccessHandleRequest, info)
+}
+
+// Reserve 保留指定呼号, 保留的呼号不会在注册时自动分配
+func (service *CidService) Reserve(data *DTO.ReserveCid) *dto.ApiResponse[bool] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(Permission.CidManage) {
+		service.logger.Errorf("user %04d no permission to reserve cid", data.Cid)
+		return dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+	used, err := service.repo.IsUsed(data.TargetCid)
+	if err != nil {
+		service.logger.Errorf("Reserve handle fail, check cid err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if used {
+		return dto.NewApiResponse(ErrCidTaken, false)
+	}
+	reservation := &Entity.CidReservation{
+		Cid:        data.TargetCid,
+		Comment:    data.Comment,
+		ReservedBy: data.Uid,
+	}
+	if err := service.repo.Save(reservation); err != nil {
+		service.logger.Errorf("Reserve handle fail, save reservation err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go service.logAudit(Entity.AuditEventCidReserved, data.Cid, data.TargetCid, data.Ip, data.UserAgent, "", data.Comment)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+func (service *CidService) Release(data *DTO.ReleaseCid) *dto.ApiResponse[bool] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(Permission.CidManage) {
+		service.logger.Errorf("user %04d no permission to release cid", data.Cid)
+		return dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+	reservation, err := service.repo.GetReservation(data.TargetCid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse(ErrCidNotReserved, false)
+		}
+		service.logger.Errorf("Release handle fail, get reservation err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if reservation.UserId != nil {
+		return dto.NewApiResponse(ErrCidReservationUse, false)
+	}
+	if err := service.repo.Release(data.TargetCid); err != nil {
+		service.logger.Errorf("Release handle fail, release cid err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go service.logAudit(Entity.AuditEventCidReleased, data.Cid, data.TargetCid, data.Ip, data.UserAgent, reservation.Comment, "")
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+// AssignCid 为用户指定呼号, 可以指定保留号段或管理员保留的呼号
+func (service *CidService) AssignCid(data *DTO.AssignCid) *dto.ApiResponse[bool] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(Permission.CidManage) {
+		service.logger.Errorf("user %04d no permission to assign cid", data.Cid)
+		return dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+	user, err := service.userRepo.GetById(data.Id)
+	if err != nil {
+		service.logger.Errorf("AssignCid handle fail, get user err, %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse(ErrUserNotFound, false)
+		}
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if user.Cid == data.TargetCid {
+		return dto.NewApiResponse(ErrCidUnchanged, false)
+	}
+	if err := service.repo.AssignCid(user.ID, data.TargetCid); err != nil {
+		service.logger.Errorf("AssignCid handle fail, assign cid err, %v", err)
+		if errors.Is(err, repository.ErrCidTaken) {
+			return dto.NewApiResponse(ErrCidTaken, false)
+		}
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go service.logAudit(Entity.AuditEventCidAssigned, data.Cid, data.TargetCid, data.Ip, data.UserAgent,
+		fmt.Sprintf("%04d", user.Cid), fmt.Sprintf("%04d (%s)", data.TargetCid, data.Reason))
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+	pb "user-service/src/interfaces/grpc"
+	Permission "user-service/src/interfaces/permission"
+	"user-service/src/interfaces/repository"
+	DTO "user-service/src/interfaces/server/dto"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/permission"
+	"half-nothing.cn/service-core/utils"
+)
+
+var (
+	ErrDeletionPending  = dto.NewApiStatus("DELETION_PENDING", "已有待处理的注销申请", dto.HttpCodeConflict)
+	ErrDeletionNotFound = dto.NewApiStatus("DELETION_NOT_FOUND", "没有待处理的注销申请", dto.HttpCodeNotFound)
+	ErrUserDeleted      = dto.NewApiStatus("USER_DELETED", "用户已注销", dto.HttpCodeBadRequest)
+	ErrDeleteSelf       = dto.NewApiStatus("DELETE_SELF", "不能删除自己, 请使用账户注销功能", dto.HttpCodeBadRequest)
+)
+
+// anonymizedUser 注销后用户的匿名化数据, 呼号保持不变以保证审计记录可追溯
+func anonymizedUser(cid uint) map[string]interface{} {
+	return map[string]interface{}{
+		"username":      fmt.Sprintf("deleted_%04d", cid),
+		"email":         fmt.Sprintf("deleted_%04d@deleted.invalid", cid),
+		"qq":            nil,
+		"image_id":      nil,
+		"last_login_ip": nil,
+		"password":      "",
+		"permission":    0,
+	}
+}
+
+// getPendingDeletion 获取用户待处理的注销申请, 用户已注销时返回 ErrUserDeleted
+func (u *UserService) getPendingDeletion(userId uint) (*Entity.AccountDeletion, *dto.ApiStatus) {
+	deletion, err := u.deletionRepo.GetLatest(userId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		u.logger.Errorf("error occurred when get account deletion: %v", err)
+		return nil, ErrDataBaseError
+	}
+	switch deletion.Status {
+	case Entity.DeletionStatusPending:
+		return deletion, nil
+	case Entity.DeletionStatusCompleted:
+		return nil, ErrUserDeleted
+	default:
+		return nil, nil
+	}
+}
+
+func (u *UserService) logDeletionAudit(event *Entity.AuditEvent, subject uint, object uint, ip string, userAgent string, reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := u.client.AuditLogClient().Log(ctx, &pb.AuditLogRequest{
+		Event:     event.Value,
+		Subject:   fmt.Sprintf("%04d", subject),
+		Object:    fmt.Sprintf("%04d", object),
+		Ip:        ip,
+		UserAgent: userAgent,
+		NewValue:  reason,
+	})
+	if err != nil {
+		u.logger.Errorf("error occurred when log audit: %v", err)
+	}
+}
+
+// sendDeletionEmail 通知用户注销申请已提交或账户已注销, 管理员操作时附带操作者信息
+func (u *UserService) sendDeletionEmail(email string, deletion *Entity.AccountDeletion, operator *entity.User) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	request := &pb.AccountDeletion{
+		TargetEmail: email,
+		Cid:         fmt.Sprintf("%04d", deletion.Cid),
+		Status:      deletion.Status,
+		Reason:      deletion.Reason,
+		ScheduledAt: deletion.ScheduledAt.Format(time.RFC3339),
+	}
+	if operator != nil && operator.ID != deletion.UserId {
+		request.Operator = fmt.Sprintf("%04d", operator.Cid)
+		request.Contact = operator.Email
+	}
+	if _, err := u.client.EmailClient().SendAccountDeletion(ctx, request); err != nil {
+		u.logger.Errorf("error occurred when send account deletion email: %v", err)
+	}
+}
+
+// completeDeletion 匿名化注销申请对应的用户数据并使其所有会话失效, 完成后通知用户的原邮箱
+//
+// 注销请求已被其他实例处理时返回 repository.ErrDeletionClosed
+func (u *UserService) completeDeletion(deletion *Entity.AccountDeletion, user *entity.User, operator *entity.User, ip string, userAgent string) error {
+	if err := u.deletionRepo.Complete(deletion, anonymizedUser(deletion.Cid)); err != nil {
+		return err
+	}
+	go func(u *UserService, deletion *Entity.AccountDeletion, email string, operator *entity.User) {
+		u.logDeletionAudit(Entity.AuditEventAccountDeleted, deletion.OperatorCid, deletion.Cid, ip, userAgent, deletion.Reason)
+		u.sendDeletionEmail(email, deletion, operator)
+	}(u, deletion, user.Email, operator)
+	return nil
+}
+
+func (u *UserService) RequestDeletion(data *DTO.RequestAccountDeletion) *dto.ApiResponse[*DTO.AccountDeletionInfo] {
+	user, err := u.repo.GetById(data.Uid)
+	if err != nil {
+		u.logger.Errorf("RequestDeletion handle fail, get user err, %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse[*DTO.AccountDeletionInfo](ErrUserNotFound, nil)
+		}
+		return dto.NewApiResponse[*DTO.AccountDeletionInfo](ErrDataBaseError, nil)
+	}
+
+	pending, status := u.getPendingDeletion(user.ID)
+	if status != nil {
+		return dto.NewApiResponse[*DTO.AccountDeletionInfo](status, nil)
+	}
+	if pending != nil {
+		return dto.NewApiResponse[*DTO.AccountDeletionInfo](ErrDeletionPending, nil)
+	}
+
+	if !utils.BcryptCompare([]byte(data.Password), []byte(user.Password)) {
+		u.logger.Errorf("RequestDeletion handle fail, password error")
+		return dto.NewApiResponse[*DTO.AccountDeletionInfo](ErrOldPassword, nil)
+	}
+
+	if res := verifyEmailCode[*DTO.AccountDeletionInfo](u, user.Email, data.EmailCode); res != nil {
+		return res
+	}
+
+	deletion := &Entity.AccountDeletion{
+		UserId:      user.ID,
+		Cid:         user.Cid,
+		OperatorCid: user.Cid,
+		Status:      Entity.DeletionStatusPending,
+		Reason:      data.Reason,
+		ScheduledAt: time.Now().Add(u.deletionConfig.GracePeriodDuration),
+	}
+	if err := u.deletionRepo.Save(deletion); err != nil {
+		u.logger.Errorf("RequestDeletion handle fail, save deletion err, %v", err)
+		return dto.NewApiResponse[*DTO.AccountDeletionInfo](ErrDataBaseError, nil)
+	}
+
+	go func(u *UserService, data *DTO.RequestAccountDeletion, user *entity.User, deletion *Entity.AccountDeletion) {
+		u.logDeletionAudit(Entity.AuditEventAccountDeletionRequested, user.Cid, user.Cid, data.Ip, data.UserAgent, data.Reason)
+		u.sendDeletionEmail(user.Email, deletion, user)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		u.removeEmailCode(ctx, user.Email)
+	}(u, data, user, deletion)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, (&DTO.AccountDeletionInfo{}).FromEntity(deletion))
+}
+
+func (u *UserService) GetDeletion(data *DTO.GetAccountDeletion) *dto.ApiResponse[*DTO.AccountDeletionInfo] {
+	pending, status := u.getPendingDeletion(data.Uid)
+	if status != nil {
+		return dto.NewApiResponse[*DTO.AccountDeletionInfo](status, nil)
+	}
+	if pending == nil {
+		return dto.NewApiResponse[*DTO.AccountDeletionInfo](ErrDeletionNotFound, nil)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, (&DTO.AccountDeletionInfo{}).FromEntity(pending))
+}
+
+func (u *UserService) CancelDeletion(data *DTO.CancelAccountDeletion) *dto.ApiResponse[bool] {
+	pending, status := u.getPendingDeletion(data.Uid)
+	if status != nil {
+		return dto.NewApiResponse(status, false)
+	}
+	if pending == nil {
+		return dto.NewApiResponse(ErrDeletionNotFound, false)
+	}
+	if err := u.deletionRepo.Cancel(pending.ID); err != nil {
+		u.logger.Errorf("CancelDeletion handle fail, cancel deletion err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	go u.logDeletionAudit(Entity.AuditEventAccountDeletionCancelled, pending.Cid, pending.Cid, data.Ip, data.UserAgent, "")
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+// Delete 管理员删除用户, 不经过宽限期直接匿名化
+func (u *UserService) Delete(data *DTO.DeleteUser) *dto.ApiResponse[bool] {
+	scope, res := checkScope[bool](u.scope, data.Uid, data.Permission, Permission.UserDelete)
+	if res != nil {
+		u.logger.Errorf("user %04d no permission to delete user", data.Cid)
+		return res
+	}
+	if data.Id == data.Uid {
+		return dto.NewApiResponse(ErrDeleteSelf, false)
+	}
+
+	user, err := u.repo.GetById(data.Id)
+	if err != nil {
+		u.logger.Errorf("Delete handle fail, get user err, %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse(ErrUserNotFound, false)
+		}
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if res := checkUserInScope[bool](u.scope, scope, user.ID); res != nil {
+		u.logger.Errorf("user %04d no permission to delete user %04d", data.Cid, user.Cid)
+		return res
+	}
+	// 只有超级管理员可以删除超级管理员
+	perm := permission.Permission(data.Permission)
+	if Permission.Contains(Permission.Effective(user), Permission.SuperAdmin) && !perm.HasPermission(Permission.SuperAdmin) {
+		u.logger.Errorf("user %04d no permission to delete super admin %04d", data.Cid, user.Cid)
+		return dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+
+	operator, err := u.repo.GetById(data.Uid)
+	if err != nil {
+		u.logger.Errorf("Delete handle fail, get operator err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	pending, status := u.getPendingDeletion(user.ID)
+	if status != nil {
+		return dto.NewApiResponse(status, false)
+	}
+	deletion := pending
+	if deletion == nil {
+		deletion = &Entity.AccountDeletion{
+			UserId: user.ID,
+			Cid:    user.Cid,
+			Status: Entity.DeletionStatusPending,
+		}
+	}
+	deletion.OperatorCid = data.Cid
+	deletion.Reason = data.Reason
+	deletion.ScheduledAt = time.Now()
+	if err := u.deletionRepo.Save(deletion); err != nil {
+		u.logger.Errorf("Delete handle fail, save deletion err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	if err := u.completeDeletion(deletion, user, operator, data.Ip, data.UserAgent); err != nil {
+		u.logger.Errorf("Delete handle fail, anonymize user err, %v", err)
+		if errors.Is(err, repository.ErrDeletionClosed) {
+			return dto.NewApiResponse(ErrUserDeleted, false)
+		}
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+// ProcessDueDeletions 匿名化宽限期已结束的注销申请, 返回成功处理的数量
+func (u *UserService) ProcessDueDeletions() (int, error) {
+	deletions, err := u.deletionRepo.GetDue(time.Now(), u.deletionConfig.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	processed := 0
+	for _, deletion := range deletions {
+		user, err := u.repo.GetById(deletion.UserId)
+		if err != nil {
+			u.logger.Errorf("error occurred when get user %04d: %v", deletion.Cid, err)
+			continue
+		}
+		if err := u.completeDeletion(deletion, user, nil, "", ""); err != nil {
+			// 其他实例已处理或用户已取消
+			if errors.Is(err, repository.ErrDeletionClosed) {
+				continue
+			}
+			u.logger.Errorf("error occurred when anonymize user %04d: %v", deletion.Cid, err)
+			continue
+		}
+		processed++
+	}
+	return processed, nil
+}
@@ -0,0 +1,367 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/src/interfaces/content"
+	Entity "user-service/src/interfaces/database/entity"
+	"user-service/src/interfaces/grpc"
+	Permission "user-service/src/interfaces/permission"
+	"user-service/src/interfaces/repository"
+	DTO "user-service/src/interfaces/server/dto"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/logger"
+	"half-nothing.cn/service-core/permission"
+	"half-nothing.cn/service-core/utils"
+)
+
+type DivisionService struct {
+	logger   logger.Interface
+	repo     repository.DivisionInterface
+	userRepo repository.UserInterface
+	scope    *scopeResolver
+	client   *content.GrpcClientManager
+}
+
+func NewDivisionService(
+	lg logger.Interface,
+	repo repository.DivisionInterface,
+	userRepo repository.UserInterface,
+	client *content.GrpcClientManager,
+) *DivisionService {
+	adapter := logger.NewLoggerAdapter(lg, "division-service")
+	return &DivisionService{
+		logger:   adapter,
+		repo:     repo,
+		userRepo: userRepo,
+		scope:    newScopeResolver(adapter, repo),
+		client:   client,
+	}
+}
+
+var (
+	ErrDivisionNotFound  = dto.NewApiStatus("DIVISION_NOT_FOUND", "分区不存在", dto.HttpCodeNotFound)
+	ErrDivisionHasUsers  = dto.NewApiStatus("DIVISION_HAS_USERS", "分区下有用户", dto.HttpCodeConflict)
+	ErrUserNotInDivision = dto.NewApiStatus("USER_NOT_IN_DIVISION", "用户不属于该分区", dto.HttpCodeBadRequest)
+)
+
+func (service *DivisionService) GetPages(page *DTO.GetDivisionPage) *dto.ApiResponse[*DTO.GetDivisionPageResponse] {
+	perm := permission.Permission(page.Permission)
+	if !perm.HasPermission(Permission.DivisionShowList) {
+		return dto.NewApiResponse[*DTO.GetDivisionPageResponse](dto.ErrNoPermission, nil)
+	}
+	divisions, total, err := service.repo.GetPages(page.PageNum, page.PageSize, page.Search)
+	if err != nil {
+		service.logger.Errorf("error occurred when get division list: %v", err)
+		return dto.NewApiResponse[*DTO.GetDivisionPageResponse](ErrDataBaseError, nil)
+	}
+	divisionInfos := make([]*DTO.DivisionInfo, len(divisions))
+	utils.ForEach(divisions, func(index int, division *Entity.Division) {
+		divisionInfos[index] = (&DTO.DivisionInfo{}).FromDivisionEntity(division)
+	})
+	return dto.NewApiResponse(dto.SuccessHandleRequest, &DTO.GetDivisionPageResponse{
+		Data:     divisionInfos,
+		Total:    int(total),
+		PageNum:  page.PageNum,
+		PageSize: page.PageSize,
+	})
+}
+
+func (service *DivisionService) GetById(data *DTO.GetDivisionDetail) *dto.ApiResponse[*DTO.DivisionInfo] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(Permission.DivisionShowList) {
+		return dto.NewApiResponse[*DTO.DivisionInfo](dto.ErrNoPermission, nil)
+	}
+	division, err := service.repo.GetById(data.Id)
+	if err != nil {
+		service.logger.Errorf("error occurred when get division by id: %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse[*DTO.DivisionInfo](ErrDivisionNotFound, nil)
+		}
+		return dto.NewApiResponse[*DTO.DivisionInfo](ErrDataBaseError, nil)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, (&DTO.DivisionInfo{}).FromDivisionEntity(division))
+}
+
+func (service *DivisionService) Create(data *DTO.CreateDivision) *dto.ApiResponse[bool] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(Permission.DivisionManage) {
+		service.logger.Errorf("user %04d no permission to create division", data.Cid)
+		return dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+
+	division := &Entity.Division{
+		Code:    strings.ToUpper(data.Code),
+		Name:    data.Name,
+		Comment: data.Description,
+	}
+	if err := service.repo.Save(division); err != nil {
+		service.logger.Errorf("error occurred when create division: %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go func(data *DTO.CreateDivision, division *Entity.Division) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := service.client.AuditLogClient().Log(ctx, &grpc.AuditLogRequest{
+			Event:     Entity.AuditEventDivisionCreated.Value,
+			Subject:   fmt.Sprintf("%04d", data.Cid),
+			Object:    strconv.Itoa(int(division.ID)),
+			Ip:        data.Ip,
+			UserAgent: data.UserAgent,
+			NewValue:  fmt.Sprintf("%s %s(%s)", division.Code, division.Name, division.Comment),
+		})
+		if err != nil {
+			service.logger.Errorf("error occurred when create audit log: %v", err)
+		}
+	}(data, division)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+func (service *DivisionService) Update(data *DTO.UpdateDivision) *dto.ApiResponse[bool] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(Permission.DivisionManage) {
+		service.logger.Errorf("user %04d no permission to edit division", data.Cid)
+		return dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+
+	division, err := service.repo.GetById(data.Id)
+	if err != nil {
+		service.logger.Errorf("error occurred when get division by id: %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse(ErrDivisionNotFound, false)
+		}
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	oldValue := fmt.Sprintf("%s(%s)", division.Name, division.Comment)
+	if data.Name != "" {
+		division.Name = data.Name
+	}
+	if data.Description != "" {
+		division.Comment = data.Description
+	}
+	if err := service.repo.Save(division); err != nil {
+		service.logger.Errorf("error occurred when update division: %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go func(data *DTO.UpdateDivision, division *Entity.Division, oldValue string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := service.client.AuditLogClient().Log(ctx, &grpc.AuditLogRequest{
+			Event:     Entity.AuditEventDivisionUpdated.Value,
+			Subject:   fmt.Sprintf("%04d", data.Cid),
+			Object:    strconv.Itoa(int(division.ID)),
+			Ip:        data.Ip,
+			UserAgent: data.UserAgent,
+			OldValue:  oldValue,
+			NewValue:  fmt.Sprintf("%s(%s)", division.Name, division.Comment),
+		})
+		if err != nil {
+			service.logger.Errorf("error occurred when create audit log: %v", err)
+		}
+	}(data, division, oldValue)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+func (service *DivisionService) Delete(data *DTO.DeleteDivision) *dto.ApiResponse[bool] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(Permission.DivisionManage) {
+		service.logger.Errorf("user %04d no permission to delete division", data.Cid)
+		return dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+
+	division, err := service.repo.GetById(data.Id)
+	if err != nil {
+		service.logger.Errorf("error occurred when get division by id: %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse(ErrDivisionNotFound, false)
+		}
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	total, err := service.repo.CountMembers(division.ID)
+	if err != nil {
+		service.logger.Errorf("error occurred when count division members: %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	if total > 0 && !data.Force {
+		service.logger.Errorf("Division(ID: %d) has %d users", division.ID, total)
+		return dto.NewApiResponse(ErrDivisionHasUsers, false)
+	}
+
+	if err := service.repo.DeleteDivision(division.ID); err != nil {
+		service.logger.Errorf("error occurred when delete division: %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go func(data *DTO.DeleteDivision, division *Entity.Division) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := service.client.AuditLogClient().Log(ctx, &grpc.AuditLogRequest{
+			Event:     Entity.AuditEventDivisionDeleted.Value,
+			Subject:   fmt.Sprintf("%04d", data.Cid),
+			Object:    strconv.Itoa(int(division.ID)),
+			Ip:        data.Ip,
+			UserAgent: data.UserAgent,
+			OldValue:  fmt.Sprintf("%s %s(%s)", division.Code, division.Name, division.Comment),
+		})
+		if err != nil {
+			service.logger.Errorf("error occurred when create audit log: %v", err)
+		}
+	}(data, division)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+func (service *DivisionService) getDivisionAndUsers(divisionId uint, userIds []uint) (*Entity.Division, []*entity.User, *dto.ApiResponse[bool]) {
+	division, err := service.repo.GetById(divisionId)
+	if err != nil {
+		service.logger.Errorf("error occurred when get division by id: %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, dto.NewApiResponse(ErrDivisionNotFound, false)
+		}
+		return nil, nil, dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	users, err := service.userRepo.GetByIds(userIds)
+	if err != nil {
+		service.logger.Errorf("error occurred when get users: %v", err)
+		return nil, nil, checkDatabaseError[bool](err)
+	}
+
+	return division, users, nil
+}
+
+//goland:noinspection DuplicatedCode
+func (service *DivisionService) AddMembers(data *DTO.EditDivisionMember) *dto.ApiResponse[bool] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(Permission.DivisionManage) {
+		service.logger.Errorf("user %04d no permission to edit division members", data.Cid)
+		return dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+
+	division, users, res := service.getDivisionAndUsers(data.Id, data.UserIds)
+	if res != nil {
+		return res
+	}
+
+	userIds := make([]uint, len(users))
+	utils.ForEach(users, func(index int, user *entity.User) {
+		userIds[index] = user.ID
+	})
+
+	if err := service.repo.AddMembers(division.ID, userIds); err != nil {
+		service.logger.Errorf("error occurred when add division members: %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go func(data *DTO.EditDivisionMember, division *Entity.Division, users []*entity.User) {
+		userCids := make([]string, len(users))
+		utils.ForEach(users, func(index int, user *entity.User) {
+			userCids[index] = fmt.Sprintf("%04d", user.Cid)
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := service.client.AuditLogClient().Log(ctx, &grpc.AuditLogRequest{
+			Event:     Entity.AuditEventDivisionMemberAdd.Value,
+			Subject:   fmt.Sprintf("%04d", data.Cid),
+			Object:    fmt.Sprintf("%d(%s)", division.ID, division.Code),
+			Ip:        data.Ip,
+			UserAgent: data.UserAgent,
+			NewValue:  strings.Join(userCids, ","),
+		})
+		if err != nil {
+			service.logger.Errorf("error occurred when create audit log: %v", err)
+		}
+	}(data, division, users)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+//goland:noinspection DuplicatedCode
+func (service *DivisionService) RemoveMembers(data *DTO.EditDivisionMember) *dto.ApiResponse[bool] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(Permission.DivisionManage) {
+		service.logger.Errorf("user %04d no permission to edit division members", data.Cid)
+		return dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+
+	division, users, res := service.getDivisionAndUsers(data.Id, data.UserIds)
+	if res != nil {
+		return res
+	}
+
+	userIds := make([]uint, len(users))
+	utils.ForEach(users, func(index int, user *entity.User) {
+		userIds[index] = user.ID
+	})
+
+	// 移除成员时同时撤销其在该分区内的角色
+	if err := service.repo.RemoveMembers(division.ID, userIds); err != nil {
+		service.logger.Errorf("error occurred when remove division members: %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go func(data *DTO.EditDivisionMember, division *Entity.Division, users []*entity.User) {
+		userCids := make([]string, len(users))
+		utils.ForEach(users, func(index int, user *entity.User) {
+			userCids[index] = fmt.Sprintf("%04d", user.Cid)
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := service.client.AuditLogClient().Log(ctx, &grpc.AuditLogRequest{
+			Event:     Entity.AuditEventDivisionMemberRemove.Value,
+			Subject:   fmt.Sprintf("%04d", data.Cid),
+			Object:    fmt.Sprintf("%d(%s)", division.ID, division.Code),
+			Ip:        data.Ip,
+			UserAgent: data.UserAgent,
+			OldValue:  strings.Join(userCids, ","),
+		})
+		if err != nil {
+			service.logger.Errorf("error occurred when create audit log: %v", err)
+		}
+	}(data, division, users)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+func (service *DivisionService) GetUserDivisions(data *DTO.GetUserDivisions) *dto.ApiResponse[*DTO.UserDivisionsResponse] {
+	if data.Id != data.Uid {
+		scope, res := checkScope[*DTO.UserDivisionsResponse](service.scope, data.Uid, data.Permission, permission.UserShowList)
+		if res != nil {
+			service.logger.Errorf("user %04d no permission to get divisions of user %d", data.Cid, data.Id)
+			return res
+		}
+		if res := checkUserInScope[*DTO.UserDivisionsResponse](service.scope, scope, data.Id); res != nil {
+			service.logger.Errorf("user %04d no permission to get divisions of user %d", data.Cid, data.Id)
+			return res
+		}
+	}
+
+	divisions, err := service.repo.GetUserDivisions(data.Id)
+	if err != nil {
+		service.logger.Errorf("error occurred when get user divisions: %v", err)
+		return dto.NewApiResponse[*DTO.UserDivisionsResponse](ErrDataBaseError, nil)
+	}
+	divisionInfos := make([]*DTO.DivisionInfo, len(divisions))
+	utils.ForEach(divisions, func(index int, division *Entity.Division) {
+		divisionInfos[index] = (&DTO.DivisionInfo{}).FromDivisionEntity(division)
+	})
+	return dto.NewApiResponse(dto.SuccessHandleRequest, &DTO.UserDivisionsResponse{Divisions: divisionInfos})
+}
@@ -0,0 +1,302 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+	c "user-service/src/interfaces/config"
+	"user-service/src/interfaces/content"
+	Entity "user-service/src/interfaces/database/entity"
+	pb "user-service/src/interfaces/grpc"
+	"user-service/src/interfaces/repository"
+	DTO "user-service/src/interfaces/server/dto"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+var (
+	ErrEmailChangeNotFound = dto.NewApiStatus("EMAIL_CHANGE_NOT_FOUND", "邮箱修改请求不存在或链接无效", dto.HttpCodeNotFound)
+	ErrEmailChangeExpired  = dto.NewApiStatus("EMAIL_CHANGE_EXPIRED", "链接已过期", dto.HttpCodeBadRequest)
+	ErrEmailChangeClosed   = dto.NewApiStatus("EMAIL_CHANGE_CLOSED", "该邮箱修改请求已被处理", dto.HttpCodeConflict)
+	ErrEmailChangeOutdated = dto.NewApiStatus("EMAIL_CHANGE_OUTDATED", "账户邮箱已发生变化, 请重新申请修改", dto.HttpCodeConflict)
+	ErrEmailChangeSendFail = dto.NewApiStatus("EMAIL_CHANGE_SEND_FAIL", "邮件发送失败, 请稍后再试", dto.HttpCodeInternalError)
+)
+
+// EmailChangeService 两步确认的邮箱修改
+//
+// 用户申请修改邮箱后, 新邮箱收到确认链接, 原邮箱收到撤销链接, 新邮箱确认后修改才会生效.
+// 有效期内原邮箱可以随时通过撤销链接取消修改或恢复原邮箱, 同时使该用户的所有会话失效,
+// 以防止会话被盗用后账户被永久夺取
+type EmailChangeService struct {
+	logger      logger.Interface
+	config      *c.EmailChangeConfig
+	emailConfig *c.EmailConfig
+	repo        repository.EmailChangeInterface
+	userRepo    repository.UserInterface
+	client      *content.GrpcClientManager
+}
+
+func NewEmailChangeService(
+	lg logger.Interface,
+	config *c.EmailChangeConfig,
+	emailConfig *c.EmailConfig,
+	repo repository.EmailChangeInterface,
+	userRepo repository.UserInterface,
+	client *content.GrpcClientManager,
+) *EmailChangeService {
+	return &EmailChangeService{
+		logger:      logger.NewLoggerAdapter(lg, "email-change-service"),
+		config:      config,
+		emailConfig: emailConfig,
+		repo:        repo,
+		userRepo:    userRepo,
+		client:      client,
+	}
+}
+
+// newEmailChangeToken 生成随机令牌, 返回令牌本身与其 SHA-256 摘要
+func newEmailChangeToken() (string, string) {
+	random := make([]byte, 32)
+	_, _ = rand.Read(random)
+	token := hex.EncodeToString(random)
+	return token, hashEmailChangeToken(token)
+}
+
+func hashEmailChangeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (service *EmailChangeService) logAudit(event *Entity.AuditEvent, cid uint, ip string, userAgent string, oldValue string, newValue string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := service.client.AuditLogClient().Log(ctx, &pb.AuditLogRequest{
+		Event:     event.Value,
+		Subject:   fmt.Sprintf("%04d", cid),
+		Object:    fmt.Sprintf("%04d", cid),
+		Ip:        ip,
+		UserAgent: userAgent,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+	})
+	if err != nil {
+		service.logger.Errorf("error occurred when log audit: %v", err)
+	}
+}
+
+// request 创建邮箱修改请求, 并向新邮箱发送确认链接, 向原邮箱发送撤销链接
+//
+// 两封邮件都发送成功后请求才有效, 否则取消请求, 避免原邮箱收不到撤销链接
+func (service *EmailChangeService) request(user *entity.User, email string, ip string, userAgent string) *dto.ApiStatus {
+	confirmToken, confirmHash := newEmailChangeToken()
+	revertToken, revertHash := newEmailChangeToken()
+	change := &Entity.EmailChange{
+		UserId:           user.ID,
+		OldEmail:         user.Email,
+		NewEmail:         email,
+		ConfirmTokenHash: confirmHash,
+		RevertTokenHash:  revertHash,
+		Status:           Entity.EmailChangeStatusPending,
+		Ip:               ip,
+		UserAgent:        userAgent,
+		ExpiresAt:        time.Now().Add(service.config.ValidDuration),
+	}
+	if err := service.repo.Request(change); err != nil {
+		service.logger.Errorf("error occurred when save email change: %v", err)
+		return ErrDataBaseError
+	}
+
+	if err := service.sendRequestEmails(user, change, confirmToken, revertToken); err != nil {
+		service.logger.Errorf("error occurred when send email change emails: %v", err)
+		if err := service.repo.Cancel(change, false); err != nil {
+			service.logger.Errorf("error occurred when cancel email change: %v", err)
+		}
+		return ErrEmailChangeSendFail
+	}
+
+	go service.logAudit(Entity.AuditEventEmailChangeRequested, user.Cid, change.Ip, change.UserAgent, change.OldEmail, change.NewEmail)
+
+	return nil
+}
+
+// sendRequestEmails 发送确认与撤销邮件, 依赖邮件服务实现 SendEmailChangeConfirm 与 SendEmailChangeRevert
+func (service *EmailChangeService) sendRequestEmails(user *entity.User, change *Entity.EmailChange, confirmToken string, revertToken string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := service.client.EmailClient().SendEmailChangeRevert(ctx, &pb.EmailChangeRevert{
+		TargetEmail: change.OldEmail,
+		Cid:         fmt.Sprintf("%04d", user.Cid),
+		Email:       change.NewEmail,
+		Link:        service.config.Link(service.config.RevertUrl, revertToken),
+		ExpiresAt:   change.ExpiresAt.Format(time.RFC3339),
+		Ip:          change.Ip,
+		UserAgent:   change.UserAgent,
+	})
+	if err != nil {
+		return fmt.Errorf("send revert email: %w", err)
+	}
+	_, err = service.client.EmailClient().SendEmailChangeConfirm(ctx, &pb.EmailChangeConfirm{
+		TargetEmail: change.NewEmail,
+		Cid:         fmt.Sprintf("%04d", user.Cid),
+		Email:       change.NewEmail,
+		Link:        service.config.Link(service.config.ConfirmUrl, confirmToken),
+		ExpiresAt:   change.ExpiresAt.Format(time.RFC3339),
+		Ip:          change.Ip,
+		UserAgent:   change.UserAgent,
+	})
+	if err != nil {
+		return fmt.Errorf("send confirm email: %w", err)
+	}
+	return nil
+}
+
+func (service *EmailChangeService) GetEmailChange(data *DTO.GetEmailChange) *dto.ApiResponse[*DTO.EmailChangeInfo] {
+	change, err := service.repo.GetPending(data.Uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse[*DTO.EmailChangeInfo](dto.SuccessHandleRequest, nil)
+		}
+		service.logger.Errorf("GetEmailChange handle fail, get email change err, %v", err)
+		return dto.NewApiResponse[*DTO.EmailChangeInfo](ErrDataBaseError, nil)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, (&DTO.EmailChangeInfo{}).FromEntity(change))
+}
+
+func (service *EmailChangeService) CancelEmailChange(data *DTO.CancelEmailChange) *dto.ApiResponse[bool] {
+	change, err := service.repo.GetPending(data.Uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse(ErrEmailChangeNotFound, false)
+		}
+		service.logger.Errorf("CancelEmailChange handle fail, get email change err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if err := service.repo.Cancel(change, false); err != nil {
+		if errors.Is(err, repository.ErrEmailChangeClosed) {
+			return dto.NewApiResponse(ErrEmailChangeClosed, false)
+		}
+		service.logger.Errorf("CancelEmailChange handle fail, cancel email change err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go service.logAudit(Entity.AuditEventEmailChangeCancelled, data.Cid, data.Ip, data.UserAgent, change.NewEmail, "")
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+func (service *EmailChangeService) ConfirmEmailChange(data *DTO.ConfirmEmailChange) *dto.ApiResponse[bool] {
+	change, err := service.repo.GetByConfirmToken(hashEmailChangeToken(data.Token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse(ErrEmailChangeNotFound, false)
+		}
+		service.logger.Errorf("ConfirmEmailChange handle fail, get email change err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if change.Status != Entity.EmailChangeStatusPending {
+		return dto.NewApiResponse(ErrEmailChangeClosed, false)
+	}
+	if change.Expired(time.Now()) {
+		return dto.NewApiResponse(ErrEmailChangeExpired, false)
+	}
+	user, err := service.userRepo.GetById(change.UserId)
+	if err != nil {
+		service.logger.Errorf("ConfirmEmailChange handle fail, get user err, %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse(ErrUserNotFound, false)
+		}
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	if err := service.repo.Confirm(change, service.emailConfig.Canonical(change.NewEmail)); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicated):
+			return dto.NewApiResponse(ErrEmailTaken, false)
+		case errors.Is(err, repository.ErrEmailChanged):
+			return dto.NewApiResponse(ErrEmailChangeOutdated, false)
+		case errors.Is(err, repository.ErrEmailChangeClosed):
+			return dto.NewApiResponse(ErrEmailChangeClosed, false)
+		}
+		service.logger.Errorf("ConfirmEmailChange handle fail, confirm email change err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go func(service *EmailChangeService, data *DTO.ConfirmEmailChange, user *entity.User, change *Entity.EmailChange) {
+		service.logAudit(Entity.AuditEventEmailChangeConfirmed, user.Cid, data.Ip, data.UserAgent, change.OldEmail, change.NewEmail)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err := service.client.EmailClient().SendEmailChange(ctx, &pb.EmailChange{
+			TargetEmail: change.OldEmail,
+			Cid:         fmt.Sprintf("%04d", user.Cid),
+			Email:       change.NewEmail,
+			Time:        time.Now().Format(time.RFC3339),
+			Ip:          data.Ip,
+			UserAgent:   data.UserAgent,
+		})
+		if err != nil {
+			service.logger.Errorf("error occurred when send email change email: %v", err)
+		}
+	}(service, data, user, change)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+// RevertEmailChange 通过原邮箱收到的撤销链接取消尚未确认的修改, 或将已确认的修改恢复为原邮箱
+func (service *EmailChangeService) RevertEmailChange(data *DTO.RevertEmailChange) *dto.ApiResponse[bool] {
+	change, err := service.repo.GetByRevertToken(hashEmailChangeToken(data.Token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse(ErrEmailChangeNotFound, false)
+		}
+		service.logger.Errorf("RevertEmailChange handle fail, get email change err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if change.Expired(time.Now()) {
+		return dto.NewApiResponse(ErrEmailChangeExpired, false)
+	}
+	user, err := service.userRepo.GetById(change.UserId)
+	if err != nil {
+		service.logger.Errorf("RevertEmailChange handle fail, get user err, %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse(ErrUserNotFound, false)
+		}
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	var event *Entity.AuditEvent
+	switch change.Status {
+	case Entity.EmailChangeStatusPending:
+		event = Entity.AuditEventEmailChangeCancelled
+		err = service.repo.Cancel(change, true)
+	case Entity.EmailChangeStatusConfirmed:
+		event = Entity.AuditEventEmailChangeReverted
+		err = service.repo.Revert(change, service.emailConfig.Canonical(change.OldEmail))
+	default:
+		return dto.NewApiResponse(ErrEmailChangeClosed, false)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicated):
+			return dto.NewApiResponse(ErrEmailTaken, false)
+		case errors.Is(err, repository.ErrEmailChangeClosed):
+			return dto.NewApiResponse(ErrEmailChangeClosed, false)
+		}
+		service.logger.Errorf("RevertEmailChange handle fail, revert email change err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go service.logAudit(event, user.Cid, data.Ip, data.UserAgent, change.NewEmail, change.OldEmail)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
@@ -0,0 +1,428 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+	c "user-service/src/interfaces/config"
+	"user-service/src/interfaces/content"
+	Entity "user-service/src/interfaces/database/entity"
+	pb "user-service/src/interfaces/grpc"
+	Permission "user-service/src/interfaces/permission"
+	"user-service/src/interfaces/repository"
+	DTO "user-service/src/interfaces/server/dto"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/logger"
+	"half-nothing.cn/service-core/utils"
+)
+
+var (
+	ErrExportFormat       = dto.NewApiStatus("EXPORT_FORMAT_INVALID", "不支持的导出格式", dto.HttpCodeBadRequest)
+	ErrExportTooFrequent  = dto.NewApiStatus("EXPORT_TOO_FREQUENT", "导出过于频繁, 请稍后再试", HttpCodeTooManyRequests)
+	ErrExportInProgress   = dto.NewApiStatus("EXPORT_IN_PROGRESS", "导出正在进行中", dto.HttpCodeConflict)
+	ErrExportNotFound     = dto.NewApiStatus("EXPORT_NOT_FOUND", "没有导出记录", dto.HttpCodeNotFound)
+	ErrExportNotReady     = dto.NewApiStatus("EXPORT_NOT_READY", "导出文件尚未生成", dto.HttpCodeBadRequest)
+	ErrExportExpired      = dto.NewApiStatus("EXPORT_EXPIRED", "导出文件已过期", dto.HttpCodeBadRequest)
+	ErrExportDataNotFound = dto.NewApiStatus("EXPORT_DATA_NOT_FOUND", "导出文件不存在", dto.HttpCodeNotFound)
+)
+
+const exportFileName = "user-data"
+
+type DataExportService struct {
+	logger       logger.Interface
+	config       *c.ExportConfig
+	repo         repository.ExportInterface
+	userRepo     repository.UserInterface
+	divisionRepo repository.DivisionInterface
+	deletionRepo repository.DeletionInterface
+	profileRepo  repository.ProfileInterface
+	emailRepo    repository.EmailChangeInterface
+	avatar       *AvatarService
+	rating       *RatingService
+	instructor   *InstructorService
+	username     *UsernameService
+	client       *content.GrpcClientManager
+	limiter      *rateLimiter
+}
+
+func NewDataExportService(
+	lg logger.Interface,
+	config *c.ExportConfig,
+	repo repository.ExportInterface,
+	userRepo repository.UserInterface,
+	divisionRepo repository.DivisionInterface,
+	deletionRepo repository.DeletionInterface,
+	profileRepo repository.ProfileInterface,
+	emailRepo repository.EmailChangeInterface,
+	avatar *AvatarService,
+	rating *RatingService,
+	instructor *InstructorService,
+	username *UsernameService,
+	client *content.GrpcClientManager,
+) *DataExportService {
+	return &DataExportService{
+		logger:       logger.NewLoggerAdapter(lg, "data-export-service"),
+		config:       config,
+		repo:         repo,
+		userRepo:     userRepo,
+		divisionRepo: divisionRepo,
+		deletionRepo: deletionRepo,
+		profileRepo:  profileRepo,
+		emailRepo:    emailRepo,
+		avatar:       avatar,
+		rating:       rating,
+		instructor:   instructor,
+		username:     username,
+		client:       client,
+		limiter:      newRateLimiter(config.RateLimit, time.Minute),
+	}
+}
+
+func (service *DataExportService) logAudit(event *Entity.AuditEvent, cid uint, ip string, userAgent string, value string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := service.client.AuditLogClient().Log(ctx, &pb.AuditLogRequest{
+		Event:     event.Value,
+		Subject:   fmt.Sprintf("%04d", cid),
+		Object:    fmt.Sprintf("%04d", cid),
+		Ip:        ip,
+		UserAgent: userAgent,
+		NewValue:  value,
+	})
+	if err != nil {
+		service.logger.Errorf("error occurred when log audit: %v", err)
+	}
+}
+
+// getLatest 获取用户最近一次导出记录, 不存在时返回 nil
+func (service *DataExportService) getLatest(userId uint) (*Entity.DataExport, error) {
+	export, err := service.repo.GetLatest(userId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+// assignmentRecords 转换指派记录, 对方只保留呼号与显示名称
+func (service *DataExportService) assignmentRecords(
+	assignments []*Entity.InstructorAssignment,
+	withTrainee bool,
+	withInstructor bool,
+) ([]*DTO.AssignmentRecord, error) {
+	userIds := make([]uint, 0, len(assignments))
+	for _, assignment := range assignments {
+		if withTrainee && !slices.Contains(userIds, assignment.TraineeId) {
+			userIds = append(userIds, assignment.TraineeId)
+		}
+		if withInstructor && !slices.Contains(userIds, assignment.InstructorId) {
+			userIds = append(userIds, assignment.InstructorId)
+		}
+	}
+	counterparties := make(map[uint]*DTO.Counterparty, len(userIds))
+	if len(userIds) > 0 {
+		users, err := service.userRepo.GetByIds(userIds)
+		if err != nil {
+			return nil, err
+		}
+		profiles, err := service.profileRepo.GetByUserIds(userIds)
+		if err != nil {
+			return nil, err
+		}
+		utils.ForEach(users, func(index int, user *entity.User) {
+			counterparty := &DTO.Counterparty{Cid: user.Cid}
+			if profile, ok := profiles[user.ID]; ok && profile != nil {
+				counterparty.DisplayName = profile.DisplayName
+			}
+			counterparties[user.ID] = counterparty
+		})
+	}
+	records := make([]*DTO.AssignmentRecord, len(assignments))
+	utils.ForEach(assignments, func(index int, assignment *Entity.InstructorAssignment) {
+		record := (&DTO.AssignmentRecord{}).FromEntity(assignment)
+		if withTrainee {
+			record.Trainee = counterparties[assignment.TraineeId]
+		}
+		if withInstructor {
+			record.Instructor = counterparties[assignment.InstructorId]
+		}
+		records[index] = record
+	})
+	return records, nil
+}
+
+// collect 收集本服务保存的用户个人数据, 新增保存用户数据的功能时需要同步补充
+//
+// 登录记录只保存最近一次登录, 封禁记录只保存当前状态, 历史记录由审计服务保存
+func (service *DataExportService) collect(user *entity.User) (*DTO.PersonalData, error) {
+	divisions, err := service.divisionRepo.GetUserDivisions(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	scopedRoles, err := service.divisionRepo.GetScopedRoles(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	deletions, err := service.deletionRepo.GetByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := getProfile(service.profileRepo, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	avatars, err := service.avatar.repo.GetByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	rating, err := service.rating.ratingInfo(user)
+	if err != nil {
+		return nil, err
+	}
+	instructors, err := service.instructor.repo.GetByTrainee(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	instructorRecords, err := service.assignmentRecords(instructors, false, true)
+	if err != nil {
+		return nil, err
+	}
+	trainees, err := service.instructor.repo.GetTrainees(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	traineeRecords, err := service.assignmentRecords(trainees, true, false)
+	if err != nil {
+		return nil, err
+	}
+	usernames, err := service.username.repo.GetByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	emailChanges, err := service.emailRepo.GetByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &DTO.PersonalData{
+		ExportedAt:       time.Now(),
+		Profile:          (&DTO.FullUserInfo{}).FromUserEntity(user, profile),
+		CustomFields:     make([]*DTO.CustomFieldRecord, 0),
+		Divisions:        make([]*DTO.DivisionInfo, len(divisions)),
+		ScopedRoles:      make([]*DTO.ScopedRoleInfo, 0, len(scopedRoles)),
+		Avatars:          make([]*DTO.AvatarInfo, len(avatars)),
+		Rating:           rating,
+		Instructors:      &DTO.AssignmentHistory{History: instructorRecords},
+		Trainees:         traineeRecords,
+		UsernameHistory:  make([]*DTO.UsernameChangeInfo, len(usernames)),
+		EmailChanges:     make([]*DTO.EmailChangeRecord, len(emailChanges)),
+		LoginHistory:     make([]*DTO.LoginRecord, 0, 1),
+		Sanctions:        make([]*DTO.SanctionRecord, 0, 1),
+		AccountDeletions: make([]*DTO.AccountDeletionInfo, len(deletions)),
+	}
+	if profile != nil {
+		utils.ForEach(profile.Fields, func(index int, value *Entity.UserFieldValue) {
+			data.CustomFields = append(data.CustomFields, (&DTO.CustomFieldRecord{}).FromEntity(value))
+		})
+	}
+	data.Permissions, _ = Permission.Decode(user.Permission)
+	data.EffectivePermissions, _ = Permission.Decode(uint64(Permission.Effective(user)))
+	utils.ForEach(divisions, func(index int, division *Entity.Division) {
+		data.Divisions[index] = (&DTO.DivisionInfo{}).FromDivisionEntity(division)
+	})
+	utils.ForEach(avatars, func(index int, avatar *Entity.Avatar) {
+		data.Avatars[index] = service.avatar.avatarInfo(avatar, user.ImageId)
+	})
+	for _, record := range instructorRecords {
+		if record.EndedAt == nil {
+			data.Instructors.Current = record
+			break
+		}
+	}
+	utils.ForEach(usernames, func(index int, change *Entity.UsernameChange) {
+		data.UsernameHistory[index] = (&DTO.UsernameChangeInfo{}).FromEntity(change)
+	})
+	utils.ForEach(emailChanges, func(index int, change *Entity.EmailChange) {
+		data.EmailChanges[index] = (&DTO.EmailChangeRecord{}).FromEntity(change)
+	})
+	for _, scopedRole := range scopedRoles {
+		if scopedRole.Role == nil || scopedRole.Division == nil {
+			continue
+		}
+		role := &DTO.BaseRoleInfo{}
+		role.FromRoleEntity(scopedRole.Role)
+		data.ScopedRoles = append(data.ScopedRoles, &DTO.ScopedRoleInfo{
+			Division: (&DTO.DivisionInfo{}).FromDivisionEntity(scopedRole.Division),
+			Role:     role,
+		})
+	}
+	if user.LastLoginTime.Valid {
+		data.LoginHistory = append(data.LoginHistory, &DTO.LoginRecord{Time: &user.LastLoginTime.Time, Ip: user.LastLoginIP})
+	}
+	if user.Banned {
+		sanction := &DTO.SanctionRecord{Banned: true}
+		if user.BannedUntil.Valid {
+			sanction.BannedUntil = &user.BannedUntil.Time
+		}
+		data.Sanctions = append(data.Sanctions, sanction)
+	}
+	utils.ForEach(deletions, func(index int, deletion *Entity.AccountDeletion) {
+		data.AccountDeletions[index] = (&DTO.AccountDeletionInfo{}).FromEntity(deletion)
+	})
+	return data, nil
+}
+
+func archive(data *DTO.PersonalData, format string) ([]byte, error) {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if format == Entity.ExportFormatJson {
+		return content, nil
+	}
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+	file, err := writer.Create(exportFileName + ".json")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Write(content); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// generate 在后台生成导出文件
+func (service *DataExportService) generate(export *Entity.DataExport) {
+	user, err := service.userRepo.GetById(export.UserId)
+	if err == nil {
+		var data *DTO.PersonalData
+		if data, err = service.collect(user); err == nil {
+			var content []byte
+			if content, err = archive(data, export.Format); err == nil {
+				err = service.repo.Complete(export.ID, content, time.Now().Add(service.config.RetentionDuration))
+			}
+		}
+	}
+	if err != nil {
+		service.logger.Errorf("error occurred when generate data export %d: %v", export.ID, err)
+		if err := service.repo.Fail(export.ID); err != nil {
+			service.logger.Errorf("error occurred when mark data export %d failed: %v", export.ID, err)
+		}
+	}
+}
+
+func (service *DataExportService) RequestExport(data *DTO.RequestDataExport) *dto.ApiResponse[*DTO.DataExportInfo] {
+	if !service.limiter.allow(strconv.Itoa(int(data.Uid))) {
+		return dto.NewApiResponse[*DTO.DataExportInfo](ErrTooManyRequests, nil)
+	}
+	if data.Format == "" {
+		data.Format = Entity.ExportFormatJson
+	}
+	if data.Format != Entity.ExportFormatJson && data.Format != Entity.ExportFormatZip {
+		return dto.NewApiResponse[*DTO.DataExportInfo](ErrExportFormat, nil)
+	}
+
+	latest, err := service.getLatest(data.Uid)
+	if err != nil {
+		service.logger.Errorf("RequestExport handle fail, get latest export err, %v", err)
+		return dto.NewApiResponse[*DTO.DataExportInfo](ErrDataBaseError, nil)
+	}
+	// 失败的导出不计入频率限制, 超过间隔仍未完成的导出视为已中断
+	if latest != nil && latest.Status != Entity.ExportStatusFailed && latest.CreatedAt.Add(service.config.IntervalDuration).After(time.Now()) {
+		if latest.Status == Entity.ExportStatusPending {
+			return dto.NewApiResponse[*DTO.DataExportInfo](ErrExportInProgress, nil)
+		}
+		return dto.NewApiResponse[*DTO.DataExportInfo](ErrExportTooFrequent, nil)
+	}
+
+	export := &Entity.DataExport{
+		UserId: data.Uid,
+		Format: data.Format,
+		Status: Entity.ExportStatusPending,
+	}
+	if err := service.repo.Save(export); err != nil {
+		service.logger.Errorf("RequestExport handle fail, save export err, %v", err)
+		return dto.NewApiResponse[*DTO.DataExportInfo](ErrDataBaseError, nil)
+	}
+
+	go service.generate(export)
+	go service.logAudit(Entity.AuditEventDataExportRequested, data.Cid, data.Ip, data.UserAgent, data.Format)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, (&DTO.DataExportInfo{}).FromEntity(export))
+}
+
+func (service *DataExportService) GetExport(data *DTO.GetDataExport) *dto.ApiResponse[*DTO.DataExportInfo] {
+	if !service.limiter.allow(strconv.Itoa(int(data.Uid))) {
+		return dto.NewApiResponse[*DTO.DataExportInfo](ErrTooManyRequests, nil)
+	}
+	latest, err := service.getLatest(data.Uid)
+	if err != nil {
+		service.logger.Errorf("GetExport handle fail, get latest export err, %v", err)
+		return dto.NewApiResponse[*DTO.DataExportInfo](ErrDataBaseError, nil)
+	}
+	if latest == nil {
+		return dto.NewApiResponse[*DTO.DataExportInfo](ErrExportNotFound, nil)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, (&DTO.DataExportInfo{}).FromEntity(latest))
+}
+
+func (service *DataExportService) Download(data *DTO.DownloadDataExport) (*DTO.DataExportFile, *dto.ApiResponse[bool]) {
+	if !service.limiter.allow(strconv.Itoa(int(data.Uid))) {
+		return nil, dto.NewApiResponse(ErrTooManyRequests, false)
+	}
+	latest, err := service.getLatest(data.Uid)
+	if err != nil {
+		service.logger.Errorf("Download handle fail, get latest export err, %v", err)
+		return nil, dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if latest == nil {
+		return nil, dto.NewApiResponse(ErrExportNotFound, false)
+	}
+	if latest.Status != Entity.ExportStatusCompleted {
+		return nil, dto.NewApiResponse(ErrExportNotReady, false)
+	}
+	if !latest.ExpiresAt.Valid || latest.ExpiresAt.Time.Before(time.Now()) {
+		return nil, dto.NewApiResponse(ErrExportExpired, false)
+	}
+
+	export, err := service.repo.GetWithData(latest.ID)
+	if err != nil {
+		service.logger.Errorf("Download handle fail, get export data err, %v", err)
+		return nil, dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if len(export.Data) == 0 {
+		return nil, dto.NewApiResponse(ErrExportDataNotFound, false)
+	}
+
+	go service.logAudit(Entity.AuditEventDataExportDownloaded, data.Cid, data.Ip, data.UserAgent, export.Format)
+
+	return &DTO.DataExportFile{
+		Name:    fmt.Sprintf("%s.%s", exportFileName, export.Format),
+		Format:  export.Format,
+		Content: export.Data,
+	}, nil
+}
+
+// PurgeExpired 清除已超过保留期限的导出归档, 返回清除的数量
+func (service *DataExportService) PurgeExpired() (int64, error) {
+	return service.repo.PurgeExpired(time.Now())
+}
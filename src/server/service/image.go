@@ -0,0 +1,130 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+var (
+	errImageFormat    = errors.New("unsupported image format")
+	errImageDimension = errors.New("image dimension too large")
+)
+
+// 允许上传的图片类型, 以文件内容检测结果为准
+var avatarContentTypes = []string{"image/png", "image/jpeg", "image/gif"}
+
+// decodeAvatar 检测并解码上传的图片, 解码前先检查宽高以避免解压炸弹
+func decodeAvatar(data []byte, maxDimension int) (image.Image, error) {
+	if !slices.Contains(avatarContentTypes, http.DetectContentType(data)) {
+		return nil, errImageFormat
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errImageFormat
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width > maxDimension || config.Height > maxDimension {
+		return nil, errImageDimension
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errImageFormat
+	}
+	return img, nil
+}
+
+// cropAndResize 居中裁剪为正方形, 再按区域平均缩放为 size*size
+func cropAndResize(src image.Image, size int) *image.NRGBA {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	x0 := bounds.Min.X + (bounds.Dx()-side)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-side)/2
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		sy0 := y0 + y*side/size
+		sy1 := max(y0+(y+1)*side/size, sy0+1)
+		for x := 0; x < size; x++ {
+			sx0 := x0 + x*side/size
+			sx1 := max(x0+(x+1)*side/size, sx0+1)
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
+
+// renderAvatar 将图片重新编码为各个尺寸的 PNG, 去除原图中的元数据
+//
+// 从最大尺寸开始缩放, 较小的尺寸基于上一个结果生成以减少计算量
+func renderAvatar(src image.Image, sizes []int) (map[int][]byte, error) {
+	ordered := slices.Clone(sizes)
+	slices.Sort(ordered)
+	slices.Reverse(ordered)
+	result := make(map[int][]byte, len(ordered))
+	current := src
+	for _, size := range ordered {
+		resized := cropAndResize(current, size)
+		buffer := &bytes.Buffer{}
+		if err := png.Encode(buffer, resized); err != nil {
+			return nil, err
+		}
+		result[size] = buffer.Bytes()
+		current = resized
+	}
+	return result, nil
+}
+
+const identiconGrid = 5
+
+// renderIdenticon 根据呼号生成左右对称的 5x5 图标
+func renderIdenticon(cid uint, size int) ([]byte, error) {
+	hash := sha256.Sum256([]byte(strconv.FormatUint(uint64(cid), 10)))
+	foreground := color.NRGBA{R: hash[0], G: hash[1], B: hash[2], A: 255}
+	background := color.NRGBA{R: 240, G: 240, B: 240, A: 255}
+	cell := size / (identiconGrid + 1)
+	padding := (size - cell*identiconGrid) / 2
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.SetNRGBA(x, y, background)
+		}
+	}
+	for row := 0; row < identiconGrid; row++ {
+		for column := 0; column < (identiconGrid+1)/2; column++ {
+			if hash[3+row*3+column]%2 == 0 {
+				continue
+			}
+			for _, c := range []int{column, identiconGrid - 1 - column} {
+				for y := 0; y < cell; y++ {
+					for x := 0; x < cell; x++ {
+						img.SetNRGBA(padding+c*cell+x, padding+row*cell+y, foreground)
+					}
+				}
+			}
+		}
+	}
+	buffer := &bytes.Buffer{}
+	if err := png.Encode(buffer, img); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
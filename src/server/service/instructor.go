@@ -0,0 +1,347 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	c "user-service/src/interfaces/config"
+	"user-service/src/interfaces/content"
+	Entity "user-service/src/interfaces/database/entity"
+	pb "user-service/src/interfaces/grpc"
+	Permission "user-service/src/interfaces/permission"
+	"user-service/src/interfaces/repository"
+	DTO "user-service/src/interfaces/server/dto"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/logger"
+	"half-nothing.cn/service-core/permission"
+	"half-nothing.cn/service-core/utils"
+)
+
+var (
+	ErrInstructorSelf        = dto.NewApiStatus("INSTRUCTOR_SELF", "不能将用户指派为自己的教员", dto.HttpCodeBadRequest)
+	ErrInstructorNotFound    = dto.NewApiStatus("INSTRUCTOR_NOT_FOUND", "教员不存在", dto.HttpCodeNotFound)
+	ErrInstructorRating      = dto.NewApiStatus("INSTRUCTOR_RATING_TOO_LOW", "教员的管制员等级不足", dto.HttpCodeBadRequest)
+	ErrInstructorRole        = dto.NewApiStatus("INSTRUCTOR_ROLE_REQUIRED", "教员未持有担任教员所需的角色", dto.HttpCodeBadRequest)
+	ErrInstructorBanned      = dto.NewApiStatus("INSTRUCTOR_BANNED", "教员已被封禁", dto.HttpCodeBadRequest)
+	ErrInstructorFull        = dto.NewApiStatus("INSTRUCTOR_TRAINEES_FULL", "教员指导的学员数已达上限", dto.HttpCodeBadRequest)
+	ErrInstructorUnchanged   = dto.NewApiStatus("INSTRUCTOR_UNCHANGED", "该教员已是学员当前的教员", dto.HttpCodeBadRequest)
+	ErrInstructorNotAssigned = dto.NewApiStatus("INSTRUCTOR_NOT_ASSIGNED", "学员当前没有指派教员", dto.HttpCodeNotFound)
+)
+
+// instructorNone 学员没有教员时在通知与审计日志中的描述
+const instructorNone = "无"
+
+type InstructorService struct {
+	logger   logger.Interface
+	config   *c.InstructorConfig
+	repo     repository.InstructorInterface
+	userRepo repository.UserInterface
+	scope    *scopeResolver
+	client   *content.GrpcClientManager
+}
+
+func NewInstructorService(
+	lg logger.Interface,
+	config *c.InstructorConfig,
+	repo repository.InstructorInterface,
+	userRepo repository.UserInterface,
+	divisionRepo repository.DivisionInterface,
+	client *content.GrpcClientManager,
+) *InstructorService {
+	adapter := logger.NewLoggerAdapter(lg, "instructor-service")
+	return &InstructorService{
+		logger:   adapter,
+		config:   config,
+		repo:     repo,
+		userRepo: userRepo,
+		scope:    newScopeResolver(adapter, divisionRepo),
+		client:   client,
+	}
+}
+
+func (service *InstructorService) getUser(userId uint, notFound *dto.ApiStatus) (*entity.User, *dto.ApiStatus) {
+	user, err := service.userRepo.GetById(userId)
+	if err != nil {
+		service.logger.Errorf("error occurred when get user: %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, notFound
+		}
+		return nil, ErrDataBaseError
+	}
+	return user, nil
+}
+
+// assignmentInfos 转换指派记录, 并按需填充学员与教员的基本信息
+func (service *InstructorService) assignmentInfos(
+	assignments []*Entity.InstructorAssignment,
+	withTrainee bool,
+	withInstructor bool,
+) ([]*DTO.InstructorAssignmentInfo, error) {
+	userIds := make([]uint, 0, len(assignments))
+	for _, assignment := range assignments {
+		if withTrainee && !slices.Contains(userIds, assignment.TraineeId) {
+			userIds = append(userIds, assignment.TraineeId)
+		}
+		if withInstructor && !slices.Contains(userIds, assignment.InstructorId) {
+			userIds = append(userIds, assignment.InstructorId)
+		}
+	}
+	users := make(map[uint]*DTO.BaseUserInfo, len(userIds))
+	if len(userIds) > 0 {
+		result, err := service.userRepo.GetByIds(userIds)
+		if err != nil {
+			return nil, err
+		}
+		utils.ForEach(result, func(index int, user *entity.User) {
+			users[user.ID] = (&DTO.BaseUserInfo{}).FromUserEntity(user, nil)
+		})
+	}
+	infos := make([]*DTO.InstructorAssignmentInfo, len(assignments))
+	utils.ForEach(assignments, func(index int, assignment *Entity.InstructorAssignment) {
+		info := (&DTO.InstructorAssignmentInfo{}).FromEntity(assignment)
+		if withTrainee {
+			info.Trainee = users[assignment.TraineeId]
+		}
+		if withInstructor {
+			info.Instructor = users[assignment.InstructorId]
+		}
+		infos[index] = info
+	})
+	return infos, nil
+}
+
+func (service *InstructorService) instructorHistory(traineeId uint) (*DTO.InstructorHistory, *dto.ApiStatus) {
+	assignments, err := service.repo.GetByTrainee(traineeId)
+	if err != nil {
+		service.logger.Errorf("error occurred when get instructor assignments: %v", err)
+		return nil, ErrDataBaseError
+	}
+	infos, err := service.assignmentInfos(assignments, false, true)
+	if err != nil {
+		service.logger.Errorf("error occurred when get instructors: %v", err)
+		return nil, ErrDataBaseError
+	}
+	history := &DTO.InstructorHistory{History: infos}
+	for _, info := range infos {
+		if info.EndedAt == nil {
+			history.Current = info
+			break
+		}
+	}
+	return history, nil
+}
+
+// checkInstructor 检查用户是否满足担任教员的条件, 学员数量上限在指派时检查
+func (service *InstructorService) checkInstructor(instructor *entity.User) *dto.ApiStatus {
+	if instructor.Banned {
+		return ErrInstructorBanned
+	}
+	if instructor.Rating < service.config.MinRating {
+		return ErrInstructorRating
+	}
+	if len(service.config.Roles) > 0 {
+		hasRole := slices.ContainsFunc(instructor.Roles, func(role *entity.UserRole) bool {
+			return role.Role != nil && slices.Contains(service.config.Roles, role.Role.Name)
+		})
+		if !hasRole {
+			return ErrInstructorRole
+		}
+	}
+	return nil
+}
+
+// notify 记录审计日志并通知学员教员变更
+func (service *InstructorService) notify(
+	event *Entity.AuditEvent,
+	operatorId uint,
+	ip string,
+	userAgent string,
+	trainee *entity.User,
+	oldInstructor string,
+	newInstructor string,
+	reason string,
+) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	operator, err := service.userRepo.GetById(operatorId)
+	if err != nil {
+		service.logger.Errorf("error occurred when get operator: %v", err)
+		return
+	}
+	_, err = service.client.AuditLogClient().Log(ctx, &pb.AuditLogRequest{
+		Event:     event.Value,
+		Subject:   fmt.Sprintf("%04d", operator.Cid),
+		Object:    fmt.Sprintf("%04d", trainee.Cid),
+		Ip:        ip,
+		UserAgent: userAgent,
+		OldValue:  oldInstructor,
+		NewValue:  fmt.Sprintf("%s (%s)", newInstructor, reason),
+	})
+	if err != nil {
+		service.logger.Errorf("error occurred when log audit: %v", err)
+	}
+	_, err = service.client.EmailClient().SendInstructorChange(ctx, &pb.InstructorChange{
+		TargetEmail: trainee.Email,
+		Cid:         fmt.Sprintf("%04d", trainee.Cid),
+		Reason:      reason,
+		Instructor:  newInstructor,
+		Operator:    fmt.Sprintf("%04d", operator.Cid),
+		Contact:     operator.Email,
+	})
+	if err != nil {
+		service.logger.Errorf("error occurred when send instructor change email: %v", err)
+	}
+}
+
+func (service *InstructorService) GetSelfInstructor(data *DTO.GetSelfInstructor) *dto.ApiResponse[*DTO.InstructorHistory] {
+	history, status := service.instructorHistory(data.Uid)
+	if status != nil {
+		return dto.NewApiResponse[*DTO.InstructorHistory](status, nil)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, history)
+}
+
+func (service *InstructorService) GetSelfTrainees(data *DTO.GetSelfTrainees) *dto.ApiResponse[*DTO.TraineeList] {
+	assignments, err := service.repo.GetTrainees(data.Uid)
+	if err != nil {
+		service.logger.Errorf("GetSelfTrainees handle fail, get trainees err, %v", err)
+		return dto.NewApiResponse[*DTO.TraineeList](ErrDataBaseError, nil)
+	}
+	infos, err := service.assignmentInfos(assignments, true, false)
+	if err != nil {
+		service.logger.Errorf("GetSelfTrainees handle fail, get trainee users err, %v", err)
+		return dto.NewApiResponse[*DTO.TraineeList](ErrDataBaseError, nil)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, &DTO.TraineeList{Trainees: infos})
+}
+
+func (service *InstructorService) GetUserInstructor(data *DTO.GetUserInstructor) *dto.ApiResponse[*DTO.InstructorHistory] {
+	scope, res := checkScope[*DTO.InstructorHistory](service.scope, data.Uid, data.Permission, permission.UserShowList)
+	if res != nil {
+		service.logger.Errorf("user %04d no permission to get user instructor", data.Cid)
+		return res
+	}
+	user, status := service.getUser(data.Id, ErrUserNotFound)
+	if status != nil {
+		return dto.NewApiResponse[*DTO.InstructorHistory](status, nil)
+	}
+	if res := checkUserInScope[*DTO.InstructorHistory](service.scope, scope, user.ID); res != nil {
+		service.logger.Errorf("user %04d no permission to get instructor of user %04d", data.Cid, user.Cid)
+		return res
+	}
+	history, status := service.instructorHistory(user.ID)
+	if status != nil {
+		return dto.NewApiResponse[*DTO.InstructorHistory](status, nil)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, history)
+}
+
+// AssignInstructor 为学员指派教员, 学员已有教员时替换为新的教员
+func (service *InstructorService) AssignInstructor(data *DTO.AssignInstructor) *dto.ApiResponse[bool] {
+	scope, res := checkScope[bool](service.scope, data.Uid, data.Permission, Permission.InstructorAssign)
+	if res != nil {
+		service.logger.Errorf("user %04d no permission to assign instructor", data.Cid)
+		return res
+	}
+	if data.Id == data.InstructorId {
+		return dto.NewApiResponse(ErrInstructorSelf, false)
+	}
+	trainee, status := service.getUser(data.Id, ErrUserNotFound)
+	if status != nil {
+		return dto.NewApiResponse(status, false)
+	}
+	if res := checkUserInScope[bool](service.scope, scope, trainee.ID); res != nil {
+		service.logger.Errorf("user %04d no permission to assign instructor of user %04d", data.Cid, trainee.Cid)
+		return res
+	}
+
+	oldInstructor := instructorNone
+	current, err := service.repo.GetCurrent(trainee.ID)
+	if err == nil {
+		if current.InstructorId == data.InstructorId {
+			return dto.NewApiResponse(ErrInstructorUnchanged, false)
+		}
+		old, status := service.getUser(current.InstructorId, ErrInstructorNotFound)
+		if status != nil {
+			return dto.NewApiResponse(status, false)
+		}
+		oldInstructor = fmt.Sprintf("%04d", old.Cid)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		service.logger.Errorf("AssignInstructor handle fail, get current assignment err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	instructor, status := service.getUser(data.InstructorId, ErrInstructorNotFound)
+	if status != nil {
+		return dto.NewApiResponse(status, false)
+	}
+	if status := service.checkInstructor(instructor); status != nil {
+		service.logger.Errorf("user %04d can not be instructor of user %04d", instructor.Cid, trainee.Cid)
+		return dto.NewApiResponse(status, false)
+	}
+
+	assignment := &Entity.InstructorAssignment{
+		TraineeId:    trainee.ID,
+		InstructorId: instructor.ID,
+		AssignedBy:   data.Uid,
+		Reason:       data.Reason,
+	}
+	if err := service.repo.Assign(assignment, service.config.MaxTrainees); err != nil {
+		service.logger.Errorf("AssignInstructor handle fail, assign instructor err, %v", err)
+		if errors.Is(err, repository.ErrInstructorFull) {
+			return dto.NewApiResponse(ErrInstructorFull, false)
+		}
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go service.notify(Entity.AuditEventInstructorAssigned, data.Uid, data.Ip, data.UserAgent, trainee,
+		oldInstructor, fmt.Sprintf("%04d", instructor.Cid), data.Reason)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+func (service *InstructorService) UnassignInstructor(data *DTO.UnassignInstructor) *dto.ApiResponse[bool] {
+	scope, res := checkScope[bool](service.scope, data.Uid, data.Permission, Permission.InstructorAssign)
+	if res != nil {
+		service.logger.Errorf("user %04d no permission to unassign instructor", data.Cid)
+		return res
+	}
+	trainee, status := service.getUser(data.Id, ErrUserNotFound)
+	if status != nil {
+		return dto.NewApiResponse(status, false)
+	}
+	if res := checkUserInScope[bool](service.scope, scope, trainee.ID); res != nil {
+		service.logger.Errorf("user %04d no permission to unassign instructor of user %04d", data.Cid, trainee.Cid)
+		return res
+	}
+	current, err := service.repo.GetCurrent(trainee.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse(ErrInstructorNotAssigned, false)
+		}
+		service.logger.Errorf("UnassignInstructor handle fail, get current assignment err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	instructor, status := service.getUser(current.InstructorId, ErrInstructorNotFound)
+	if status != nil {
+		return dto.NewApiResponse(status, false)
+	}
+	if err := service.repo.Unassign(current, data.Uid, data.Reason); err != nil {
+		service.logger.Errorf("UnassignInstructor handle fail, unassign instructor err, %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go service.notify(Entity.AuditEventInstructorUnassigned, data.Uid, data.Ip, data.UserAgent, trainee,
+		fmt.Sprintf("%04d", instructor.Cid), instructorNone, data.Reason)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
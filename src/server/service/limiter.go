@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+)
+
+// HttpCodeTooManyRequests service-core 未提供 429 状态码
+const HttpCodeTooManyRequests = dto.HttpCode(http.StatusTooManyRequests)
+
+var (
+	ErrTooManyRequests = dto.NewApiStatus("TOO_MANY_REQUESTS", "请求过于频繁, 请稍后再试", HttpCodeTooManyRequests)
+)
+
+// rateLimiterSweepSize 记录数超过该值时清理已过期的窗口
+const rateLimiterSweepSize = 10000
+
+type rateLimitEntry struct {
+	count   int
+	resetAt time.Time
+}
+
+// rateLimiter 基于固定窗口的内存限流器, 按键统计窗口内的请求次数
+type rateLimiter struct {
+	limit   int
+	window  time.Duration
+	lock    sync.Mutex
+	entries map[string]*rateLimitEntry
+}
+
+// newRateLimiter 创建限流器, limit 不大于 0 时不限流
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		window:  window,
+		entries: make(map[string]*rateLimitEntry),
+	}
+}
+
+func (limiter *rateLimiter) allow(key string) bool {
+	if limiter.limit <= 0 {
+		return true
+	}
+	limiter.lock.Lock()
+	defer limiter.lock.Unlock()
+	now := time.Now()
+	if len(limiter.entries) >= rateLimiterSweepSize {
+		for k, entry := range limiter.entries {
+			if !entry.resetAt.After(now) {
+				delete(limiter.entries, k)
+			}
+		}
+	}
+	entry, ok := limiter.entries[key]
+	if !ok || !entry.resetAt.After(now) {
+		limiter.entries[key] = &rateLimitEntry{count: 1, resetAt: now.Add(limiter.window)}
+		return true
+	}
+	if entry.count >= limiter.limit {
+		return false
+	}
+	entry.count++
+	return true
+}
+
+// failureCounter 基于固定窗口的失败次数统计, 用于判断是否需要额外的人机验证
+type failureCounter struct {
+	window  time.Duration
+	lock    sync.Mutex
+	entries map[string]*rateLimitEntry
+}
+
+func newFailureCounter(window time.Duration) *failureCounter {
+	return &failureCounter{
+		window:  window,
+		entries: make(map[string]*rateLimitEntry),
+	}
+}
+
+// count 获取窗口内的失败次数
+func (counter *failureCounter) count(key string) int {
+	counter.lock.Lock()
+	defer counter.lock.Unlock()
+	entry, ok := counter.entries[key]
+	if !ok || !entry.resetAt.After(time.Now()) {
+		return 0
+	}
+	return entry.count
+}
+
+// add 记录一次失败
+func (counter *failureCounter) add(key string) {
+	counter.lock.Lock()
+	defer counter.lock.Unlock()
+	now := time.Now()
+	if len(counter.entries) >= rateLimiterSweepSize {
+		for k, entry := range counter.entries {
+			if !entry.resetAt.After(now) {
+				delete(counter.entries, k)
+			}
+		}
+	}
+	entry, ok := counter.entries[key]
+	if !ok || !entry.resetAt.After(now) {
+		counter.entries[key] = &rateLimitEntry{count: 1, resetAt: now.Add(counter.window)}
+		return
+	}
+	entry.count++
+}
@@ -6,46 +6,69 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 	"user-service/src/interfaces/content"
+	Entity "user-service/src/interfaces/database/entity"
 	"user-service/src/interfaces/grpc"
+	Permission "user-service/src/interfaces/permission"
 	"user-service/src/interfaces/repository"
 	DTO "user-service/src/interfaces/server/dto"
 
 	"gorm.io/gorm"
 	"half-nothing.cn/service-core/interfaces/database/entity"
 	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/http/jwt"
 	"half-nothing.cn/service-core/interfaces/logger"
 	"half-nothing.cn/service-core/permission"
 	"half-nothing.cn/service-core/utils"
 )
 
 type PermissionService struct {
-	logger   logger.Interface
-	userRepo repository.UserInterface
-	roleRepo repository.RoleInterface
-	client   *content.GrpcClientManager
+	logger       logger.Interface
+	userRepo     repository.UserInterface
+	roleRepo     repository.RoleInterface
+	divisionRepo repository.DivisionInterface
+	scope        *scopeResolver
+	client       *content.GrpcClientManager
+	catalogs     map[string]*permissionCatalog
+}
+
+// permissionCatalog 预先生成的某一语言的权限目录及其 ETag
+type permissionCatalog struct {
+	etag    string
+	catalog *DTO.PermissionCatalog
 }
 
 func NewPermissionService(
 	lg logger.Interface,
 	userRepo repository.UserInterface,
 	roleRepo repository.RoleInterface,
+	divisionRepo repository.DivisionInterface,
 	client *content.GrpcClientManager,
 ) *PermissionService {
+	adapter := logger.NewLoggerAdapter(lg, "permission-service")
 	return &PermissionService{
-		logger:   logger.NewLoggerAdapter(lg, "permission-service"),
-		userRepo: userRepo,
-		roleRepo: roleRepo,
-		client:   client,
+		logger:       adapter,
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		divisionRepo: divisionRepo,
+		scope:        newScopeResolver(adapter, divisionRepo),
+		client:       client,
+		catalogs:     buildPermissionCatalogs(),
 	}
 }
 
 var (
 	ErrPermissionNodeNotFound = dto.NewApiStatus("PERMISSION_NODE_NOT_FOUND", "权限节点不存在", dto.HttpCodeNotFound)
+	ErrPermissionEscalation   = dto.NewApiStatus("PERMISSION_ESCALATION", "不能授予或撤销超出自身权限的权限或角色", dto.HttpCodePermissionDenied)
+	ErrEditSelfPermission     = dto.NewApiStatus("EDIT_SELF_PERMISSION", "不能修改自己的权限或角色", dto.HttpCodePermissionDenied)
 )
 
 func checkDatabaseError[T comparable](err error) *dto.ApiResponse[T] {
@@ -61,28 +84,29 @@ func updatePermission(
 	perm permission.Permission,
 	targetPerm uint64,
 	changeData map[string]bool,
-) (res *dto.ApiResponse[bool], resPerm permission.Permission, permGrant []string, permRevoke []string) {
+) (res *dto.ApiResponse[bool], resPerm permission.Permission, permGrant []string, permRevoke []string, deniedNode string) {
 	permGrant = make([]string, 0)
 	permRevoke = make([]string, 0)
 	resPerm = permission.Permission(targetPerm)
 
 	for k, v := range changeData {
-		if !permission.Permissions.IsValidEnum(k) {
+		node, ok := Permission.GetNode(k)
+		if !ok {
 			logger.Errorf("%s is not an valid permission node", k)
 			res = dto.NewApiResponse(ErrPermissionNodeNotFound, false)
 			return
 		}
-		node := permission.Permissions.GetEnum(k)
-		if !perm.HasPermission(node.Data) {
+		if !perm.HasPermission(node) {
 			logger.Errorf("user has no permission on permission node %s", k)
-			res = dto.NewApiResponse(dto.ErrNoPermission, false)
+			res = dto.NewApiResponse(ErrPermissionEscalation, false)
+			deniedNode = k
 			return
 		}
 		if v {
-			resPerm.Grant(node.Data)
+			resPerm.Grant(node)
 			permGrant = append(permGrant, k)
 		} else {
-			resPerm.Revoke(node.Data)
+			resPerm.Revoke(node)
 			permRevoke = append(permRevoke, k)
 		}
 	}
@@ -90,20 +114,75 @@ func updatePermission(
 	return
 }
 
+// getOperator 获取操作者及其当前的有效权限
+//
+// 有效权限从数据库实时计算, 而不是使用JWT中可能已经过期的权限
+func (service *PermissionService) getOperator(uid uint) (*entity.User, permission.Permission, *dto.ApiResponse[bool]) {
+	operator, err := service.userRepo.GetById(uid)
+	if err != nil {
+		service.logger.Errorf("get operator failed: %v", err)
+		return nil, 0, checkDatabaseError[bool](err)
+	}
+	return operator, Permission.Effective(operator), nil
+}
+
+// checkRoleEscalation 返回第一个权限超出操作者有效权限的角色, 全部合法时返回 nil
+func checkRoleEscalation(operatorPerm permission.Permission, roles []*entity.Role) *entity.Role {
+	for _, role := range roles {
+		if !Permission.Contains(operatorPerm, permission.Permission(role.Permission)) {
+			return role
+		}
+	}
+	return nil
+}
+
+// logPermissionDenied 记录被拒绝的越权操作
+func (service *PermissionService) logPermissionDenied(operator *entity.User, object string, reason string, ip string, userAgent string) {
+	go func(operator *entity.User, object string, reason string, ip string, userAgent string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := service.client.AuditLogClient().Log(ctx, &grpc.AuditLogRequest{
+			Event:     Entity.AuditEventPermissionEscalationDenied.Value,
+			Subject:   fmt.Sprintf("%04d", operator.Cid),
+			Object:    object,
+			Ip:        ip,
+			UserAgent: userAgent,
+			NewValue:  reason,
+		})
+		if err != nil {
+			service.logger.Errorf("log permission denied failed: %v", err)
+		}
+	}(operator, object, reason, ip, userAgent)
+}
+
 func (service *PermissionService) EditUserPermission(data *DTO.EditUserPermission) *dto.ApiResponse[bool] {
 	perm := permission.Permission(data.Permission)
 	if !perm.HasPermission(permission.UserEditPermission) {
 		return dto.NewApiResponse(dto.ErrNoPermission, false)
 	}
 
+	operator, operatorPerm, res := service.getOperator(data.Uid)
+	if res != nil {
+		return res
+	}
+
+	if operator.ID == data.UserId && !operatorPerm.HasPermission(Permission.SuperAdmin) {
+		service.logger.Errorf("user %04d try to edit own permission", operator.Cid)
+		service.logPermissionDenied(operator, fmt.Sprintf("%04d", operator.Cid), "edit own permission", data.Ip, data.UserAgent)
+		return dto.NewApiResponse(ErrEditSelfPermission, false)
+	}
+
 	targetUser, err := service.userRepo.GetById(data.UserId)
 	if err != nil {
 		service.logger.Errorf("get user failed: %v", err)
 		return checkDatabaseError[bool](err)
 	}
 
-	res, targetPerm, permGrant, permRevoke := updatePermission(service.logger, perm, targetUser.Permission, data.Data)
+	res, targetPerm, permGrant, permRevoke, deniedNode := updatePermission(service.logger, operatorPerm, targetUser.Permission, data.Data)
 	if res != nil {
+		if deniedNode != "" {
+			service.logPermissionDenied(operator, fmt.Sprintf("%04d", targetUser.Cid), "permission node "+deniedNode, data.Ip, data.UserAgent)
+		}
 		return res
 	}
 
@@ -112,8 +191,7 @@ func (service *PermissionService) EditUserPermission(data *DTO.EditUserPermissio
 		return dto.NewApiResponse(dto.ErrServerError, false)
 	}
 
-	go func(data *DTO.EditUserPermission, targetUser *entity.User, grantList []string, revokeList []string) {
-		user, _ := service.userRepo.GetById(data.Uid)
+	go func(data *DTO.EditUserPermission, user *entity.User, targetUser *entity.User, grantList []string, revokeList []string) {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 		auditLogRequest := &grpc.AuditLogRequest{
@@ -145,7 +223,7 @@ func (service *PermissionService) EditUserPermission(data *DTO.EditUserPermissio
 			Operator:    auditLogRequest.Subject,
 			Contact:     user.Email,
 		})
-	}(data, targetUser, permGrant, permRevoke)
+	}(data, operator, targetUser, permGrant, permRevoke)
 
 	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
 }
@@ -156,14 +234,32 @@ func (service *PermissionService) EditRolePermission(data *DTO.EditRolePermissio
 		return dto.NewApiResponse(dto.ErrNoPermission, false)
 	}
 
+	operator, operatorPerm, res := service.getOperator(data.Uid)
+	if res != nil {
+		return res
+	}
+
 	targetRole, err := service.roleRepo.GetById(data.RoleId)
 	if err != nil {
 		service.logger.Errorf("get role failed: %v", err)
 		return checkDatabaseError[bool](err)
 	}
 
-	res, targetPerm, permGrant, permRevoke := updatePermission(service.logger, perm, targetRole.Permission, data.Data)
+	if !operatorPerm.HasPermission(Permission.SuperAdmin) {
+		for _, userRole := range operator.Roles {
+			if userRole.RoleId == targetRole.ID {
+				service.logger.Errorf("user %04d try to edit permission of own role %d", operator.Cid, targetRole.ID)
+				service.logPermissionDenied(operator, fmt.Sprintf("%d(%s)", targetRole.ID, targetRole.Name), "edit permission of own role", data.Ip, data.UserAgent)
+				return dto.NewApiResponse(ErrEditSelfPermission, false)
+			}
+		}
+	}
+
+	res, targetPerm, permGrant, permRevoke, deniedNode := updatePermission(service.logger, operatorPerm, targetRole.Permission, data.Data)
 	if res != nil {
+		if deniedNode != "" {
+			service.logPermissionDenied(operator, fmt.Sprintf("%d(%s)", targetRole.ID, targetRole.Name), "permission node "+deniedNode, data.Ip, data.UserAgent)
+		}
 		return res
 	}
 
@@ -202,6 +298,30 @@ func (service *PermissionService) EditRolePermission(data *DTO.EditRolePermissio
 	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
 }
 
+// checkRoleOperation 校验操作者能否对目标用户授予或撤销指定角色
+//
+// 操作者不能修改自己的角色(超级管理员除外), 且角色中的每一个权限位都必须包含在操作者的有效权限中
+func (service *PermissionService) checkRoleOperation(
+	operator *entity.User,
+	operatorPerm permission.Permission,
+	userIds []uint,
+	roles []*entity.Role,
+	ip string,
+	userAgent string,
+) *dto.ApiResponse[bool] {
+	if !operatorPerm.HasPermission(Permission.SuperAdmin) && slices.Contains(userIds, operator.ID) {
+		service.logger.Errorf("user %04d try to edit own roles", operator.Cid)
+		service.logPermissionDenied(operator, fmt.Sprintf("%04d", operator.Cid), "edit own roles", ip, userAgent)
+		return dto.NewApiResponse(ErrEditSelfPermission, false)
+	}
+	if role := checkRoleEscalation(operatorPerm, roles); role != nil {
+		service.logger.Errorf("user %04d has no permission to operate role %d", operator.Cid, role.ID)
+		service.logPermissionDenied(operator, fmt.Sprintf("%d(%s)", role.ID, role.Name), "role permission exceeds operator permission", ip, userAgent)
+		return dto.NewApiResponse(ErrPermissionEscalation, false)
+	}
+	return nil
+}
+
 func (service *PermissionService) getUserAndRoles(userId uint, roleIds []uint) ([]*entity.Role, *entity.User, *dto.ApiResponse[bool]) {
 	roles, err := service.roleRepo.GetByIds(roleIds)
 	if err != nil {
@@ -224,22 +344,36 @@ func (service *PermissionService) GrantUserRole(data *DTO.GrantUserRole) *dto.Ap
 		return dto.NewApiResponse(dto.ErrNoPermission, false)
 	}
 
+	operator, operatorPerm, res := service.getOperator(data.Uid)
+	if res != nil {
+		return res
+	}
+
 	roles, targetUser, res := service.getUserAndRoles(data.UserId, data.RoleIds)
 	if res != nil {
 		return res
 	}
 
-	if err := service.userRepo.GrantRole(targetUser.ID, data.RoleIds); err != nil {
+	if res := service.checkRoleOperation(operator, operatorPerm, []uint{targetUser.ID}, roles, data.Ip, data.UserAgent); res != nil {
+		return res
+	}
+
+	roles = slices.DeleteFunc(roles, func(role *entity.Role) bool { return userHasRole(targetUser, role.ID) })
+	if len(roles) == 0 {
+		service.logger.Infof("user %04d already has all roles, no change", targetUser.Cid)
+		return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+	}
+
+	if err := service.userRepo.GrantRole(targetUser.ID, roleIdsOf(roles)); err != nil {
 		service.logger.Errorf("grant user role failed: %v", err)
 		return checkDatabaseError[bool](err)
 	}
 
-	go func(data *DTO.GrantUserRole, targetUser *entity.User, roles []*entity.Role) {
+	go func(data *DTO.GrantUserRole, user *entity.User, targetUser *entity.User, roles []*entity.Role) {
 		newRoles := make([]string, len(roles))
 		utils.ForEach(roles, func(index int, role *entity.Role) {
 			newRoles[index] = role.Name
 		})
-		user, _ := service.userRepo.GetById(data.Uid)
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		_, err := service.client.AuditLogClient().Log(ctx, &grpc.AuditLogRequest{
@@ -263,7 +397,7 @@ func (service *PermissionService) GrantUserRole(data *DTO.GrantUserRole) *dto.Ap
 		if err != nil {
 			service.logger.Errorf("send role change email failed: %v", err)
 		}
-	}(data, targetUser, roles)
+	}(data, operator, targetUser, roles)
 
 	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
 }
@@ -275,22 +409,36 @@ func (service *PermissionService) RevokeUserRole(data *DTO.RevokeUserRole) *dto.
 		return dto.NewApiResponse(dto.ErrNoPermission, false)
 	}
 
+	operator, operatorPerm, res := service.getOperator(data.Uid)
+	if res != nil {
+		return res
+	}
+
 	roles, targetUser, res := service.getUserAndRoles(data.UserId, data.RoleIds)
 	if res != nil {
 		return res
 	}
 
-	if err := service.userRepo.RevokeRole(targetUser.ID, data.RoleIds); err != nil {
+	if res := service.checkRoleOperation(operator, operatorPerm, []uint{targetUser.ID}, roles, data.Ip, data.UserAgent); res != nil {
+		return res
+	}
+
+	roles = slices.DeleteFunc(roles, func(role *entity.Role) bool { return !userHasRole(targetUser, role.ID) })
+	if len(roles) == 0 {
+		service.logger.Infof("user %04d has none of the roles, no change", targetUser.Cid)
+		return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+	}
+
+	if err := service.userRepo.RevokeRole(targetUser.ID, roleIdsOf(roles)); err != nil {
 		service.logger.Errorf("revoke user role failed: %v", err)
 		return checkDatabaseError[bool](err)
 	}
 
-	go func(data *DTO.RevokeUserRole, targetUser *entity.User, roles []*entity.Role) {
+	go func(data *DTO.RevokeUserRole, user *entity.User, targetUser *entity.User, roles []*entity.Role) {
 		oldRoles := make([]string, len(roles))
 		utils.ForEach(roles, func(index int, role *entity.Role) {
 			oldRoles[index] = role.Name
 		})
-		user, _ := service.userRepo.GetById(data.Uid)
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		_, err := service.client.AuditLogClient().Log(ctx, &grpc.AuditLogRequest{
@@ -314,11 +462,44 @@ func (service *PermissionService) RevokeUserRole(data *DTO.RevokeUserRole) *dto.
 		if err != nil {
 			service.logger.Errorf("send role change email failed: %v", err)
 		}
-	}(data, targetUser, roles)
+	}(data, operator, targetUser, roles)
 
 	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
 }
 
+func userHasRole(user *entity.User, roleId uint) bool {
+	return slices.ContainsFunc(user.Roles, func(userRole *entity.UserRole) bool { return userRole.RoleId == roleId })
+}
+
+func roleIdsOf(roles []*entity.Role) []uint {
+	roleIds := make([]uint, len(roles))
+	utils.ForEach(roles, func(index int, role *entity.Role) {
+		roleIds[index] = role.ID
+	})
+	return roleIds
+}
+
+func userIdsOf(users []*entity.User) []uint {
+	userIds := make([]uint, len(users))
+	utils.ForEach(users, func(index int, user *entity.User) {
+		userIds[index] = user.ID
+	})
+	return userIds
+}
+
+func (service *PermissionService) getRoleMemberIds(roleId uint) ([]uint, *dto.ApiResponse[bool]) {
+	userRoles, err := service.roleRepo.GetRoleUsers(roleId)
+	if err != nil {
+		service.logger.Errorf("get role users failed: %v", err)
+		return nil, checkDatabaseError[bool](err)
+	}
+	memberIds := make([]uint, len(userRoles))
+	utils.ForEach(userRoles, func(index int, userRole *entity.UserRole) {
+		memberIds[index] = userRole.UserId
+	})
+	return memberIds, nil
+}
+
 func (service *PermissionService) getRoleAndUsers(roleId uint, userIds []uint) ([]*entity.User, *entity.Role, *dto.ApiResponse[bool]) {
 	users, err := service.userRepo.GetByIds(userIds)
 	if err != nil {
@@ -342,23 +523,41 @@ func (service *PermissionService) GrantRoleUser(data *DTO.GrantRoleUser) *dto.Ap
 		return dto.NewApiResponse(dto.ErrNoPermission, false)
 	}
 
+	operator, operatorPerm, res := service.getOperator(data.Uid)
+	if res != nil {
+		return res
+	}
+
 	users, role, res := service.getRoleAndUsers(data.RoleId, data.UserIds)
 	if res != nil {
 		return res
 	}
 
-	if err := service.roleRepo.GrantUser(role.ID, data.UserIds); err != nil {
+	if res := service.checkRoleOperation(operator, operatorPerm, data.UserIds, []*entity.Role{role}, data.Ip, data.UserAgent); res != nil {
+		return res
+	}
+
+	memberIds, res := service.getRoleMemberIds(role.ID)
+	if res != nil {
+		return res
+	}
+	users = slices.DeleteFunc(users, func(user *entity.User) bool { return slices.Contains(memberIds, user.ID) })
+	if len(users) == 0 {
+		service.logger.Infof("all users already have role %d(%s), no change", role.ID, role.Name)
+		return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+	}
+
+	if err := service.roleRepo.GrantUser(role.ID, userIdsOf(users)); err != nil {
 		service.logger.Errorf("grant role user failed: %v", err)
 		return checkDatabaseError[bool](err)
 	}
 
-	go func(data *DTO.GrantRoleUser, users []*entity.User, role *entity.Role) {
+	go func(data *DTO.GrantRoleUser, user *entity.User, users []*entity.User, role *entity.Role) {
 		userCids := make([]string, len(users))
 		utils.ForEach(users, func(index int, user *entity.User) {
 			userCids[index] = fmt.Sprintf("%04d", user.Cid)
 		})
 
-		user, _ := service.userRepo.GetById(data.Uid)
 		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(len(users)+1)*5*time.Second)
 		defer cancel()
 
@@ -388,7 +587,7 @@ func (service *PermissionService) GrantRoleUser(data *DTO.GrantRoleUser) *dto.Ap
 		if err != nil {
 			service.logger.Errorf("send role change email failed: %v", err)
 		}
-	}(data, users, role)
+	}(data, operator, users, role)
 
 	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
 }
@@ -400,23 +599,41 @@ func (service *PermissionService) RevokeRoleUser(data *DTO.RevokeRoleUser) *dto.
 		return dto.NewApiResponse(dto.ErrNoPermission, false)
 	}
 
+	operator, operatorPerm, res := service.getOperator(data.Uid)
+	if res != nil {
+		return res
+	}
+
 	users, role, res := service.getRoleAndUsers(data.RoleId, data.UserIds)
 	if res != nil {
 		return res
 	}
 
-	if err := service.roleRepo.RevokeUser(role.ID, data.UserIds); err != nil {
+	if res := service.checkRoleOperation(operator, operatorPerm, data.UserIds, []*entity.Role{role}, data.Ip, data.UserAgent); res != nil {
+		return res
+	}
+
+	memberIds, res := service.getRoleMemberIds(role.ID)
+	if res != nil {
+		return res
+	}
+	users = slices.DeleteFunc(users, func(user *entity.User) bool { return !slices.Contains(memberIds, user.ID) })
+	if len(users) == 0 {
+		service.logger.Infof("none of the users has role %d(%s), no change", role.ID, role.Name)
+		return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+	}
+
+	if err := service.roleRepo.RevokeUser(role.ID, userIdsOf(users)); err != nil {
 		service.logger.Errorf("revoke role user failed: %v", err)
 		return checkDatabaseError[bool](err)
 	}
 
-	go func(data *DTO.RevokeRoleUser, users []*entity.User, role *entity.Role) {
+	go func(data *DTO.RevokeRoleUser, user *entity.User, users []*entity.User, role *entity.Role) {
 		userCids := make([]string, len(users))
 		utils.ForEach(users, func(index int, user *entity.User) {
 			userCids[index] = fmt.Sprintf("%04d", user.Cid)
 		})
 
-		user, _ := service.userRepo.GetById(data.Uid)
 		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(len(users)+1)*5*time.Second)
 		defer cancel()
 
@@ -446,7 +663,325 @@ func (service *PermissionService) RevokeRoleUser(data *DTO.RevokeRoleUser) *dto.
 		if err != nil {
 			service.logger.Errorf("send role change email failed: %v", err)
 		}
-	}(data, users, role)
+	}(data, operator, users, role)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+// SetRoleUsers 将角色成员设置为给定的用户列表, 只有成员关系实际发生变化的用户会收到通知
+func (service *PermissionService) SetRoleUsers(data *DTO.SetRoleUsers) *dto.ApiResponse[bool] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(permission.UserEditRole) {
+		return dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+
+	operator, operatorPerm, res := service.getOperator(data.Uid)
+	if res != nil {
+		return res
+	}
+
+	userIds := slices.Compact(slices.Sorted(slices.Values(*data.UserIds)))
+	users, role, res := service.getRoleAndUsers(data.RoleId, userIds)
+	if res != nil {
+		return res
+	}
+	if len(users) != len(userIds) {
+		service.logger.Errorf("some of users %v not found", userIds)
+		return dto.NewApiResponse(ErrUserNotFound, false)
+	}
+
+	userRoles, err := service.roleRepo.GetRoleUsers(role.ID)
+	if err != nil {
+		service.logger.Errorf("get role users failed: %v", err)
+		return checkDatabaseError[bool](err)
+	}
+
+	added := slices.DeleteFunc(slices.Clone(users), func(user *entity.User) bool {
+		return slices.ContainsFunc(userRoles, func(userRole *entity.UserRole) bool { return userRole.UserId == user.ID })
+	})
+	removed := make([]*entity.User, 0)
+	for _, userRole := range userRoles {
+		if userRole.User != nil && !slices.Contains(userIds, userRole.UserId) {
+			removed = append(removed, userRole.User)
+		}
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		service.logger.Infof("role %d(%s) membership no change", role.ID, role.Name)
+		return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+	}
+
+	changedIds := append(userIdsOf(added), userIdsOf(removed)...)
+	if res := service.checkRoleOperation(operator, operatorPerm, changedIds, []*entity.Role{role}, data.Ip, data.UserAgent); res != nil {
+		return res
+	}
+
+	if err := service.roleRepo.SyncUsers(role.ID, userIdsOf(added), userIdsOf(removed)); err != nil {
+		service.logger.Errorf("sync role users failed: %v", err)
+		return checkDatabaseError[bool](err)
+	}
+
+	go func(data *DTO.SetRoleUsers, user *entity.User, role *entity.Role, added []*entity.User, removed []*entity.User) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(len(added)+len(removed)+1)*5*time.Second)
+		defer cancel()
+
+		notify := func(users []*entity.User, event string, roles string) {
+			if len(users) == 0 {
+				return
+			}
+			userCids := make([]string, len(users))
+			emails := make([]string, len(users))
+			utils.ForEach(users, func(index int, user *entity.User) {
+				userCids[index] = fmt.Sprintf("%04d", user.Cid)
+				emails[index] = user.Email
+			})
+			auditLogRequest := &grpc.AuditLogRequest{
+				Event:     event,
+				Subject:   fmt.Sprintf("%04d", data.Cid),
+				Object:    fmt.Sprintf("%d(%s)", role.ID, role.Name),
+				Ip:        data.Ip,
+				UserAgent: data.UserAgent,
+			}
+			if event == entity.AuditEventRoleGrant.Value {
+				auditLogRequest.NewValue = strings.Join(userCids, ",")
+			} else {
+				auditLogRequest.OldValue = strings.Join(userCids, ",")
+			}
+			if _, err := service.client.AuditLogClient().Log(ctx, auditLogRequest); err != nil {
+				service.logger.Errorf("log role membership change failed: %v", err)
+			}
+			_, err := service.client.EmailClient().SendRoleChange(ctx, &grpc.RoleChange{
+				TargetEmail: emails,
+				Cid:         strings.Join(userCids, ","),
+				Roles:       roles,
+				Operator:    fmt.Sprintf("%04d", user.Cid),
+				Contact:     user.Email,
+			})
+			if err != nil {
+				service.logger.Errorf("send role change email failed: %v", err)
+			}
+		}
+
+		notify(added, entity.AuditEventRoleGrant.Value, fmt.Sprintf("+%s", role.Name))
+		notify(removed, entity.AuditEventRoleRevoke.Value, fmt.Sprintf("-%s", role.Name))
+	}(data, operator, role, added, removed)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+// getScopedRoleTarget 校验分区角色授予/撤销操作, 返回目标分区、目标用户与角色
+//
+// 操作者需在该分区内持有 UserEditRole 权限, 角色权限不能超出操作者在该分区内的有效权限, 且目标用户必须属于该分区
+func (service *PermissionService) getScopedRoleTarget(
+	data *jwt.Content,
+	httpContent *dto.HttpContent,
+	divisionId uint,
+	userId uint,
+	roleIds []uint,
+) (*entity.User, *Entity.Division, *entity.User, []*entity.Role, *dto.ApiResponse[bool]) {
+	scope, res := checkScope[bool](service.scope, data.Uid, data.Permission, permission.UserEditRole)
+	if res != nil {
+		service.logger.Errorf("user %04d no permission to edit scoped roles", data.Cid)
+		return nil, nil, nil, nil, res
+	}
+	if !scope.Contains([]uint{divisionId}) {
+		service.logger.Errorf("user %04d no permission to edit scoped roles of division %d", data.Cid, divisionId)
+		return nil, nil, nil, nil, dto.NewApiResponse(ErrOutOfScope, false)
+	}
+
+	operator, operatorPerm, res := service.getOperator(data.Uid)
+	if res != nil {
+		return nil, nil, nil, nil, res
+	}
+
+	division, err := service.divisionRepo.GetById(divisionId)
+	if err != nil {
+		service.logger.Errorf("get division failed: %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil, nil, dto.NewApiResponse(ErrDivisionNotFound, false)
+		}
+		return nil, nil, nil, nil, dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	roles, targetUser, res := service.getUserAndRoles(userId, roleIds)
+	if res != nil {
+		return nil, nil, nil, nil, res
+	}
+
+	divisionIds, err := service.divisionRepo.GetUserDivisionIds(targetUser.ID)
+	if err != nil {
+		service.logger.Errorf("get user divisions failed: %v", err)
+		return nil, nil, nil, nil, dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if !slices.Contains(divisionIds, division.ID) {
+		service.logger.Errorf("user %04d is not a member of division %d", targetUser.Cid, division.ID)
+		return nil, nil, nil, nil, dto.NewApiResponse(ErrUserNotInDivision, false)
+	}
+
+	operatorPerm, err = service.scope.effectiveIn(operator, operatorPerm, division.ID)
+	if err != nil {
+		service.logger.Errorf("get operator scoped permission failed: %v", err)
+		return nil, nil, nil, nil, dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	if res := service.checkRoleOperation(operator, operatorPerm, []uint{targetUser.ID}, roles, httpContent.Ip, httpContent.UserAgent); res != nil {
+		return nil, nil, nil, nil, res
+	}
+
+	return operator, division, targetUser, roles, nil
+}
+
+//goland:noinspection DuplicatedCode
+func (service *PermissionService) GrantScopedRole(data *DTO.GrantScopedRole) *dto.ApiResponse[bool] {
+	operator, division, targetUser, roles, res := service.getScopedRoleTarget(&data.Content, &data.HttpContent, data.DivisionId, data.UserId, data.RoleIds)
+	if res != nil {
+		return res
+	}
+
+	if err := service.divisionRepo.GrantScopedRole(division.ID, targetUser.ID, data.RoleIds); err != nil {
+		service.logger.Errorf("grant scoped role failed: %v", err)
+		return checkDatabaseError[bool](err)
+	}
+
+	go func(data *DTO.GrantScopedRole, user *entity.User, division *Entity.Division, targetUser *entity.User, roles []*entity.Role) {
+		newRoles := make([]string, len(roles))
+		utils.ForEach(roles, func(index int, role *entity.Role) {
+			newRoles[index] = fmt.Sprintf("%s@%s", role.Name, division.Code)
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err := service.client.AuditLogClient().Log(ctx, &grpc.AuditLogRequest{
+			Event:     Entity.AuditEventScopedRoleGrant.Value,
+			Subject:   fmt.Sprintf("%04d", user.Cid),
+			Object:    fmt.Sprintf("%04d", targetUser.Cid),
+			Ip:        data.Ip,
+			UserAgent: data.UserAgent,
+			NewValue:  strings.Join(newRoles, ","),
+		})
+		if err != nil {
+			service.logger.Errorf("log scoped role grant failed: %v", err)
+		}
+		_, err = service.client.EmailClient().SendRoleChange(ctx, &grpc.RoleChange{
+			TargetEmail: []string{targetUser.Email},
+			Cid:         fmt.Sprintf("%04d", targetUser.Cid),
+			Roles:       "\n+" + strings.Join(newRoles, "\n+"),
+			Operator:    fmt.Sprintf("%04d", user.Cid),
+			Contact:     user.Email,
+		})
+		if err != nil {
+			service.logger.Errorf("send role change email failed: %v", err)
+		}
+	}(data, operator, division, targetUser, roles)
 
 	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
 }
+
+//goland:noinspection DuplicatedCode
+func (service *PermissionService) RevokeScopedRole(data *DTO.RevokeScopedRole) *dto.ApiResponse[bool] {
+	operator, division, targetUser, roles, res := service.getScopedRoleTarget(&data.Content, &data.HttpContent, data.DivisionId, data.UserId, data.RoleIds)
+	if res != nil {
+		return res
+	}
+
+	if err := service.divisionRepo.RevokeScopedRole(division.ID, targetUser.ID, data.RoleIds); err != nil {
+		service.logger.Errorf("revoke scoped role failed: %v", err)
+		return checkDatabaseError[bool](err)
+	}
+
+	go func(data *DTO.RevokeScopedRole, user *entity.User, division *Entity.Division, targetUser *entity.User, roles []*entity.Role) {
+		oldRoles := make([]string, len(roles))
+		utils.ForEach(roles, func(index int, role *entity.Role) {
+			oldRoles[index] = fmt.Sprintf("%s@%s", role.Name, division.Code)
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err := service.client.AuditLogClient().Log(ctx, &grpc.AuditLogRequest{
+			Event:     Entity.AuditEventScopedRoleRevoke.Value,
+			Subject:   fmt.Sprintf("%04d", user.Cid),
+			Object:    fmt.Sprintf("%04d", targetUser.Cid),
+			Ip:        data.Ip,
+			UserAgent: data.UserAgent,
+			OldValue:  strings.Join(oldRoles, ","),
+		})
+		if err != nil {
+			service.logger.Errorf("log scoped role revoke failed: %v", err)
+		}
+		_, err = service.client.EmailClient().SendRoleChange(ctx, &grpc.RoleChange{
+			TargetEmail: []string{targetUser.Email},
+			Cid:         fmt.Sprintf("%04d", targetUser.Cid),
+			Roles:       "\n-" + strings.Join(oldRoles, "\n-"),
+			Operator:    fmt.Sprintf("%04d", user.Cid),
+			Contact:     user.Email,
+		})
+		if err != nil {
+			service.logger.Errorf("send role change email failed: %v", err)
+		}
+	}(data, operator, division, targetUser, roles)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+// buildPermissionCatalogs 为每种支持的语言生成权限目录, 目录内容在运行期间不变
+func buildPermissionCatalogs() map[string]*permissionCatalog {
+	catalogs := make(map[string]*permissionCatalog, len(Permission.Languages))
+	for _, lang := range Permission.Languages {
+		nodes := make([]*DTO.PermissionNode, 0, len(Permission.Catalog))
+		for _, node := range Permission.Catalog {
+			implies := node.Implies
+			if implies == nil {
+				implies = make([]string, 0)
+			}
+			nodes = append(nodes, &DTO.PermissionNode{
+				Name:        node.Name,
+				Bit:         node.Bit(),
+				Value:       uint64(node.Node),
+				DisplayName: node.Names[lang],
+				Category:    node.Category,
+				Implies:     implies,
+			})
+		}
+		catalog := &DTO.PermissionCatalog{Lang: lang, Nodes: nodes}
+		raw, _ := json.Marshal(catalog)
+		sum := sha256.Sum256(raw)
+		catalogs[lang] = &permissionCatalog{
+			etag:    fmt.Sprintf("\"%s\"", hex.EncodeToString(sum[:8])),
+			catalog: catalog,
+		}
+	}
+	return catalogs
+}
+
+// matchLanguage 根据请求的语言匹配支持的语言, 无法匹配时使用默认语言
+func matchLanguage(lang string) string {
+	for _, part := range strings.Split(lang, ",") {
+		tag := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		if tag == "" {
+			continue
+		}
+		for _, supported := range Permission.Languages {
+			if tag == strings.ToLower(supported) || strings.SplitN(tag, "-", 2)[0] == strings.ToLower(supported) {
+				return supported
+			}
+		}
+		if strings.HasPrefix(tag, "zh") {
+			return Permission.LangZhCN
+		}
+	}
+	return Permission.Languages[0]
+}
+
+func (service *PermissionService) GetCatalog(data *DTO.GetPermissionCatalog) (string, *dto.ApiResponse[*DTO.PermissionCatalog]) {
+	catalog := service.catalogs[matchLanguage(data.Lang)]
+	return catalog.etag, dto.NewApiResponse(dto.SuccessHandleRequest, catalog.catalog)
+}
+
+func (service *PermissionService) DecodePermission(data *DTO.DecodePermission) *dto.ApiResponse[*DTO.DecodedPermission] {
+	nodes, unknown := Permission.Decode(data.Value)
+	if unknown != 0 {
+		service.logger.Infof("permission %d contains unknown bits %d", data.Value, unknown)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, &DTO.DecodedPermission{
+		Value:       data.Value,
+		Nodes:       nodes,
+		UnknownBits: unknown,
+	})
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+	Entity "user-service/src/interfaces/database/entity"
+	"user-service/src/interfaces/repository"
+	DTO "user-service/src/interfaces/server/dto"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+)
+
+var (
+	ErrTimezoneInvalid   = dto.NewApiStatus("TIMEZONE_INVALID", "时区无效", dto.HttpCodeBadRequest)
+	ErrLanguageInvalid   = dto.NewApiStatus("LANGUAGE_INVALID", "语言无效", dto.HttpCodeBadRequest)
+	ErrProfileTooLong    = dto.NewApiStatus("PROFILE_TOO_LONG", "资料内容过长", dto.HttpCodeBadRequest)
+	ErrFieldNotFound     = dto.NewApiStatus("PROFILE_FIELD_NOT_FOUND", "自定义资料字段不存在", dto.HttpCodeNotFound)
+	ErrFieldNotEditable  = dto.NewApiStatus("PROFILE_FIELD_NOT_EDITABLE", "无权修改该资料字段", dto.HttpCodeBadRequest)
+	ErrFieldValueInvalid = dto.NewApiStatus("PROFILE_FIELD_VALUE_INVALID", "资料字段值格式错误", dto.HttpCodeBadRequest)
+)
+
+const (
+	profileFieldMaxLength = 64
+	customFieldMaxLength  = 255
+	customFieldDateLayout = "2006-01-02"
+)
+
+var languageRegexp = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// getProfile 获取用户扩展资料, 用户尚未填写资料时返回 nil
+func getProfile(repo repository.ProfileInterface, userId uint) (*Entity.UserProfile, error) {
+	profile, err := repo.GetByUserId(userId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return profile, nil
+}
+
+// getProfiles 批量获取用户扩展资料, 返回用户 ID 到资料的映射
+func getProfiles(repo repository.ProfileInterface, users []*entity.User) (map[uint]*Entity.UserProfile, error) {
+	userIds := make([]uint, len(users))
+	for index, user := range users {
+		userIds[index] = user.ID
+	}
+	return repo.GetByUserIds(userIds)
+}
+
+// profileChanges 计算扩展资料的修改内容, 返回修改前的值与需要更新的字段
+func profileChanges(
+	divisionRepo repository.DivisionInterface,
+	profile *Entity.UserProfile,
+	data *DTO.UpdateProfile,
+) (map[string]interface{}, map[string]interface{}, *dto.ApiStatus) {
+	if profile == nil {
+		profile = Entity.DefaultUserProfile(0)
+	}
+	oldValue := map[string]interface{}{}
+	updates := map[string]interface{}{}
+	if len([]rune(data.DisplayName)) > profileFieldMaxLength || len([]rune(data.RealName)) > profileFieldMaxLength {
+		return nil, nil, ErrProfileTooLong
+	}
+	if data.DisplayName != "" && profile.DisplayName != data.DisplayName {
+		oldValue["display_name"] = profile.DisplayName
+		updates["display_name"] = data.DisplayName
+	}
+	if data.RealName != "" && profile.RealName != data.RealName {
+		oldValue["real_name"] = profile.RealName
+		updates["real_name"] = data.RealName
+	}
+	if data.Timezone != "" && profile.Timezone != data.Timezone {
+		if _, err := time.LoadLocation(data.Timezone); err != nil {
+			return nil, nil, ErrTimezoneInvalid
+		}
+		oldValue["timezone"] = profile.Timezone
+		updates["timezone"] = data.Timezone
+	}
+	if data.Language != "" && profile.Language != data.Language {
+		if !languageRegexp.MatchString(data.Language) {
+			return nil, nil, ErrLanguageInvalid
+		}
+		oldValue["language"] = profile.Language
+		updates["language"] = data.Language
+	}
+	if data.DivisionId != nil {
+		// DivisionId 为 0 表示清除所属分区
+		var divisionId *uint
+		if *data.DivisionId != 0 {
+			if _, err := divisionRepo.GetById(*data.DivisionId); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil, nil, ErrDivisionNotFound
+				}
+				return nil, nil, ErrDataBaseError
+			}
+			divisionId = data.DivisionId
+		}
+		if (divisionId == nil) != (profile.DivisionId == nil) ||
+			(divisionId != nil && *divisionId != *profile.DivisionId) {
+			oldValue["division_id"] = profile.DivisionId
+			updates["division_id"] = divisionId
+		}
+	}
+	if data.Privacy != nil {
+		privacyChanges(profile, data.Privacy, oldValue, updates)
+	}
+	return oldValue, updates, nil
+}
+
+// privacyChanges 计算隐私设置的修改内容
+func privacyChanges(profile *Entity.UserProfile, data *DTO.UpdatePrivacy, oldValue map[string]interface{}, updates map[string]interface{}) {
+	settings := []struct {
+		column  string
+		current bool
+		value   *bool
+	}{
+		{"show_username", profile.ShowUsername, data.ShowUsername},
+		{"show_avatar", profile.ShowAvatar, data.ShowAvatar},
+		{"show_rating", profile.ShowRating, data.ShowRating},
+		{"show_roles", profile.ShowRoles, data.ShowRoles},
+		{"show_division", profile.ShowDivision, data.ShowDivision},
+		{"allow_qq_avatar", profile.AllowQQAvatar, data.AllowQQAvatar},
+		{"allow_gravatar", profile.AllowGravatar, data.AllowGravatar},
+	}
+	for _, setting := range settings {
+		if setting.value != nil && *setting.value != setting.current {
+			oldValue[setting.column] = setting.current
+			updates[setting.column] = *setting.value
+		}
+	}
+}
+
+// normalizeFieldValue 按字段类型与正则校验字段值, 返回规范化后的值
+func normalizeFieldValue(field *Entity.ProfileField, value string) (string, bool) {
+	if len([]rune(value)) > customFieldMaxLength {
+		return "", false
+	}
+	switch field.Type {
+	case Entity.FieldTypeInt:
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", false
+		}
+		value = strconv.FormatInt(number, 10)
+	case Entity.FieldTypeBool:
+		flag, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", false
+		}
+		value = strconv.FormatBool(flag)
+	case Entity.FieldTypeEnum:
+		if !slices.Contains(field.Options, value) {
+			return "", false
+		}
+	case Entity.FieldTypeDate:
+		if _, err := time.Parse(customFieldDateLayout, value); err != nil {
+			return "", false
+		}
+	}
+	if field.Regex != "" {
+		matched, err := regexp.MatchString(field.Regex, value)
+		if err != nil || !matched {
+			return "", false
+		}
+	}
+	return value, true
+}
+
+// customFieldChanges 计算自定义字段的修改内容, editor 为修改者所处的编辑范围
+//
+// 返回修改前的值、修改后的值(以 custom_fields.<key> 为键, 用于审计)与需要写入的字段值, 值为空字符串表示删除
+func customFieldChanges(
+	fieldRepo repository.ProfileFieldInterface,
+	profile *Entity.UserProfile,
+	values map[string]string,
+	editor string,
+) (map[string]interface{}, map[string]interface{}, map[uint]string, *dto.ApiStatus) {
+	oldValue := map[string]interface{}{}
+	newValue := map[string]interface{}{}
+	fieldValues := map[uint]string{}
+	if len(values) == 0 {
+		return oldValue, newValue, fieldValues, nil
+	}
+	fields, err := fieldRepo.GetAll()
+	if err != nil {
+		return nil, nil, nil, ErrDataBaseError
+	}
+	fieldMap := make(map[string]*Entity.ProfileField, len(fields))
+	for _, field := range fields {
+		fieldMap[field.Key] = field
+	}
+	current := map[uint]string{}
+	if profile != nil {
+		for _, value := range profile.Fields {
+			current[value.FieldId] = value.Value
+		}
+	}
+	for key, value := range values {
+		field, ok := fieldMap[key]
+		if !ok {
+			return nil, nil, nil, ErrFieldNotFound
+		}
+		if !Entity.CanView(field.Editable, editor) {
+			return nil, nil, nil, ErrFieldNotEditable
+		}
+		if value != "" {
+			if value, ok = normalizeFieldValue(field, value); !ok {
+				return nil, nil, nil, ErrFieldValueInvalid
+			}
+		}
+		if current[field.ID] == value {
+			continue
+		}
+		oldValue["custom_fields."+key] = current[field.ID]
+		newValue["custom_fields."+key] = value
+		fieldValues[field.ID] = value
+	}
+	return oldValue, newValue, fieldValues, nil
+}
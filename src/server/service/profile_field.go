@@ -0,0 +1,239 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+	"user-service/src/interfaces/content"
+	Entity "user-service/src/interfaces/database/entity"
+	"user-service/src/interfaces/grpc"
+	Permission "user-service/src/interfaces/permission"
+	"user-service/src/interfaces/repository"
+	DTO "user-service/src/interfaces/server/dto"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/logger"
+	"half-nothing.cn/service-core/permission"
+	"half-nothing.cn/service-core/utils"
+)
+
+type ProfileFieldService struct {
+	logger logger.Interface
+	repo   repository.ProfileFieldInterface
+	client *content.GrpcClientManager
+}
+
+func NewProfileFieldService(
+	lg logger.Interface,
+	repo repository.ProfileFieldInterface,
+	client *content.GrpcClientManager,
+) *ProfileFieldService {
+	return &ProfileFieldService{
+		logger: logger.NewLoggerAdapter(lg, "profile-field-service"),
+		repo:   repo,
+		client: client,
+	}
+}
+
+var (
+	ErrFieldKeyExists         = dto.NewApiStatus("PROFILE_FIELD_KEY_EXISTS", "资料字段标识已存在", dto.HttpCodeConflict)
+	ErrFieldTypeInvalid       = dto.NewApiStatus("PROFILE_FIELD_TYPE_INVALID", "资料字段类型无效", dto.HttpCodeBadRequest)
+	ErrFieldOptionsInvalid    = dto.NewApiStatus("PROFILE_FIELD_OPTIONS_INVALID", "枚举类型字段必须提供可选值", dto.HttpCodeBadRequest)
+	ErrFieldRegexInvalid      = dto.NewApiStatus("PROFILE_FIELD_REGEX_INVALID", "资料字段校验正则无效", dto.HttpCodeBadRequest)
+	ErrFieldVisibilityInvalid = dto.NewApiStatus("PROFILE_FIELD_VISIBILITY_INVALID", "资料字段可见范围或编辑范围无效", dto.HttpCodeBadRequest)
+	ErrFieldEditableHidden    = dto.NewApiStatus("PROFILE_FIELD_EDITABLE_HIDDEN", "资料字段的编辑范围必须能够查看该字段", dto.HttpCodeBadRequest)
+	ErrFieldValuesMismatch    = dto.NewApiStatus("PROFILE_FIELD_VALUES_MISMATCH", "已有用户的字段值不符合新的可选值或校验正则", dto.HttpCodeConflict)
+)
+
+// checkFieldDefinition 校验字段定义, 编辑范围仅支持 self 与 staff, 且必须能够查看该字段
+func checkFieldDefinition(field *Entity.ProfileField) *dto.ApiStatus {
+	if !slices.Contains(Entity.FieldTypes, field.Type) {
+		return ErrFieldTypeInvalid
+	}
+	if field.Type == Entity.FieldTypeEnum && len(field.Options) == 0 {
+		return ErrFieldOptionsInvalid
+	}
+	if field.Regex != "" {
+		if _, err := regexp.Compile(field.Regex); err != nil {
+			return ErrFieldRegexInvalid
+		}
+	}
+	if !Entity.ValidVisibility(field.Visibility) ||
+		(field.Editable != Entity.VisibilitySelf && field.Editable != Entity.VisibilityStaff) {
+		return ErrFieldVisibilityInvalid
+	}
+	if !Entity.CanView(field.Visibility, field.Editable) {
+		return ErrFieldEditableHidden
+	}
+	return nil
+}
+
+func (service *ProfileFieldService) logAudit(event *Entity.AuditEvent, cid uint, field *Entity.ProfileField, ip string, userAgent string, oldValue string, newValue string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := service.client.AuditLogClient().Log(ctx, &grpc.AuditLogRequest{
+		Event:     event.Value,
+		Subject:   fmt.Sprintf("%04d", cid),
+		Object:    field.Key,
+		Ip:        ip,
+		UserAgent: userAgent,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+	})
+	if err != nil {
+		service.logger.Errorf("error occurred when create audit log: %v", err)
+	}
+}
+
+func fieldSnapshot(field *Entity.ProfileField) string {
+	snapshot, _ := json.Marshal((&DTO.ProfileFieldInfo{}).FromEntity(field))
+	return string(snapshot)
+}
+
+// GetFields 获取自定义资料字段定义, 非管理人员只能看到公开与本人可见的字段
+func (service *ProfileFieldService) GetFields(data *DTO.GetProfileFields) *dto.ApiResponse[*DTO.ProfileFieldList] {
+	fields, err := service.repo.GetAll()
+	if err != nil {
+		service.logger.Errorf("error occurred when get profile fields: %v", err)
+		return dto.NewApiResponse[*DTO.ProfileFieldList](ErrDataBaseError, nil)
+	}
+	perm := permission.Permission(data.Permission)
+	viewer := Entity.VisibilitySelf
+	if perm.HasPermission(permission.UserShowList) || perm.HasPermission(Permission.ProfileFieldManage) {
+		viewer = Entity.VisibilityStaff
+	}
+	fieldInfos := make([]*DTO.ProfileFieldInfo, 0, len(fields))
+	utils.ForEach(fields, func(_ int, field *Entity.ProfileField) {
+		if Entity.CanView(field.Visibility, viewer) {
+			fieldInfos = append(fieldInfos, (&DTO.ProfileFieldInfo{}).FromEntity(field))
+		}
+	})
+	return dto.NewApiResponse(dto.SuccessHandleRequest, &DTO.ProfileFieldList{Fields: fieldInfos})
+}
+
+func (service *ProfileFieldService) Create(data *DTO.CreateProfileField) *dto.ApiResponse[bool] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(Permission.ProfileFieldManage) {
+		service.logger.Errorf("user %04d no permission to create profile field", data.Cid)
+		return dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+
+	field := &Entity.ProfileField{
+		Key:        data.Key,
+		Name:       data.Name,
+		Type:       data.Type,
+		Options:    data.Options,
+		Regex:      data.Regex,
+		Visibility: data.Visibility,
+		Editable:   data.Editable,
+	}
+	if status := checkFieldDefinition(field); status != nil {
+		return dto.NewApiResponse(status, false)
+	}
+	if _, err := service.repo.GetByKey(field.Key); err == nil {
+		return dto.NewApiResponse(ErrFieldKeyExists, false)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		service.logger.Errorf("error occurred when get profile field by key: %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if err := service.repo.Save(field); err != nil {
+		service.logger.Errorf("error occurred when create profile field: %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go service.logAudit(Entity.AuditEventProfileFieldCreated, data.Cid, field, data.Ip, data.UserAgent, "", fieldSnapshot(field))
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+// Update 修改字段定义, 字段标识与类型创建后不可修改, 已有字段值不符合新定义时拒绝修改
+func (service *ProfileFieldService) Update(data *DTO.UpdateProfileField) *dto.ApiResponse[bool] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(Permission.ProfileFieldManage) {
+		service.logger.Errorf("user %04d no permission to edit profile field", data.Cid)
+		return dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+
+	field, err := service.repo.GetById(data.Id)
+	if err != nil {
+		service.logger.Errorf("error occurred when get profile field by id: %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse(ErrFieldNotFound, false)
+		}
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	oldValue := fieldSnapshot(field)
+	if data.Name != "" {
+		field.Name = data.Name
+	}
+	if data.Options != nil {
+		field.Options = data.Options
+	}
+	if data.Regex != nil {
+		field.Regex = *data.Regex
+	}
+	if data.Visibility != "" {
+		field.Visibility = data.Visibility
+	}
+	if data.Editable != "" {
+		field.Editable = data.Editable
+	}
+	if status := checkFieldDefinition(field); status != nil {
+		return dto.NewApiResponse(status, false)
+	}
+	// 可选值或校验正则改变时已有的字段值必须仍然合法
+	var check func(value string) bool
+	if data.Options != nil || data.Regex != nil {
+		check = func(value string) bool {
+			_, ok := normalizeFieldValue(field, value)
+			return ok
+		}
+	}
+	if err := service.repo.UpdateField(field, check); err != nil {
+		service.logger.Errorf("error occurred when update profile field: %v", err)
+		if errors.Is(err, repository.ErrFieldValuesMismatch) {
+			return dto.NewApiResponse(ErrFieldValuesMismatch, false)
+		}
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go service.logAudit(Entity.AuditEventProfileFieldUpdated, data.Cid, field, data.Ip, data.UserAgent, oldValue, fieldSnapshot(field))
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
+
+// Delete 删除字段定义及所有用户的字段值
+func (service *ProfileFieldService) Delete(data *DTO.DeleteProfileField) *dto.ApiResponse[bool] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(Permission.ProfileFieldManage) {
+		service.logger.Errorf("user %04d no permission to delete profile field", data.Cid)
+		return dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+
+	field, err := service.repo.GetById(data.Id)
+	if err != nil {
+		service.logger.Errorf("error occurred when get profile field by id: %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse(ErrFieldNotFound, false)
+		}
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if err := service.repo.DeleteField(field.ID); err != nil {
+		service.logger.Errorf("error occurred when delete profile field: %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go service.logAudit(Entity.AuditEventProfileFieldDeleted, data.Cid, field, data.Ip, data.UserAgent, fieldSnapshot(field), "")
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"errors"
+	DTO "user-service/src/interfaces/server/dto"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/utils"
+)
+
+const publicProfileBatchLimit = 100
+
+var (
+	ErrProfileBatchTooLarge = dto.NewApiStatus("PROFILE_BATCH_TOO_LARGE", "批量查询数量超出限制", dto.HttpCodeBadRequest)
+)
+
+// GetPublicProfile 获取用户公开资料, 路径中的 cid 优先于 id
+func (u *UserService) GetPublicProfile(data *DTO.GetPublicProfile) *dto.ApiResponse[*DTO.PublicUserInfo] {
+	var user *entity.User
+	var err error
+	if data.Cid > 0 {
+		user, err = u.repo.GetByCid(data.Cid)
+	} else if data.Id > 0 {
+		user, err = u.repo.GetById(data.Id)
+	} else {
+		return dto.NewApiResponse[*DTO.PublicUserInfo](dto.ErrErrorParam, nil)
+	}
+	if err != nil {
+		u.logger.Errorf("GetPublicProfile handle fail, get user err, %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse[*DTO.PublicUserInfo](ErrUserNotFound, nil)
+		}
+		return dto.NewApiResponse[*DTO.PublicUserInfo](ErrDataBaseError, nil)
+	}
+	// 已注销的用户不再提供公开资料
+	if _, status := u.getPendingDeletion(user.ID); status != nil {
+		if status == ErrUserDeleted {
+			return dto.NewApiResponse[*DTO.PublicUserInfo](ErrUserNotFound, nil)
+		}
+		return dto.NewApiResponse[*DTO.PublicUserInfo](status, nil)
+	}
+	profile, err := getProfile(u.profileRepo, user.ID)
+	if err != nil {
+		u.logger.Errorf("GetPublicProfile handle fail, get profile err, %v", err)
+		return dto.NewApiResponse[*DTO.PublicUserInfo](ErrDataBaseError, nil)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, (&DTO.PublicUserInfo{}).FromUserEntity(user, profile))
+}
+
+// BatchGetPublicProfile 按用户 ID 与呼号批量获取公开资料, 结果按呼号排序, 已注销的用户不在结果中
+func (u *UserService) BatchGetPublicProfile(data *DTO.BatchGetPublicProfile) *dto.ApiResponse[*DTO.PublicProfileList] {
+	if len(data.Ids)+len(data.Cids) > publicProfileBatchLimit {
+		return dto.NewApiResponse[*DTO.PublicProfileList](ErrProfileBatchTooLarge, nil)
+	}
+	users, err := u.repo.GetByIdsOrCids(data.Ids, data.Cids)
+	if err != nil {
+		u.logger.Errorf("BatchGetPublicProfile handle fail, get users err, %v", err)
+		return dto.NewApiResponse[*DTO.PublicProfileList](ErrDataBaseError, nil)
+	}
+	userIds := make([]uint, len(users))
+	utils.ForEach(users, func(index int, element *entity.User) {
+		userIds[index] = element.ID
+	})
+	profiles, err := u.profileRepo.GetByUserIds(userIds)
+	if err != nil {
+		u.logger.Errorf("BatchGetPublicProfile handle fail, get profiles err, %v", err)
+		return dto.NewApiResponse[*DTO.PublicProfileList](ErrDataBaseError, nil)
+	}
+	userInfos := make([]*DTO.PublicUserInfo, len(users))
+	utils.ForEach(users, func(index int, element *entity.User) {
+		userInfos[index] = (&DTO.PublicUserInfo{}).FromUserEntity(element, profiles[element.ID])
+	})
+	return dto.NewApiResponse(dto.SuccessHandleRequest, &DTO.PublicProfileList{Profiles: userInfos})
+}
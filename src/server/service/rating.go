@@ -0,0 +1,262 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	c "user-service/src/interfaces/config"
+	"user-service/src/interfaces/content"
+	Entity "user-service/src/interfaces/database/entity"
+	pb "user-service/src/interfaces/grpc"
+	Permission "user-service/src/interfaces/permission"
+	"user-service/src/interfaces/repository"
+	DTO "user-service/src/interfaces/server/dto"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/logger"
+	"half-nothing.cn/service-core/permission"
+	"half-nothing.cn/service-core/utils"
+)
+
+var (
+	ErrRatingInvalid       = dto.NewApiStatus("RATING_INVALID", "管制员等级不存在", dto.HttpCodeBadRequest)
+	ErrRatingUnchanged     = dto.NewApiStatus("RATING_UNCHANGED", "管制员等级未发生变化", dto.HttpCodeBadRequest)
+	ErrRatingSelf          = dto.NewApiStatus("RATING_SELF_CHANGE", "不能修改自己的管制员等级", dto.HttpCodePermissionDenied)
+	ErrRatingDemotion      = dto.NewApiStatus("RATING_DEMOTION_DISABLED", "不允许降低管制员等级", dto.HttpCodeBadRequest)
+	ErrRatingStepTooLarge  = dto.NewApiStatus("RATING_STEP_TOO_LARGE", "超出单次允许晋升的等级数", dto.HttpCodeBadRequest)
+	ErrRatingTimeNotEnough = dto.NewApiStatus("RATING_TIME_NOT_ENOUGH", "未满足当前等级的最短停留时间", dto.HttpCodeBadRequest)
+	ErrRatingConflict      = dto.NewApiStatus("RATING_CONFLICT", "用户等级已被修改, 请刷新后重试", dto.HttpCodeConflict)
+)
+
+type RatingService struct {
+	logger   logger.Interface
+	config   *c.RatingConfig
+	repo     repository.RatingInterface
+	userRepo repository.UserInterface
+	scope    *scopeResolver
+	client   *content.GrpcClientManager
+}
+
+func NewRatingService(
+	lg logger.Interface,
+	config *c.RatingConfig,
+	repo repository.RatingInterface,
+	userRepo repository.UserInterface,
+	divisionRepo repository.DivisionInterface,
+	client *content.GrpcClientManager,
+) *RatingService {
+	adapter := logger.NewLoggerAdapter(lg, "rating-service")
+	return &RatingService{
+		logger:   adapter,
+		config:   config,
+		repo:     repo,
+		userRepo: userRepo,
+		scope:    newScopeResolver(adapter, divisionRepo),
+		client:   client,
+	}
+}
+
+// ratingName 获取等级名称, 未在配置中定义的等级使用数值表示
+func (service *RatingService) ratingName(value int) string {
+	if index := service.config.Index(value); index >= 0 {
+		return service.config.Levels[index].Name
+	}
+	return strconv.Itoa(value)
+}
+
+func (service *RatingService) getUser(userId uint) (*entity.User, *dto.ApiStatus) {
+	user, err := service.userRepo.GetById(userId)
+	if err != nil {
+		service.logger.Errorf("error occurred when get user: %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrDataBaseError
+	}
+	return user, nil
+}
+
+// ratingSince 获取用户获得当前等级的时间, 没有变更记录时为注册时间
+func (service *RatingService) ratingSince(user *entity.User) (time.Time, error) {
+	latest, err := service.repo.GetLatest(user.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user.CreatedAt, nil
+		}
+		return time.Time{}, err
+	}
+	return latest.CreatedAt, nil
+}
+
+// checkTransition 检查等级变更是否符合配置的晋升规则
+func (service *RatingService) checkTransition(user *entity.User, newRating int) *dto.ApiStatus {
+	newIndex := service.config.Index(newRating)
+	if newIndex < 0 {
+		return ErrRatingInvalid
+	}
+	if newRating == user.Rating {
+		return ErrRatingUnchanged
+	}
+	oldIndex := service.config.Index(user.Rating)
+	if oldIndex < 0 {
+		// 当前等级未在配置中定义时无法判断晋升规则, 仅允许修正为已定义的等级
+		return nil
+	}
+	if newIndex < oldIndex {
+		if !service.config.AllowDemotion {
+			return ErrRatingDemotion
+		}
+		return nil
+	}
+	if service.config.MaxPromotionStep > 0 && newIndex-oldIndex > service.config.MaxPromotionStep {
+		return ErrRatingStepTooLarge
+	}
+	minTime := service.config.Levels[oldIndex].MinTimeDuration
+	if minTime <= 0 {
+		return nil
+	}
+	since, err := service.ratingSince(user)
+	if err != nil {
+		service.logger.Errorf("error occurred when get latest rating change: %v", err)
+		return ErrDataBaseError
+	}
+	if time.Since(since) < minTime {
+		return ErrRatingTimeNotEnough
+	}
+	return nil
+}
+
+func (service *RatingService) GetRating(data *DTO.GetRating) *dto.ApiResponse[*DTO.RatingInfo] {
+	if data.Id != data.Uid {
+		scope, res := checkScope[*DTO.RatingInfo](service.scope, data.Uid, data.Permission, permission.UserShowList)
+		if res != nil {
+			service.logger.Errorf("user %04d no permission to get user rating", data.Cid)
+			return res
+		}
+		if res := checkUserInScope[*DTO.RatingInfo](service.scope, scope, data.Id); res != nil {
+			service.logger.Errorf("user %04d no permission to get rating of user %d", data.Cid, data.Id)
+			return res
+		}
+	}
+
+	user, status := service.getUser(data.Id)
+	if status != nil {
+		return dto.NewApiResponse[*DTO.RatingInfo](status, nil)
+	}
+	info, err := service.ratingInfo(user)
+	if err != nil {
+		service.logger.Errorf("GetRating handle fail, get rating changes err, %v", err)
+		return dto.NewApiResponse[*DTO.RatingInfo](ErrDataBaseError, nil)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, info)
+}
+
+// ratingInfo 获取用户当前等级及等级变更历史
+func (service *RatingService) ratingInfo(user *entity.User) (*DTO.RatingInfo, error) {
+	changes, err := service.repo.GetByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &DTO.RatingInfo{
+		Rating:  user.Rating,
+		Name:    service.ratingName(user.Rating),
+		Since:   user.CreatedAt,
+		History: make([]*DTO.RatingChangeInfo, len(changes)),
+	}
+	if len(changes) > 0 {
+		info.Since = changes[0].CreatedAt
+	}
+	utils.ForEach(changes, func(index int, change *Entity.RatingChange) {
+		info.History[index] = (&DTO.RatingChangeInfo{}).FromEntity(change, service.ratingName(change.OldRating), service.ratingName(change.NewRating))
+	})
+	return info, nil
+}
+
+// ChangeRating 修改用户管制员等级, 记录变更历史并通知用户
+func (service *RatingService) ChangeRating(data *DTO.ChangeRating) *dto.ApiResponse[bool] {
+	if data.Rating == nil {
+		return dto.NewApiResponse(dto.ErrErrorParam, false)
+	}
+	scope, res := checkScope[bool](service.scope, data.Uid, data.Permission, Permission.UserEditRating)
+	if res != nil {
+		service.logger.Errorf("user %04d no permission to change user rating", data.Cid)
+		return res
+	}
+	if data.Id == data.Uid {
+		service.logger.Errorf("user %04d try to change own rating", data.Cid)
+		return dto.NewApiResponse(ErrRatingSelf, false)
+	}
+
+	user, status := service.getUser(data.Id)
+	if status != nil {
+		return dto.NewApiResponse(status, false)
+	}
+	if res := checkUserInScope[bool](service.scope, scope, user.ID); res != nil {
+		service.logger.Errorf("user %04d no permission to change rating of user %04d", data.Cid, user.Cid)
+		return res
+	}
+	if status := service.checkTransition(user, *data.Rating); status != nil {
+		return dto.NewApiResponse(status, false)
+	}
+
+	change := &Entity.RatingChange{
+		UserId:     user.ID,
+		OldRating:  user.Rating,
+		NewRating:  *data.Rating,
+		OperatorId: data.Uid,
+		Reason:     data.Reason,
+	}
+	if err := service.repo.ChangeRating(change); err != nil {
+		service.logger.Errorf("ChangeRating handle fail, change rating err, %v", err)
+		if errors.Is(err, repository.ErrRatingChanged) {
+			return dto.NewApiResponse(ErrRatingConflict, false)
+		}
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	go func(data *DTO.ChangeRating, user *entity.User, change *Entity.RatingChange) {
+		oldName := service.ratingName(change.OldRating)
+		newName := service.ratingName(change.NewRating)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err := service.client.AuditLogClient().Log(ctx, &pb.AuditLogRequest{
+			Event:     Entity.AuditEventRatingChanged.Value,
+			Subject:   fmt.Sprintf("%04d", data.Cid),
+			Object:    fmt.Sprintf("%04d", user.Cid),
+			Ip:        data.Ip,
+			UserAgent: data.UserAgent,
+			OldValue:  oldName,
+			NewValue:  fmt.Sprintf("%s (%s)", newName, change.Reason),
+		})
+		if err != nil {
+			service.logger.Errorf("error occurred when log audit: %v", err)
+		}
+		operator, err := service.userRepo.GetById(data.Uid)
+		if err != nil {
+			service.logger.Errorf("error occurred when get operator: %v", err)
+			return
+		}
+		_, err = service.client.EmailClient().SendAtcRatingChange(ctx, &pb.AtcRatingChange{
+			TargetEmail: user.Email,
+			Cid:         fmt.Sprintf("%04d", user.Cid),
+			NewValue:    newName,
+			OldValue:    oldName,
+			Operator:    fmt.Sprintf("%04d", operator.Cid),
+			Contact:     operator.Email,
+		})
+		if err != nil {
+			service.logger.Errorf("error occurred when send rating change email: %v", err)
+		}
+	}(data, user, change)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+}
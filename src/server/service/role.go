@@ -8,10 +8,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 	"user-service/src/interfaces/content"
+	Entity "user-service/src/interfaces/database/entity"
 	"user-service/src/interfaces/grpc"
+	Permission "user-service/src/interfaces/permission"
 	"user-service/src/interfaces/repository"
 	DTO "user-service/src/interfaces/server/dto"
 
@@ -24,20 +28,26 @@ import (
 )
 
 type RoleService struct {
-	logger logger.Interface
-	repo   repository.RoleInterface
-	client *content.GrpcClientManager
+	logger      logger.Interface
+	repo        repository.RoleInterface
+	userRepo    repository.UserInterface
+	profileRepo repository.ProfileInterface
+	client      *content.GrpcClientManager
 }
 
 func NewRoleService(
 	lg logger.Interface,
 	repo repository.RoleInterface,
+	userRepo repository.UserInterface,
+	profileRepo repository.ProfileInterface,
 	client *content.GrpcClientManager,
 ) *RoleService {
 	return &RoleService{
-		logger: logger.NewLoggerAdapter(lg, "role-service"),
-		repo:   repo,
-		client: client,
+		logger:      logger.NewLoggerAdapter(lg, "role-service"),
+		repo:        repo,
+		userRepo:    userRepo,
+		profileRepo: profileRepo,
+		client:      client,
 	}
 }
 
@@ -73,19 +83,56 @@ func (service *RoleService) GetById(data *DTO.GetRoleDetail) *dto.ApiResponse[*D
 	role, err := service.repo.GetById(data.Id)
 	if err != nil {
 		service.logger.Errorf("error occurred when get role by id: %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse[*DTO.RoleInfo](ErrRoleNotFound, nil)
+		}
 		return dto.NewApiResponse[*DTO.RoleInfo](ErrDataBaseError, nil)
 	}
 	roleInfo := &DTO.RoleInfo{}
 	roleInfo.FromRoleEntity(role)
-	userRoles, err := service.repo.GetRoleUsers(role.ID)
-	roleInfo.Users = make([]*DTO.BaseUserInfo, len(userRoles))
-	utils.ForEach(userRoles, func(index int, userRole *entity.UserRole) {
-		roleInfo.Users[index] = &DTO.BaseUserInfo{}
-		roleInfo.Users[index].FromUserEntity(userRole.User)
-	})
+	roleInfo.UserCount, err = service.repo.CountRoleUsers(role.ID)
+	if err != nil {
+		service.logger.Errorf("error occurred when count role users: %v", err)
+		return dto.NewApiResponse[*DTO.RoleInfo](ErrDataBaseError, nil)
+	}
 	return dto.NewApiResponse(dto.SuccessHandleRequest, roleInfo)
 }
 
+func (service *RoleService) GetUsers(data *DTO.GetRoleUserPage) *dto.ApiResponse[*DTO.GetRoleUserPageResponse] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(permission.RoleShowList) {
+		service.logger.Errorf("user %04d no permission to show role list", data.Cid)
+		return dto.NewApiResponse[*DTO.GetRoleUserPageResponse](dto.ErrNoPermission, nil)
+	}
+	if _, err := service.repo.GetById(data.Id); err != nil {
+		service.logger.Errorf("error occurred when get role by id: %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse[*DTO.GetRoleUserPageResponse](ErrRoleNotFound, nil)
+		}
+		return dto.NewApiResponse[*DTO.GetRoleUserPageResponse](ErrDataBaseError, nil)
+	}
+	users, total, err := service.userRepo.GetRoleUserPages(data.Id, data.PageNum, data.PageSize, data.Search)
+	if err != nil {
+		service.logger.Errorf("error occurred when get role users: %v", err)
+		return dto.NewApiResponse[*DTO.GetRoleUserPageResponse](ErrDataBaseError, nil)
+	}
+	profiles, err := getProfiles(service.profileRepo, users)
+	if err != nil {
+		service.logger.Errorf("error occurred when get profiles: %v", err)
+		return dto.NewApiResponse[*DTO.GetRoleUserPageResponse](ErrDataBaseError, nil)
+	}
+	userInfos := make([]*DTO.BaseUserInfo, len(users))
+	utils.ForEach(users, func(index int, user *entity.User) {
+		userInfos[index] = (&DTO.BaseUserInfo{}).FromUserEntity(user, profiles[user.ID])
+	})
+	return dto.NewApiResponse(dto.SuccessHandleRequest, &DTO.GetRoleUserPageResponse{
+		Data:     userInfos,
+		Total:    int(total),
+		PageNum:  data.PageNum,
+		PageSize: data.PageSize,
+	})
+}
+
 //goland:noinspection DuplicatedCode
 func (service *RoleService) Create(role *DTO.CreateRole) *dto.ApiResponse[bool] {
 	perm := permission.Permission(role.Permission)
@@ -181,53 +228,652 @@ func (service *RoleService) Update(role *DTO.UpdateRole) *dto.ApiResponse[bool]
 	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
 }
 
-var ErrRoleHasUsers = dto.NewApiStatus("ROLE_HAS_USERS", "角色下有用户", dto.HttpCodeConflict)
+var (
+	ErrRoleHasUsers           = dto.NewApiStatus("ROLE_HAS_USERS", "角色下有用户", dto.HttpCodeConflict)
+	ErrReplacementRoleInvalid = dto.NewApiStatus("REPLACEMENT_ROLE_INVALID", "替代角色不存在或与被删除角色相同", dto.HttpCodeBadRequest)
+)
 
-func (service *RoleService) Delete(role *DTO.DeleteRole) *dto.ApiResponse[bool] {
-	perm := permission.Permission(role.Permission)
+// getDeletionTarget 获取将被删除的角色与可选的替代角色
+func (service *RoleService) getDeletionTarget(roleId uint, replacementId uint) (*entity.Role, *entity.Role, *dto.ApiStatus) {
+	role, err := service.repo.GetById(roleId)
+	if err != nil {
+		service.logger.Errorf("error occurred when get role by id: %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrRoleNotFound
+		}
+		return nil, nil, ErrDataBaseError
+	}
+	if replacementId == 0 {
+		return role, nil, nil
+	}
+	if replacementId == roleId {
+		return nil, nil, ErrReplacementRoleInvalid
+	}
+	replacement, err := service.repo.GetById(replacementId)
+	if err != nil {
+		service.logger.Errorf("error occurred when get replacement role by id: %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrReplacementRoleInvalid
+		}
+		return nil, nil, ErrDataBaseError
+	}
+	return role, replacement, nil
+}
+
+// computeDeletionImpact 计算删除角色后每个成员实际失去的权限节点, 已由其他角色或用户自身权限覆盖的节点不计入
+func computeDeletionImpact(users []*entity.User, profiles map[uint]*Entity.UserProfile, role *entity.Role, replacement *entity.Role) []*DTO.RoleDeletionImpactUser {
+	impacts := make([]*DTO.RoleDeletionImpactUser, len(users))
+	utils.ForEach(users, func(index int, user *entity.User) {
+		remaining := permission.Permission(user.Permission)
+		utils.ForEach(user.Roles, func(_ int, userRole *entity.UserRole) {
+			if userRole.RoleId != role.ID && userRole.Role != nil {
+				remaining.Merge(permission.Permission(userRole.Role.Permission))
+			}
+		})
+		if replacement != nil {
+			remaining.Merge(permission.Permission(replacement.Permission))
+		}
+		lost, _ := Permission.Decode(role.Permission &^ uint64(remaining))
+		impacts[index] = &DTO.RoleDeletionImpactUser{
+			User:            (&DTO.BaseUserInfo{}).FromUserEntity(user, profiles[user.ID]),
+			LostPermissions: lost,
+		}
+	})
+	return impacts
+}
+
+func (service *RoleService) GetDeletionImpact(data *DTO.GetRoleDeletionImpact) *dto.ApiResponse[*DTO.RoleDeletionImpact] {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(permission.RoleShowList) {
+		service.logger.Errorf("user %04d no permission to show role list", data.Cid)
+		return dto.NewApiResponse[*DTO.RoleDeletionImpact](dto.ErrNoPermission, nil)
+	}
+
+	role, replacement, status := service.getDeletionTarget(data.Id, data.ReplacementId)
+	if status != nil {
+		return dto.NewApiResponse[*DTO.RoleDeletionImpact](status, nil)
+	}
+
+	users, err := service.userRepo.GetByRole(role.ID)
+	if err != nil {
+		service.logger.Errorf("error occurred when get role users: %v", err)
+		return dto.NewApiResponse[*DTO.RoleDeletionImpact](ErrDataBaseError, nil)
+	}
+	profiles, err := getProfiles(service.profileRepo, users)
+	if err != nil {
+		service.logger.Errorf("error occurred when get profiles: %v", err)
+		return dto.NewApiResponse[*DTO.RoleDeletionImpact](ErrDataBaseError, nil)
+	}
+
+	impact := &DTO.RoleDeletionImpact{
+		Role:  &DTO.BaseRoleInfo{},
+		Users: computeDeletionImpact(users, profiles, role, replacement),
+	}
+	impact.Role.FromRoleEntity(role)
+	if replacement != nil {
+		impact.Replacement = &DTO.BaseRoleInfo{}
+		impact.Replacement.FromRoleEntity(replacement)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, impact)
+}
+
+// Delete 删除角色
+//
+// 角色下仍有用户时必须指定 Force 或替代角色, 指定替代角色时成员会被转移到替代角色,
+// 每个受影响的用户都会收到角色变更邮件并记录审计日志
+func (service *RoleService) Delete(data *DTO.DeleteRole) *dto.ApiResponse[bool] {
+	perm := permission.Permission(data.Permission)
 	if !perm.HasPermission(permission.RoleDelete) {
-		service.logger.Errorf("user %04d no permission to delete role", role.Cid)
+		service.logger.Errorf("user %04d no permission to delete role", data.Cid)
+		return dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+	if data.ReplacementId != 0 && !perm.HasPermission(permission.UserEditRole) {
+		service.logger.Errorf("user %04d no permission to reassign role members", data.Cid)
 		return dto.NewApiResponse(dto.ErrNoPermission, false)
 	}
 
-	userRoles, err := service.repo.GetRoleUsers(role.Id)
+	role, replacement, status := service.getDeletionTarget(data.Id, data.ReplacementId)
+	if status != nil {
+		return dto.NewApiResponse(status, false)
+	}
+
+	operator, err := service.userRepo.GetById(data.Uid)
+	if err != nil {
+		service.logger.Errorf("get operator failed: %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+	if replacement != nil && !Permission.Contains(Permission.Effective(operator), permission.Permission(replacement.Permission)) {
+		service.logger.Errorf("user %04d has no permission to grant role %d(%s)", data.Cid, replacement.ID, replacement.Name)
+		return dto.NewApiResponse(ErrPermissionEscalation, false)
+	}
+
+	users, err := service.userRepo.GetByRole(role.ID)
 	if err != nil {
 		service.logger.Errorf("error occurred when get role users: %v", err)
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return dto.NewApiResponse(ErrRoleNotFound, false)
-		}
 		return dto.NewApiResponse(ErrDataBaseError, false)
 	}
 
-	if len(userRoles) > 0 && !role.Force {
-		service.logger.Errorf("Role(ID: %d) has %d users", role.Id, len(userRoles))
+	if len(users) > 0 && !data.Force && replacement == nil {
+		service.logger.Errorf("Role(ID: %d) has %d users", role.ID, len(users))
 		return dto.NewApiResponse(ErrRoleHasUsers, false)
 	}
-
-	if len(userRoles) > 0 {
-		err = service.repo.DeleteRole(role.Id)
-	} else {
-		err = service.repo.Delete(&entity.Role{ID: role.Id})
+	// 删除有成员的角色会撤销所有成员的该角色, 与撤销角色时一样需要修改用户角色的权限,
+	// 操作者不能借此修改自己的角色, 且被删除角色与替代角色都不能超出操作者的有效权限
+	if len(users) > 0 {
+		if !perm.HasPermission(permission.UserEditRole) {
+			service.logger.Errorf("user %04d no permission to revoke role %d(%s) from its members", data.Cid, role.ID, role.Name)
+			return dto.NewApiResponse(dto.ErrNoPermission, false)
+		}
+		operatorPerm := Permission.Effective(operator)
+		if !operatorPerm.HasPermission(Permission.SuperAdmin) && slices.ContainsFunc(users, func(user *entity.User) bool { return user.ID == operator.ID }) {
+			service.logger.Errorf("user %04d try to edit own roles", data.Cid)
+			return dto.NewApiResponse(ErrEditSelfPermission, false)
+		}
+		roles := []*entity.Role{role}
+		if replacement != nil {
+			roles = append(roles, replacement)
+		}
+		if escalated := checkRoleEscalation(operatorPerm, roles); escalated != nil {
+			service.logger.Errorf("user %04d has no permission to operate role %d(%s)", data.Cid, escalated.ID, escalated.Name)
+			return dto.NewApiResponse(ErrPermissionEscalation, false)
+		}
 	}
+	profiles, err := getProfiles(service.profileRepo, users)
 	if err != nil {
+		service.logger.Errorf("error occurred when get profiles: %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	if err := service.repo.DeleteRole(role.ID, data.ReplacementId); err != nil {
 		service.logger.Errorf("error occurred when delete role: %v", err)
 		return dto.NewApiResponse(ErrDataBaseError, false)
 	}
 
-	go func(role *DTO.DeleteRole) {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	impacts := computeDeletionImpact(users, profiles, role, replacement)
+
+	go func(data *DTO.DeleteRole, operator *entity.User, role *entity.Role, replacement *entity.Role, users []*entity.User, impacts []*DTO.RoleDeletionImpactUser) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(len(users)+1)*5*time.Second)
 		defer cancel()
+		subject := fmt.Sprintf("%04d", data.Cid)
 		_, err := service.client.AuditLogClient().Log(ctx, &grpc.AuditLogRequest{
 			Event:     entity.AuditEventRoleDeleted.Value,
-			Subject:   fmt.Sprintf("%04d", role.Cid),
-			Object:    strconv.Itoa(int(role.Id)),
-			Ip:        role.Ip,
-			UserAgent: role.UserAgent,
+			Subject:   subject,
+			Object:    strconv.Itoa(int(role.ID)),
+			Ip:        data.Ip,
+			UserAgent: data.UserAgent,
+			OldValue:  fmt.Sprintf("%s(%s)", role.Name, role.Comment),
 		})
 		if err != nil {
 			service.logger.Errorf("error occurred when create audit log: %v", err)
 		}
-	}(role)
+
+		for index, user := range users {
+			object := fmt.Sprintf("%04d", user.Cid)
+			requests := []*grpc.AuditLogRequest{{
+				Event:    entity.AuditEventRoleRevoke.Value,
+				NewValue: role.Name,
+			}}
+			if len(impacts[index].LostPermissions) > 0 {
+				requests = append(requests, &grpc.AuditLogRequest{
+					Event:    Entity.AuditEventRolePermissionLost.Value,
+					OldValue: strings.Join(impacts[index].LostPermissions, ","),
+					NewValue: role.Name,
+				})
+			}
+			roleChange := "-" + role.Name
+			if replacement != nil && !slices.ContainsFunc(user.Roles, func(userRole *entity.UserRole) bool { return userRole.RoleId == replacement.ID }) {
+				requests = append(requests, &grpc.AuditLogRequest{
+					Event:    entity.AuditEventRoleGrant.Value,
+					NewValue: replacement.Name,
+				})
+				roleChange += "\n+" + replacement.Name
+			}
+			for _, request := range requests {
+				request.Subject = subject
+				request.Object = object
+				request.Ip = data.Ip
+				request.UserAgent = data.UserAgent
+				if _, err := service.client.AuditLogClient().Log(ctx, request); err != nil {
+					service.logger.Errorf("error occurred when create audit log: %v", err)
+				}
+			}
+			_, err = service.client.EmailClient().SendRoleChange(ctx, &grpc.RoleChange{
+				TargetEmail: []string{user.Email},
+				Cid:         object,
+				Roles:       roleChange,
+				Operator:    fmt.Sprintf("%04d", operator.Cid),
+				Contact:     operator.Email,
+			})
+			if err != nil {
+				service.logger.Errorf("send role change email failed: %v", err)
+			}
+		}
+	}(data, operator, role, replacement, users, impacts)
 
 	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
 }
+
+var (
+	ErrRoleTemplateVersion = dto.NewApiStatus("ROLE_TEMPLATE_VERSION", "不支持的角色模板版本", dto.HttpCodeBadRequest)
+)
+
+func newErrRoleTemplateInvalid(reason string) *dto.ApiStatus {
+	return dto.NewApiStatus("ROLE_TEMPLATE_INVALID", fmt.Sprintf("角色模板无效: %s", reason), dto.HttpCodeBadRequest)
+}
+
+func (service *RoleService) Export(data *DTO.ExportRoles) (*DTO.RoleTemplateFile, *dto.ApiResponse[bool]) {
+	perm := permission.Permission(data.Permission)
+	if !perm.HasPermission(permission.RoleShowList) {
+		service.logger.Errorf("user %04d no permission to export roles", data.Cid)
+		return nil, dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+	// 导出成员会列出所有拥有角色的用户呼号, 需要同时拥有查看用户列表的权限
+	if data.Members && !perm.HasPermission(permission.UserShowList) {
+		service.logger.Errorf("user %04d no permission to export role members", data.Cid)
+		return nil, dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
+
+	roles, err := service.repo.GetAll()
+	if err != nil {
+		service.logger.Errorf("error occurred when get all roles: %v", err)
+		return nil, dto.NewApiResponse(ErrDataBaseError, false)
+	}
+
+	members := make(map[uint][]uint)
+	if data.Members {
+		userRoles, err := service.repo.GetAllUserRoles()
+		if err != nil {
+			service.logger.Errorf("error occurred when get all role users: %v", err)
+			return nil, dto.NewApiResponse(ErrDataBaseError, false)
+		}
+		for _, userRole := range userRoles {
+			if userRole.User != nil {
+				members[userRole.RoleId] = append(members[userRole.RoleId], userRole.User.Cid)
+			}
+		}
+	}
+
+	file := &DTO.RoleTemplateFile{
+		Version:       DTO.RoleTemplateVersion,
+		ExportedAt:    time.Now().Format(time.RFC3339),
+		ManageMembers: data.Members,
+		Roles:         make([]*DTO.RoleTemplate, len(roles)),
+	}
+	utils.ForEach(roles, func(index int, role *entity.Role) {
+		names, unknown := Permission.Decode(role.Permission)
+		if unknown != 0 {
+			service.logger.Warnf("role %d(%s) has unknown permission bits %d, they will be exported as raw bits", role.ID, role.Name, unknown)
+		}
+		roleMembers := members[role.ID]
+		slices.Sort(roleMembers)
+		roleMembers = slices.Compact(roleMembers)
+		file.Roles[index] = &DTO.RoleTemplate{
+			Name:        role.Name,
+			Description: role.Comment,
+			Permissions: names,
+			UnknownBits: unknown,
+			Members:     roleMembers,
+		}
+	})
+	return file, nil
+}
+
+// roleImportPlan 导入时单个角色需要执行的变更
+type roleImportPlan struct {
+	upsert        *repository.RoleImport
+	created       bool
+	oldValue      string
+	infoChanged   bool
+	grant         []string
+	revoke        []string
+	addMembers    []*entity.User
+	removeMembers []*entity.User
+}
+
+func userCids(users []*entity.User) []string {
+	cids := make([]string, len(users))
+	utils.ForEach(users, func(index int, user *entity.User) {
+		cids[index] = fmt.Sprintf("%04d", user.Cid)
+	})
+	return cids
+}
+
+// Import 按角色名称将模板与现有角色对齐, 重复导入同一模板不会产生变更
+//
+// 不在模板中的角色仅当 Prune 为 true 时才会被删除并出现在 Removed 中, 否则只出现在 NotInTemplate 中
+func (service *RoleService) Import(data *DTO.ImportRoles) *dto.ApiResponse[*DTO.ImportRolesResponse] {
+	file := data.File
+	required := []permission.Permission{permission.RoleCreate, permission.RoleEdit, permission.RoleEditPermission}
+	if data.Prune {
+		required = append(required, permission.RoleDelete)
+	}
+	if file.ManageMembers {
+		required = append(required, permission.UserEditRole)
+	}
+	perm := permission.Permission(data.Permission)
+	for _, node := range required {
+		if !perm.HasPermission(node) {
+			service.logger.Errorf("user %04d no permission to import roles", data.Cid)
+			return dto.NewApiResponse[*DTO.ImportRolesResponse](dto.ErrNoPermission, nil)
+		}
+	}
+
+	if file.Version != DTO.RoleTemplateVersion {
+		service.logger.Errorf("unsupported role template version %d", file.Version)
+		return dto.NewApiResponse[*DTO.ImportRolesResponse](ErrRoleTemplateVersion, nil)
+	}
+
+	operator, err := service.userRepo.GetById(data.Uid)
+	if err != nil {
+		service.logger.Errorf("get operator failed: %v", err)
+		return dto.NewApiResponse[*DTO.ImportRolesResponse](ErrDataBaseError, nil)
+	}
+	operatorPerm := Permission.Effective(operator)
+	superAdmin := operatorPerm.HasPermission(Permission.SuperAdmin)
+	ownRole := func(roleId uint) bool {
+		for _, userRole := range operator.Roles {
+			if userRole.RoleId == roleId {
+				return true
+			}
+		}
+		return false
+	}
+
+	roles, err := service.repo.GetAll()
+	if err != nil {
+		service.logger.Errorf("error occurred when get all roles: %v", err)
+		return dto.NewApiResponse[*DTO.ImportRolesResponse](ErrDataBaseError, nil)
+	}
+	existing := make(map[string]*entity.Role, len(roles))
+	for _, role := range roles {
+		existing[role.Name] = role
+	}
+
+	existingMembers := make(map[uint][]*entity.User)
+	usersByCid := make(map[uint]*entity.User)
+	if file.ManageMembers {
+		userRoles, err := service.repo.GetAllUserRoles()
+		if err != nil {
+			service.logger.Errorf("error occurred when get all role users: %v", err)
+			return dto.NewApiResponse[*DTO.ImportRolesResponse](ErrDataBaseError, nil)
+		}
+		for _, userRole := range userRoles {
+			if userRole.User != nil {
+				existingMembers[userRole.RoleId] = append(existingMembers[userRole.RoleId], userRole.User)
+			}
+		}
+		cids := make([]uint, 0)
+		for _, template := range file.Roles {
+			cids = append(cids, template.Members...)
+		}
+		if len(cids) > 0 {
+			users, err := service.userRepo.GetByCids(cids)
+			if err != nil {
+				service.logger.Errorf("error occurred when get users by cid: %v", err)
+				return dto.NewApiResponse[*DTO.ImportRolesResponse](ErrDataBaseError, nil)
+			}
+			for _, user := range users {
+				usersByCid[user.Cid] = user
+			}
+		}
+	}
+
+	result := &DTO.ImportRolesResponse{
+		DryRun:        data.DryRun,
+		Created:       make([]*DTO.RoleDiff, 0),
+		Changed:       make([]*DTO.RoleDiff, 0),
+		Removed:       make([]*DTO.RoleDiff, 0),
+		Unchanged:     make([]string, 0),
+		NotInTemplate: make([]string, 0),
+	}
+	plans := make([]*roleImportPlan, 0, len(file.Roles))
+	seen := make(map[string]bool, len(file.Roles))
+
+	for _, template := range file.Roles {
+		name := strings.TrimSpace(template.Name)
+		if name == "" {
+			return dto.NewApiResponse[*DTO.ImportRolesResponse](newErrRoleTemplateInvalid("角色名称不能为空"), nil)
+		}
+		if seen[name] {
+			return dto.NewApiResponse[*DTO.ImportRolesResponse](newErrRoleTemplateInvalid(fmt.Sprintf("角色 %s 重复", name)), nil)
+		}
+		seen[name] = true
+
+		var value permission.Permission
+		for _, nodeName := range template.Permissions {
+			node, ok := Permission.GetNode(nodeName)
+			if !ok {
+				service.logger.Errorf("%s is not an valid permission node", nodeName)
+				return dto.NewApiResponse[*DTO.ImportRolesResponse](ErrPermissionNodeNotFound, nil)
+			}
+			value.Grant(node)
+		}
+		value |= permission.Permission(template.UnknownBits)
+		if !Permission.Contains(operatorPerm, value) {
+			service.logger.Errorf("user %04d has no permission to import role %s", data.Cid, name)
+			return dto.NewApiResponse[*DTO.ImportRolesResponse](ErrPermissionEscalation, nil)
+		}
+
+		members := make([]*entity.User, 0, len(template.Members))
+		if file.ManageMembers {
+			for _, cid := range template.Members {
+				user, ok := usersByCid[cid]
+				if !ok {
+					return dto.NewApiResponse[*DTO.ImportRolesResponse](newErrRoleTemplateInvalid(fmt.Sprintf("用户 %04d 不存在", cid)), nil)
+				}
+				// 模板中重复的成员只处理一次
+				if slices.ContainsFunc(members, func(u *entity.User) bool { return u.ID == user.ID }) {
+					continue
+				}
+				members = append(members, user)
+			}
+		}
+
+		plan := &roleImportPlan{upsert: &repository.RoleImport{}}
+		diff := &DTO.RoleDiff{Name: name, Changes: make([]string, 0)}
+		role, exists := existing[name]
+		if !exists {
+			plan.created = true
+			plan.upsert.Role = &entity.Role{Name: name, Comment: template.Description, Permission: uint64(value)}
+			var unknown uint64
+			plan.grant, unknown = Permission.Decode(uint64(value))
+			plan.addMembers = members
+			if template.Description != "" {
+				diff.Changes = append(diff.Changes, fmt.Sprintf("description: %s", template.Description))
+			}
+			if unknown != 0 {
+				diff.Changes = append(diff.Changes, fmt.Sprintf("+unknown bits %d", unknown))
+			}
+		} else {
+			if !Permission.Contains(operatorPerm, permission.Permission(role.Permission)) {
+				service.logger.Errorf("user %04d has no permission to modify role %d(%s)", data.Cid, role.ID, role.Name)
+				return dto.NewApiResponse[*DTO.ImportRolesResponse](ErrPermissionEscalation, nil)
+			}
+			plan.oldValue = fmt.Sprintf("%s(%s)", role.Name, role.Comment)
+			if role.Comment != template.Description {
+				plan.infoChanged = true
+				diff.Changes = append(diff.Changes, fmt.Sprintf("description: %s -> %s", role.Comment, template.Description))
+			}
+			var granted, revoked uint64
+			plan.grant, granted = Permission.Decode(uint64(value) &^ role.Permission)
+			plan.revoke, revoked = Permission.Decode(role.Permission &^ uint64(value))
+			if granted != 0 {
+				diff.Changes = append(diff.Changes, fmt.Sprintf("+unknown bits %d", granted))
+			}
+			if revoked != 0 {
+				diff.Changes = append(diff.Changes, fmt.Sprintf("-unknown bits %d", revoked))
+			}
+			if file.ManageMembers {
+				current := existingMembers[role.ID]
+				for _, user := range members {
+					if !slices.ContainsFunc(current, func(u *entity.User) bool { return u.ID == user.ID }) {
+						plan.addMembers = append(plan.addMembers, user)
+					}
+				}
+				for _, user := range current {
+					if !slices.ContainsFunc(members, func(u *entity.User) bool { return u.ID == user.ID }) {
+						plan.removeMembers = append(plan.removeMembers, user)
+					}
+				}
+			}
+			if !superAdmin && ownRole(role.ID) && uint64(value) != role.Permission {
+				service.logger.Errorf("user %04d try to edit permission of own role %d", data.Cid, role.ID)
+				return dto.NewApiResponse[*DTO.ImportRolesResponse](ErrEditSelfPermission, nil)
+			}
+			updated := *role
+			updated.Comment = template.Description
+			updated.Permission = uint64(value)
+			plan.upsert.Role = &updated
+		}
+		for _, node := range plan.grant {
+			diff.Changes = append(diff.Changes, "+"+node)
+		}
+		for _, node := range plan.revoke {
+			diff.Changes = append(diff.Changes, "-"+node)
+		}
+		for _, cid := range userCids(plan.addMembers) {
+			diff.Changes = append(diff.Changes, "+member "+cid)
+		}
+		for _, cid := range userCids(plan.removeMembers) {
+			diff.Changes = append(diff.Changes, "-member "+cid)
+		}
+		if !superAdmin && (slices.ContainsFunc(plan.addMembers, func(u *entity.User) bool { return u.ID == operator.ID }) ||
+			slices.ContainsFunc(plan.removeMembers, func(u *entity.User) bool { return u.ID == operator.ID })) {
+			service.logger.Errorf("user %04d try to edit own roles", data.Cid)
+			return dto.NewApiResponse[*DTO.ImportRolesResponse](ErrEditSelfPermission, nil)
+		}
+
+		if plan.created {
+			result.Created = append(result.Created, diff)
+		} else if len(diff.Changes) > 0 {
+			result.Changed = append(result.Changed, diff)
+		} else {
+			result.Unchanged = append(result.Unchanged, name)
+			continue
+		}
+		if file.ManageMembers {
+			plan.upsert.Members = make([]uint, len(members))
+			utils.ForEach(members, func(index int, user *entity.User) {
+				plan.upsert.Members[index] = user.ID
+			})
+		}
+		plans = append(plans, plan)
+	}
+
+	removed := make([]*entity.Role, 0)
+	for _, role := range roles {
+		if seen[role.Name] {
+			continue
+		}
+		if !data.Prune {
+			result.NotInTemplate = append(result.NotInTemplate, role.Name)
+			continue
+		}
+		if !Permission.Contains(operatorPerm, permission.Permission(role.Permission)) {
+			service.logger.Errorf("user %04d has no permission to delete role %d(%s)", data.Cid, role.ID, role.Name)
+			return dto.NewApiResponse[*DTO.ImportRolesResponse](ErrPermissionEscalation, nil)
+		}
+		if !superAdmin && ownRole(role.ID) {
+			service.logger.Errorf("user %04d try to delete own role %d", data.Cid, role.ID)
+			return dto.NewApiResponse[*DTO.ImportRolesResponse](ErrEditSelfPermission, nil)
+		}
+		removed = append(removed, role)
+		result.Removed = append(result.Removed, &DTO.RoleDiff{Name: role.Name, Changes: make([]string, 0)})
+	}
+
+	if data.DryRun {
+		return dto.NewApiResponse(dto.SuccessHandleRequest, result)
+	}
+
+	removeIds := make([]uint, 0, len(removed))
+	utils.ForEach(removed, func(index int, role *entity.Role) {
+		removeIds = append(removeIds, role.ID)
+	})
+	if len(plans) == 0 && len(removeIds) == 0 {
+		return dto.NewApiResponse(dto.SuccessHandleRequest, result)
+	}
+
+	upserts := make([]*repository.RoleImport, len(plans))
+	utils.ForEach(plans, func(index int, plan *roleImportPlan) {
+		upserts[index] = plan.upsert
+	})
+	if err := service.repo.ImportRoles(upserts, removeIds); err != nil {
+		service.logger.Errorf("error occurred when import roles: %v", err)
+		return dto.NewApiResponse[*DTO.ImportRolesResponse](ErrDataBaseError, nil)
+	}
+
+	go func(data *DTO.ImportRoles, plans []*roleImportPlan, removed []*entity.Role, result *DTO.ImportRolesResponse) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(len(plans)+len(removed)+1)*5*time.Second)
+		defer cancel()
+		subject := fmt.Sprintf("%04d", data.Cid)
+		requests := make([]*grpc.AuditLogRequest, 0)
+		for _, plan := range plans {
+			role := plan.upsert.Role
+			object := fmt.Sprintf("%d(%s)", role.ID, role.Name)
+			if plan.created {
+				requests = append(requests, &grpc.AuditLogRequest{
+					Event:    entity.AuditEventRoleCreated.Value,
+					Object:   strconv.Itoa(int(role.ID)),
+					NewValue: fmt.Sprintf("%s(%s)", role.Name, role.Comment),
+				})
+			} else if plan.infoChanged {
+				requests = append(requests, &grpc.AuditLogRequest{
+					Event:    entity.AuditEventRoleUpdated.Value,
+					Object:   strconv.Itoa(int(role.ID)),
+					OldValue: plan.oldValue,
+					NewValue: fmt.Sprintf("%s(%s)", role.Name, role.Comment),
+				})
+			}
+			if len(plan.grant) > 0 {
+				requests = append(requests, &grpc.AuditLogRequest{
+					Event:    entity.AuditEventRolePermissionGrant.Value,
+					Object:   object,
+					NewValue: strings.Join(plan.grant, ","),
+				})
+			}
+			if len(plan.revoke) > 0 {
+				requests = append(requests, &grpc.AuditLogRequest{
+					Event:    entity.AuditEventRolePermissionRevoke.Value,
+					Object:   object,
+					NewValue: strings.Join(plan.revoke, ","),
+				})
+			}
+			if len(plan.addMembers) > 0 {
+				requests = append(requests, &grpc.AuditLogRequest{
+					Event:    entity.AuditEventRoleGrant.Value,
+					Object:   object,
+					NewValue: strings.Join(userCids(plan.addMembers), ","),
+				})
+			}
+			if len(plan.removeMembers) > 0 {
+				requests = append(requests, &grpc.AuditLogRequest{
+					Event:    entity.AuditEventRoleRevoke.Value,
+					Object:   object,
+					NewValue: strings.Join(userCids(plan.removeMembers), ","),
+				})
+			}
+		}
+		for _, role := range removed {
+			requests = append(requests, &grpc.AuditLogRequest{
+				Event:    entity.AuditEventRoleDeleted.Value,
+				Object:   strconv.Itoa(int(role.ID)),
+				OldValue: fmt.Sprintf("%s(%s)", role.Name, role.Comment),
+			})
+		}
+		requests = append(requests, &grpc.AuditLogRequest{
+			Event:    Entity.AuditEventRoleImported.Value,
+			Object:   "roles",
+			NewValue: fmt.Sprintf("created=%d,changed=%d,removed=%d", len(result.Created), len(result.Changed), len(removed)),
+		})
+		for _, request := range requests {
+			request.Subject = subject
+			request.Ip = data.Ip
+			request.UserAgent = data.UserAgent
+			if _, err := service.client.AuditLogClient().Log(ctx, request); err != nil {
+				service.logger.Errorf("error occurred when create audit log: %v", err)
+			}
+		}
+	}(data, plans, removed, result)
+
+	return dto.NewApiResponse(dto.SuccessHandleRequest, result)
+}
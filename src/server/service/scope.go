@@ -0,0 +1,132 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"slices"
+	"user-service/src/interfaces/repository"
+
+	"half-nothing.cn/service-core/interfaces/database/entity"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/logger"
+	"half-nothing.cn/service-core/permission"
+)
+
+var (
+	ErrOutOfScope = dto.NewApiStatus("USER_OUT_OF_SCOPE", "目标用户不在您的管辖范围内", dto.HttpCodePermissionDenied)
+)
+
+// Scope 操作者持有某个权限节点的范围
+type Scope struct {
+	Global      bool
+	DivisionIds []uint
+}
+
+func (s *Scope) Empty() bool {
+	return !s.Global && len(s.DivisionIds) == 0
+}
+
+// Contains 判断范围是否与给定的分区有交集
+func (s *Scope) Contains(divisionIds []uint) bool {
+	if s.Global {
+		return true
+	}
+	for _, divisionId := range divisionIds {
+		if slices.Contains(s.DivisionIds, divisionId) {
+			return true
+		}
+	}
+	return false
+}
+
+type scopeResolver struct {
+	logger       logger.Interface
+	divisionRepo repository.DivisionInterface
+}
+
+func newScopeResolver(lg logger.Interface, divisionRepo repository.DivisionInterface) *scopeResolver {
+	return &scopeResolver{
+		logger:       lg,
+		divisionRepo: divisionRepo,
+	}
+}
+
+// resolve 计算操作者持有权限节点的范围
+//
+// 全局权限(用户权限与全局角色)已包含该节点时视为全局范围, 否则为持有该节点的分区角色所在分区
+func (r *scopeResolver) resolve(uid uint, globalPerm uint64, node permission.Permission) (*Scope, error) {
+	perm := permission.Permission(globalPerm)
+	if perm.HasPermission(node) {
+		return &Scope{Global: true}, nil
+	}
+	divisionRoles, err := r.divisionRepo.GetScopedRoles(uid)
+	if err != nil {
+		return nil, err
+	}
+	scope := &Scope{DivisionIds: make([]uint, 0)}
+	for _, divisionRole := range divisionRoles {
+		if divisionRole.Role == nil || slices.Contains(scope.DivisionIds, divisionRole.DivisionId) {
+			continue
+		}
+		rolePerm := permission.Permission(divisionRole.Role.Permission)
+		if rolePerm.HasPermission(node) {
+			scope.DivisionIds = append(scope.DivisionIds, divisionRole.DivisionId)
+		}
+	}
+	return scope, nil
+}
+
+// effectiveIn 计算用户在某个分区内的有效权限, 即全局权限与该分区内角色权限的并集
+func (r *scopeResolver) effectiveIn(user *entity.User, globalPerm permission.Permission, divisionId uint) (permission.Permission, error) {
+	divisionRoles, err := r.divisionRepo.GetScopedRoles(user.ID)
+	if err != nil {
+		return globalPerm, err
+	}
+	perm := globalPerm
+	for _, divisionRole := range divisionRoles {
+		if divisionRole.DivisionId == divisionId && divisionRole.Role != nil {
+			perm.Merge(permission.Permission(divisionRole.Role.Permission))
+		}
+	}
+	return perm, nil
+}
+
+// containsUser 判断范围是否包含目标用户所在的任意分区
+func (r *scopeResolver) containsUser(scope *Scope, userId uint) (bool, error) {
+	if scope.Global {
+		return true, nil
+	}
+	divisionIds, err := r.divisionRepo.GetUserDivisionIds(userId)
+	if err != nil {
+		return false, err
+	}
+	return scope.Contains(divisionIds), nil
+}
+
+func checkScope[T comparable](resolver *scopeResolver, uid uint, globalPerm uint64, node permission.Permission) (*Scope, *dto.ApiResponse[T]) {
+	var zero T
+	scope, err := resolver.resolve(uid, globalPerm, node)
+	if err != nil {
+		resolver.logger.Errorf("error occurred when resolve permission scope: %v", err)
+		return nil, dto.NewApiResponse(ErrDataBaseError, zero)
+	}
+	if scope.Empty() {
+		return nil, dto.NewApiResponse(dto.ErrNoPermission, zero)
+	}
+	return scope, nil
+}
+
+func checkUserInScope[T comparable](resolver *scopeResolver, scope *Scope, userId uint) *dto.ApiResponse[T] {
+	var zero T
+	ok, err := resolver.containsUser(scope, userId)
+	if err != nil {
+		resolver.logger.Errorf("error occurred when check user scope: %v", err)
+		return dto.NewApiResponse(ErrDataBaseError, zero)
+	}
+	if !ok {
+		return dto.NewApiResponse(ErrOutOfScope, zero)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"time"
+	"user-service/src/interfaces/repository"
+	DTO "user-service/src/interfaces/server/dto"
+
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/logger"
+)
+
+// SessionService 校验访问令牌所属的会话
+//
+// 访问令牌本身无状态, 会话失效后在有效期内仍可通过签名校验, 因此每次请求都需要检查签发时间.
+// 管理员要求修改密码后, 用户修改密码前只能访问修改密码接口
+type SessionService struct {
+	logger logger.Interface
+	repo   repository.SessionInterface
+}
+
+func NewSessionService(
+	lg logger.Interface,
+	repo repository.SessionInterface,
+) *SessionService {
+	return &SessionService{
+		logger: logger.NewLoggerAdapter(lg, "session-service"),
+		repo:   repo,
+	}
+}
+
+var (
+	ErrPasswordChangeRequired = dto.NewApiStatus("PASSWORD_CHANGE_REQUIRED", "管理员要求您修改密码, 请先修改密码", dto.HttpCodePermissionDenied)
+)
+
+func (service *SessionService) CheckSession(data *DTO.CheckSession) *dto.ApiStatus {
+	revokedAt, err := service.repo.GetRevokedAt(data.Uid)
+	if err != nil {
+		service.logger.Errorf("CheckSession handle fail, get session revocation err, %v", err)
+		return dto.ErrServerError
+	}
+	// JWT 的签发时间只精确到秒
+	if data.Raw.IssuedAt != nil && data.Raw.IssuedAt.Before(revokedAt.Truncate(time.Second)) {
+		return ErrSessionRevoked
+	}
+	if data.PasswordChange {
+		return nil
+	}
+	required, err := service.repo.IsPasswordChangeRequired(data.Uid)
+	if err != nil {
+		service.logger.Errorf("CheckSession handle fail, get password change requirement err, %v", err)
+		return dto.ErrServerError
+	}
+	if required {
+		return ErrPasswordChangeRequired
+	}
+	return nil
+}
@@ -10,11 +10,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"strings"
 	"time"
+	c "user-service/src/interfaces/config"
 	"user-service/src/interfaces/content"
+	Entity "user-service/src/interfaces/database/entity"
 	"user-service/src/interfaces/global"
 	"user-service/src/interfaces/grpc"
 	pb "user-service/src/interfaces/grpc"
+	Permission "user-service/src/interfaces/permission"
 	"user-service/src/interfaces/repository"
 	DTO "user-service/src/interfaces/server/dto"
 
@@ -27,20 +32,67 @@ import (
 )
 
 type UserService struct {
-	logger logger.Interface
-	repo   repository.UserInterface
-	client *content.GrpcClientManager
+	logger         logger.Interface
+	deletionConfig *c.DeletionConfig
+	cidConfig      *c.CidConfig
+	availability   *c.AvailabilityConfig
+	emailConfig    *c.EmailConfig
+	repo           repository.UserInterface
+	divisionRepo   repository.DivisionInterface
+	deletionRepo   repository.DeletionInterface
+	profileRepo    repository.ProfileInterface
+	fieldRepo      repository.ProfileFieldInterface
+	cidRepo        repository.CidInterface
+	sessionRepo    repository.SessionInterface
+	scope          *scopeResolver
+	limiter        *rateLimiter
+	challenge      *ChallengeService
+	emailChange    *EmailChangeService
+	username       *UsernameService
+	avatar         *AvatarService
+	client         *content.GrpcClientManager
 }
 
 func NewUserService(
 	lg logger.Interface,
+	deletionConfig *c.DeletionConfig,
+	cidConfig *c.CidConfig,
+	availability *c.AvailabilityConfig,
+	emailConfig *c.EmailConfig,
 	repo repository.UserInterface,
+	divisionRepo repository.DivisionInterface,
+	deletionRepo repository.DeletionInterface,
+	profileRepo repository.ProfileInterface,
+	fieldRepo repository.ProfileFieldInterface,
+	cidRepo repository.CidInterface,
+	sessionRepo repository.SessionInterface,
+	challenge *ChallengeService,
+	emailChange *EmailChangeService,
+	username *UsernameService,
+	avatar *AvatarService,
 	client *content.GrpcClientManager,
 ) *UserService {
+	adapter := logger.NewLoggerAdapter(lg, "user-service")
 	return &UserService{
-		logger: logger.NewLoggerAdapter(lg, "user-service"),
-		repo:   repo,
-		client: client,
+		logger:         adapter,
+		deletionConfig: deletionConfig,
+		cidConfig:      cidConfig,
+		availability:   availability,
+		emailConfig:    emailConfig,
+		repo:           repo,
+		divisionRepo:   divisionRepo,
+		deletionRepo:   deletionRepo,
+		profileRepo:    profileRepo,
+		fieldRepo:      fieldRepo,
+		cidRepo:        cidRepo,
+		sessionRepo:    sessionRepo,
+		scope:          newScopeResolver(adapter, divisionRepo),
+		limiter:        newRateLimiter(availability.RateLimit, availability.RateWindowDuration),
+		challenge:      challenge,
+		emailChange:    emailChange,
+		username:       username,
+		avatar:         avatar,
+		client:         client,
 	}
 }
 
@@ -90,31 +142,131 @@ func (u *UserService) removeEmailCode(ctx context.Context, email string) {
 	}
 }
 
-func (u *UserService) Register(form *DTO.UserRegister) *dto.ApiResponse[bool] {
-	if exist, err := u.repo.CheckCidUsernameAndEmail(uint(form.Cid), form.Username, form.Email); !exist {
-		u.logger.Errorf("error occurred when check cid username and email: %v", err)
-		return dto.NewApiResponse(ErrRegistered, false)
+var (
+	ErrCidExhausted  = dto.NewApiStatus("CID_EXHAUSTED", "暂无可分配的呼号, 请联系管理员", dto.HttpCodeInternalError)
+	ErrUsernameTaken = dto.NewApiStatus("USERNAME_TAKEN", "用户名已被注册", dto.HttpCodeConflict)
+	ErrEmailTaken    = dto.NewApiStatus("EMAIL_TAKEN", "邮箱已被注册", dto.HttpCodeConflict)
+)
+
+// userConflictStatus 将重复的字段转换为对应的错误状态
+func userConflictStatus(conflict repository.UserConflict) *dto.ApiStatus {
+	switch conflict {
+	case repository.UserConflictCid:
+		return ErrCidTaken
+	case repository.UserConflictUsername:
+		return ErrUsernameTaken
+	case repository.UserConflictEmail:
+		return ErrEmailTaken
+	default:
+		return ErrRegistered
 	}
+}
 
-	if res := verifyEmailCode[bool](u, form.Email, form.Code); res != nil {
+// checkUserConflict 检查呼号、用户名或邮箱是否已被使用
+func checkUserConflict(lg logger.Interface, repo repository.UserInterface, cid uint, username string, email string) *dto.ApiStatus {
+	conflict, err := repo.FindConflict(cid, username, email)
+	if err != nil {
+		lg.Errorf("error occurred when check user conflict: %v", err)
+		return ErrDataBaseError
+	}
+	if conflict != repository.UserConflictNone {
+		return userConflictStatus(conflict)
+	}
+	return nil
+}
+
+var (
+	ErrEmailInvalid    = dto.NewApiStatus("EMAIL_INVALID", "邮箱格式错误", dto.HttpCodeBadRequest)
+	ErrEmailDisposable = dto.NewApiStatus("EMAIL_DISPOSABLE", "不支持使用一次性邮箱", dto.HttpCodeBadRequest)
+)
+
+// parseEmail 校验邮箱地址, 返回解析后的邮箱与用于判断唯一性的规范化邮箱
+func parseEmail(config *c.EmailConfig, email string) (string, string, *dto.ApiStatus) {
+	parsed, err := config.Parse(email)
+	if err != nil {
+		if errors.Is(err, c.ErrEmailDisposable) {
+			return "", "", ErrEmailDisposable
+		}
+		return "", "", ErrEmailInvalid
+	}
+	return parsed, config.Canonical(parsed), nil
+}
+
+// checkEmailUsed 检查规范化后的邮箱是否已被 userId 以外的用户使用, 注册时 userId 为 0
+func checkEmailUsed(lg logger.Interface, repo repository.UserInterface, canonical string, userId uint) *dto.ApiStatus {
+	used, err := repo.IsEmailUsed(canonical, userId)
+	if err != nil {
+		lg.Errorf("error occurred when check canonical email: %v", err)
+		return ErrDataBaseError
+	}
+	if used {
+		return ErrEmailTaken
+	}
+	return nil
+}
+
+// Register 注册用户
+//
+// 注册前的重复检查只用于提前给出提示, 并发注册时由数据库唯一索引保证唯一性
+func (u *UserService) Register(form *DTO.UserRegister) *dto.ApiResponse[*DTO.RegisterResult] {
+	if status := u.challenge.check(c.ChallengeEndpointRegister, form.Ip, form.Challenge); status != nil {
+		return dto.NewApiResponse[*DTO.RegisterResult](status, nil)
+	}
+
+	email, canonicalEmail, status := parseEmail(u.emailConfig, form.Email)
+	if status != nil {
+		return dto.NewApiResponse[*DTO.RegisterResult](status, nil)
+	}
+	form.Email = email
+
+	if status := checkUserConflict(u.logger, u.repo, 0, form.Username, form.Email); status != nil {
+		if status != ErrDataBaseError {
+			u.challenge.fail(c.ChallengeEndpointRegister, form.Ip)
+		}
+		return dto.NewApiResponse[*DTO.RegisterResult](status, nil)
+	}
+	if status := checkEmailUsed(u.logger, u.repo, canonicalEmail, 0); status != nil {
+		if status != ErrDataBaseError {
+			u.challenge.fail(c.ChallengeEndpointRegister, form.Ip)
+		}
+		return dto.NewApiResponse[*DTO.RegisterResult](status, nil)
+	}
+	if status := u.username.check(0, form.Username); status != nil {
+		return dto.NewApiResponse[*DTO.RegisterResult](status, nil)
+	}
+
+	if res := verifyEmailCode[*DTO.RegisterResult](u, form.Email, form.Code); res != nil {
+		u.challenge.fail(c.ChallengeEndpointRegister, form.Ip)
 		return res
 	}
 
 	hashedPassword, err := utils.BcryptEncrypt([]byte(form.Password), *global.BcryptCost)
 	if err != nil {
 		u.logger.Errorf("error occurred when encrypt password: %v", err)
-		return dto.NewApiResponse(ErrPasswordEncrypt, false)
+		return dto.NewApiResponse[*DTO.RegisterResult](ErrPasswordEncrypt, nil)
 	}
 
 	user := &entity.User{
 		Username: form.Username,
 		Email:    form.Email,
-		Cid:      uint(form.Cid),
 		Password: string(hashedPassword),
 	}
-	if err := u.repo.Save(user); err != nil {
+	if err := u.cidRepo.CreateUser(user, canonicalEmail, u.cidConfig.Min, u.cidConfig.Max, u.cidConfig.Allocatable); err != nil {
 		u.logger.Errorf("error occurred when save user: %v", err)
-		return dto.NewApiResponse(ErrDataBaseError, false)
+		if errors.Is(err, repository.ErrCidExhausted) {
+			return dto.NewApiResponse[*DTO.RegisterResult](ErrCidExhausted, nil)
+		}
+		if errors.Is(err, repository.ErrDuplicated) {
+			status := checkUserConflict(u.logger, u.repo, 0, form.Username, form.Email)
+			if status == nil {
+				status = checkEmailUsed(u.logger, u.repo, canonicalEmail, 0)
+			}
+			if status == nil {
+				status = ErrRegistered
+			}
+			return dto.NewApiResponse[*DTO.RegisterResult](status, nil)
+		}
+		return dto.NewApiResponse[*DTO.RegisterResult](ErrDataBaseError, nil)
 	}
 
 	go func(u *UserService, form *DTO.UserRegister, user *entity.User) {
@@ -122,14 +274,14 @@ func (u *UserService) Register(form *DTO.UserRegister) *dto.ApiResponse[bool] {
 		defer cancel()
 		_, err := u.client.EmailClient().SendWelcome(ctx, &pb.Welcome{
 			TargetEmail: form.Email,
-			Cid:         fmt.Sprintf("%04d", form.Cid),
+			Cid:         fmt.Sprintf("%04d", user.Cid),
 		})
 		if err != nil {
 			u.logger.Errorf("error occurred when send welcome email: %v", err)
 		}
 		_, err = u.client.AuditLogClient().Log(ctx, &pb.AuditLogRequest{
 			Event:     entity.AuditEventUserRegistered.Value,
-			Subject:   fmt.Sprintf("%04d", form.Cid),
+			Subject:   fmt.Sprintf("%04d", user.Cid),
 			Object:    fmt.Sprintf("%s(%s)", user.Username, user.Email),
 			Ip:        form.Ip,
 			UserAgent: form.UserAgent,
@@ -140,15 +292,67 @@ func (u *UserService) Register(form *DTO.UserRegister) *dto.ApiResponse[bool] {
 		u.removeEmailCode(ctx, form.Email)
 	}(u, form, user)
 
-	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
+	return dto.NewApiResponse(dto.SuccessHandleRequest, &DTO.RegisterResult{Cid: user.Cid})
 }
 
-func (u *UserService) CheckAvailability(form *DTO.UserCheckAvailability) *dto.ApiResponse[bool] {
-	exist, err := u.repo.CheckCidUsernameAndEmail(uint(form.Cid), form.Username, form.Email)
+// CheckAvailability 分别检查用户名与邮箱是否可用, 呼号由服务端分配因此不提供检查
+//
+// 接口按IP限流, 并可要求人机验证, 防止被用于批量探测已注册的邮箱, 查询到已被使用的值计为一次失败
+func (u *UserService) CheckAvailability(form *DTO.UserCheckAvailability) *dto.ApiResponse[*DTO.AvailabilityResult] {
+	if !u.limiter.allow(form.Ip) {
+		return dto.NewApiResponse[*DTO.AvailabilityResult](ErrTooManyRequests, nil)
+	}
+	if status := u.challenge.check(c.ChallengeEndpointAvailability, form.Ip, form.Challenge); status != nil {
+		return dto.NewApiResponse[*DTO.AvailabilityResult](status, nil)
+	}
+
+	result := &DTO.AvailabilityResult{}
+	check := func(username string, email string) (*bool, error) {
+		conflict, err := u.repo.FindConflict(0, username, email)
+		if err != nil {
+			return nil, err
+		}
+		available := conflict == repository.UserConflictNone
+		return &available, nil
+	}
+	var err error
+	if form.Username != "" {
+		result.Username, err = check(form.Username, "")
+		// 不符合用户名策略的用户名同样不可用
+		if err == nil && *result.Username {
+			status := u.username.check(0, form.Username)
+			if status == ErrDataBaseError {
+				err = errors.New("check username policy fail")
+			}
+			available := status == nil
+			result.Username = &available
+		}
+	}
+	if err == nil && form.Email != "" {
+		// 格式错误或不支持的邮箱只标记为不可用, 不影响其他字段的结果
+		email, canonicalEmail, status := parseEmail(u.emailConfig, form.Email)
+		if status != nil {
+			available := false
+			result.Email = &available
+		} else {
+			result.Email, err = check("", email)
+			// 按服务商规则规范化后与其他用户相同的邮箱同样不可用
+			if err == nil && *result.Email {
+				var used bool
+				used, err = u.repo.IsEmailUsed(canonicalEmail, 0)
+				available := !used
+				result.Email = &available
+			}
+		}
+	}
 	if err != nil {
-		u.logger.Errorf("error occurred when check cid username and email: %v", err)
+		u.logger.Errorf("error occurred when check availability: %v", err)
+		return dto.NewApiResponse[*DTO.AvailabilityResult](ErrDataBaseError, nil)
+	}
+	if (result.Username != nil && !*result.Username) || (result.Email != nil && !*result.Email) {
+		u.challenge.fail(c.ChallengeEndpointAvailability, form.Ip)
 	}
-	return dto.NewApiResponse(dto.SuccessHandleRequest, exist)
+	return dto.NewApiResponse(dto.SuccessHandleRequest, result)
 }
 
 var (
@@ -156,7 +360,12 @@ var (
 )
 
 func (u *UserService) ResetPassword(form *DTO.UserResetPassword) *dto.ApiResponse[bool] {
+	if status := u.challenge.check(c.ChallengeEndpointResetPassword, form.Ip, form.Challenge); status != nil {
+		return dto.NewApiResponse(status, false)
+	}
+
 	if res := verifyEmailCode[bool](u, form.Email, form.Code); res != nil {
+		u.challenge.fail(c.ChallengeEndpointResetPassword, form.Ip)
 		return res
 	}
 
@@ -164,6 +373,7 @@ func (u *UserService) ResetPassword(form *DTO.UserResetPassword) *dto.ApiRespons
 	if err != nil {
 		u.logger.Errorf("ResetPassword handle fail, get user err, %v", err)
 		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.challenge.fail(c.ChallengeEndpointResetPassword, form.Ip)
 			return dto.NewApiResponse(ErrUserNotExist, false)
 		}
 		return dto.NewApiResponse(ErrDataBaseError, false)
@@ -183,6 +393,7 @@ func (u *UserService) ResetPassword(form *DTO.UserResetPassword) *dto.ApiRespons
 		u.logger.Errorf("ResetPassword handle fail, save user err, %v", err)
 		return dto.NewApiResponse(ErrDataBaseError, false)
 	}
+	u.clearPasswordChange(user.ID)
 
 	go func(u *UserService, form *DTO.UserResetPassword, user *entity.User) {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -214,20 +425,53 @@ func (u *UserService) ResetPassword(form *DTO.UserResetPassword) *dto.ApiRespons
 }
 
 func (u *UserService) GetPages(page *DTO.GetUserPage) *dto.ApiResponse[*DTO.GetUserPageResponse] {
-	perm := permission.Permission(page.Permission)
-	if !perm.HasPermission(permission.UserShowList) {
+	scope, res := checkScope[*DTO.GetUserPageResponse](u.scope, page.Uid, page.Permission, permission.UserShowList)
+	if res != nil {
 		u.logger.Errorf("user %04d no permission to show user list", page.Cid)
-		return dto.NewApiResponse[*DTO.GetUserPageResponse](dto.ErrNoPermission, nil)
+		return res
+	}
+	// 没有全局权限时只能查看自己管辖分区内的用户
+	var divisionIds []uint
+	if !scope.Global {
+		divisionIds = scope.DivisionIds
+	}
+	if page.DivisionId > 0 {
+		if !scope.Contains([]uint{page.DivisionId}) {
+			u.logger.Errorf("user %04d no permission to show user list of division %d", page.Cid, page.DivisionId)
+			return dto.NewApiResponse[*DTO.GetUserPageResponse](ErrOutOfScope, nil)
+		}
+		divisionIds = []uint{page.DivisionId}
+	}
+	var fieldFilter *repository.FieldFilter
+	if page.Field != "" {
+		field, err := u.fieldRepo.GetByKey(page.Field)
+		if err != nil {
+			u.logger.Errorf("GetPages handle fail, get profile field err, %v", err)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return dto.NewApiResponse[*DTO.GetUserPageResponse](ErrFieldNotFound, nil)
+			}
+			return dto.NewApiResponse[*DTO.GetUserPageResponse](ErrDataBaseError, nil)
+		}
+		fieldFilter = &repository.FieldFilter{FieldId: field.ID, Value: page.FieldValue}
 	}
-	users, total, err := u.repo.GetPages(page.PageNum, page.PageSize, page.Search)
+	users, total, err := u.repo.GetPages(page.PageNum, page.PageSize, page.Search, divisionIds, fieldFilter)
 	if err != nil {
 		u.logger.Errorf("error occurred when get pages: %v", err)
 		return dto.NewApiResponse[*DTO.GetUserPageResponse](ErrDataBaseError, nil)
 	}
+	userIds := make([]uint, len(users))
+	utils.ForEach(users, func(index int, element *entity.User) {
+		userIds[index] = element.ID
+	})
+	profiles, err := u.profileRepo.GetByUserIds(userIds)
+	if err != nil {
+		u.logger.Errorf("error occurred when get profiles: %v", err)
+		return dto.NewApiResponse[*DTO.GetUserPageResponse](ErrDataBaseError, nil)
+	}
 	userInfos := make([]*DTO.FullUserInfo, len(users))
 	utils.ForEach(users, func(index int, element *entity.User) {
 		userInfos[index] = &DTO.FullUserInfo{}
-		userInfos[index].FromUserEntity(element)
+		userInfos[index].FromUserEntity(element, profiles[element.ID])
 	})
 	return dto.NewApiResponse(dto.SuccessHandleRequest, &DTO.GetUserPageResponse{
 		Data:     userInfos,
@@ -250,16 +494,21 @@ func (u *UserService) GetSelfData(data *DTO.GetCurrentUserData) *dto.ApiResponse
 		}
 		return dto.NewApiResponse[*DTO.UserInfo](ErrDataBaseError, nil)
 	}
+	profile, err := getProfile(u.profileRepo, user.ID)
+	if err != nil {
+		u.logger.Errorf("GetSelfData handle fail, get profile err, %v", err)
+		return dto.NewApiResponse[*DTO.UserInfo](ErrDataBaseError, nil)
+	}
 	userInfo := &DTO.UserInfo{}
-	userInfo.FromUserEntity(user)
+	userInfo.FromUserEntity(user, profile)
 	return dto.NewApiResponse(dto.SuccessHandleRequest, userInfo)
 }
 
 func (u *UserService) GetData(data *DTO.GetUserData) *dto.ApiResponse[*DTO.FullUserInfo] {
-	perm := permission.Permission(data.Permission)
-	if !perm.HasPermission(permission.UserShowList) {
+	scope, res := checkScope[*DTO.FullUserInfo](u.scope, data.Uid, data.Permission, permission.UserShowList)
+	if res != nil {
 		u.logger.Errorf("user %04d no permission to get user data", data.Cid)
-		return dto.NewApiResponse[*DTO.FullUserInfo](dto.ErrNoPermission, nil)
+		return res
 	}
 	user, err := u.repo.GetById(data.Id)
 	if err != nil {
@@ -269,8 +518,17 @@ func (u *UserService) GetData(data *DTO.GetUserData) *dto.ApiResponse[*DTO.FullU
 		}
 		return dto.NewApiResponse[*DTO.FullUserInfo](ErrDataBaseError, nil)
 	}
+	if res := checkUserInScope[*DTO.FullUserInfo](u.scope, scope, user.ID); res != nil {
+		u.logger.Errorf("user %04d no permission to get data of user %04d", data.Cid, user.Cid)
+		return res
+	}
+	profile, err := getProfile(u.profileRepo, user.ID)
+	if err != nil {
+		u.logger.Errorf("GetData handle fail, get profile err, %v", err)
+		return dto.NewApiResponse[*DTO.FullUserInfo](ErrDataBaseError, nil)
+	}
 	userInfo := &DTO.FullUserInfo{}
-	userInfo.FromUserEntity(user)
+	userInfo.FromUserEntity(user, profile)
 	return dto.NewApiResponse(dto.SuccessHandleRequest, userInfo)
 }
 
@@ -283,35 +541,103 @@ func (u *UserService) UpdateSelfData(data *DTO.UpdateCurrentUserData) *dto.ApiRe
 		}
 		return dto.NewApiResponse[*DTO.UserInfo](ErrDataBaseError, nil)
 	}
+	profile, err := getProfile(u.profileRepo, user.ID)
+	if err != nil {
+		u.logger.Errorf("UpdateSelfData handle fail, get profile err, %v", err)
+		return dto.NewApiResponse[*DTO.UserInfo](ErrDataBaseError, nil)
+	}
 
-	var oldEmail string
+	// 只改变大小写时不会与其他用户冲突, 无需检查
+	var checkUsername, checkEmail string
+	oldValue := map[string]interface{}{}
 	updates := map[string]interface{}{}
 	if data.Username != "" && user.Username != data.Username {
+		if !strings.EqualFold(user.Username, data.Username) {
+			checkUsername = data.Username
+		}
+		oldValue["username"] = user.Username
 		updates["username"] = data.Username
 	}
-	if data.Email != "" && user.Email != data.Email {
-		if res := verifyEmailCode[*DTO.UserInfo](u, data.Email, data.EmailCode); res != nil {
-			return res
+	// 邮箱不会立即修改, 而是创建需要新邮箱确认的修改请求
+	var canonicalEmail string
+	if data.Email != "" {
+		email, canonical, status := parseEmail(u.emailConfig, data.Email)
+		if status != nil {
+			return dto.NewApiResponse[*DTO.UserInfo](status, nil)
+		}
+		data.Email, canonicalEmail = email, canonical
+	}
+	changeEmail := data.Email != "" && user.Email != data.Email
+	if changeEmail && !strings.EqualFold(user.Email, data.Email) {
+		checkEmail = data.Email
+	}
+	if checkUsername != "" || checkEmail != "" {
+		if status := checkUserConflict(u.logger, u.repo, 0, checkUsername, checkEmail); status != nil {
+			return dto.NewApiResponse[*DTO.UserInfo](status, nil)
+		}
+	}
+	if changeEmail {
+		if status := checkEmailUsed(u.logger, u.repo, canonicalEmail, user.ID); status != nil {
+			return dto.NewApiResponse[*DTO.UserInfo](status, nil)
+		}
+	}
+	if _, ok := updates["username"]; ok {
+		if status := u.username.checkChange(user.ID, data.Username); status != nil {
+			return dto.NewApiResponse[*DTO.UserInfo](status, nil)
 		}
-		oldEmail = user.Email
-		updates["email"] = data.Email
 	}
 	if data.QQ != "" && (user.QQ == nil || *user.QQ != data.QQ) {
+		oldValue["qq"] = user.QQ
 		updates["qq"] = &data.QQ
 	}
 	if (data.ImageId != nil && user.ImageId != nil && *user.ImageId != *data.ImageId) ||
 		(data.ImageId == nil && user.ImageId != nil) ||
 		(data.ImageId != nil && user.ImageId == nil) {
+		// 只能使用自己上传且未被移除的头像
+		if data.ImageId != nil {
+			if status := u.avatar.checkImage(user.ID, *data.ImageId); status != nil {
+				return dto.NewApiResponse[*DTO.UserInfo](status, nil)
+			}
+		}
+		oldValue["image_id"] = user.ImageId
 		updates["image_id"] = data.ImageId
 	}
 
-	if len(updates) == 0 {
+	oldProfile, profileUpdates, status := profileChanges(u.divisionRepo, profile, &data.UpdateProfile)
+	if status != nil {
+		return dto.NewApiResponse[*DTO.UserInfo](status, nil)
+	}
+	oldFields, newFields, fieldValues, status := customFieldChanges(u.fieldRepo, profile, data.CustomFields, Entity.VisibilitySelf)
+	if status != nil {
+		return dto.NewApiResponse[*DTO.UserInfo](status, nil)
+	}
+
+	if !changeEmail && len(updates) == 0 && len(profileUpdates) == 0 && len(fieldValues) == 0 {
 		return dto.NewApiResponse[*DTO.UserInfo](dto.ErrErrorParam, nil)
 	}
 
-	if err := u.repo.Update(user, updates); err != nil {
-		u.logger.Errorf("UpdateSelfData handle fail, save user err, %v", err)
-		return dto.NewApiResponse[*DTO.UserInfo](ErrDataBaseError, nil)
+	if len(updates) > 0 || len(profileUpdates) > 0 || len(fieldValues) > 0 {
+		update := &repository.UserUpdate{Updates: updates, Profile: profileUpdates, FieldValues: fieldValues}
+		if oldUsername, ok := oldValue["username"]; ok {
+			update.UsernameChange = u.username.newChange(user.ID, oldUsername.(string), data.Username, data.Cid, true)
+		}
+		err := u.repo.UpdateWithProfile(user, update)
+		if err != nil {
+			u.logger.Errorf("UpdateSelfData handle fail, save user err, %v", err)
+			// 并发修改时由唯一索引拒绝, 重新检查以给出冲突的字段
+			if status := checkUserConflict(u.logger, u.repo, 0, checkUsername, ""); status != nil && status != ErrDataBaseError {
+				return dto.NewApiResponse[*DTO.UserInfo](status, nil)
+			}
+			if _, ok := updates["username"]; ok && errors.Is(err, repository.ErrDuplicated) {
+				return dto.NewApiResponse[*DTO.UserInfo](ErrUsernameTaken, nil)
+			}
+			return dto.NewApiResponse[*DTO.UserInfo](ErrDataBaseError, nil)
+		}
+	}
+	// 其他修改保存成功后才创建邮箱修改请求, 创建失败时其他修改仍然生效并记录审计日志
+	var emailStatus *dto.ApiStatus
+	if changeEmail {
+		emailStatus = u.emailChange.request(user, data.Email, data.Ip, data.UserAgent)
 	}
 
 	user, err = u.repo.GetById(data.Uid)
@@ -319,36 +645,55 @@ func (u *UserService) UpdateSelfData(data *DTO.UpdateCurrentUserData) *dto.ApiRe
 		u.logger.Errorf("UpdateSelfData handle fail, get user err, %v", err)
 		return dto.NewApiResponse[*DTO.UserInfo](ErrDataBaseError, nil)
 	}
+	profile, err = getProfile(u.profileRepo, user.ID)
+	if err != nil {
+		u.logger.Errorf("UpdateSelfData handle fail, get profile err, %v", err)
+		return dto.NewApiResponse[*DTO.UserInfo](ErrDataBaseError, nil)
+	}
 
-	if oldEmail != "" {
-		go func(u *UserService, user *entity.User, oldEmail string) {
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			_, err := u.client.EmailClient().SendEmailChange(ctx, &grpc.EmailChange{
-				TargetEmail: oldEmail,
-				Cid:         fmt.Sprintf("%04d", user.Cid),
-				Email:       user.Email,
-				Time:        time.Now().Format(time.RFC3339),
-				Ip:          data.Ip,
-				UserAgent:   data.UserAgent,
-			})
-			if err != nil {
-				u.logger.Errorf("error occurred when send email change email: %v", err)
-			}
-		}(u, user, oldEmail)
+	maps.Copy(oldValue, oldProfile)
+	maps.Copy(oldValue, oldFields)
+	maps.Copy(updates, profileUpdates)
+	maps.Copy(updates, newFields)
+
+	go func(u *UserService, data *DTO.UpdateCurrentUserData, user *entity.User, oldValue map[string]interface{}, newValue map[string]interface{}) {
+		// 仅申请修改邮箱时由邮箱修改服务记录审计日志
+		if len(newValue) == 0 {
+			return
+		}
+		oldValueStr, _ := json.Marshal(oldValue)
+		newValueStr, _ := json.Marshal(newValue)
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		_, err := u.client.AuditLogClient().Log(ctx, &pb.AuditLogRequest{
+			Event:     entity.AuditEventUserInformationEdit.Value,
+			Subject:   fmt.Sprintf("%04d", user.Cid),
+			Object:    fmt.Sprintf("%04d", user.Cid),
+			Ip:        data.Ip,
+			UserAgent: data.UserAgent,
+			OldValue:  string(oldValueStr),
+			NewValue:  string(newValueStr),
+		})
+		if err != nil {
+			u.logger.Errorf("error occurred when log audit: %v", err)
+		}
+	}(u, data, user, oldValue, updates)
+
+	if emailStatus != nil {
+		return dto.NewApiResponse[*DTO.UserInfo](emailStatus, nil)
 	}
 
 	userInfo := &DTO.UserInfo{}
-	userInfo.FromUserEntity(user)
+	userInfo.FromUserEntity(user, profile)
 
 	return dto.NewApiResponse(dto.SuccessHandleRequest, userInfo)
 }
 
 func (u *UserService) UpdateData(data *DTO.UpdateUserData) *dto.ApiResponse[bool] {
-	perm := permission.Permission(data.Permission)
-	if !perm.HasPermission(permission.UserEditInfo) {
+	scope, res := checkScope[bool](u.scope, data.Uid, data.Permission, permission.UserEditInfo)
+	if res != nil {
 		u.logger.Errorf("user %04d no permission to update user data", data.Cid)
-		return dto.NewApiResponse[bool](dto.ErrNoPermission, false)
+		return res
 	}
 	user, err := u.repo.GetById(data.Id)
 	if err != nil {
@@ -358,14 +703,42 @@ func (u *UserService) UpdateData(data *DTO.UpdateUserData) *dto.ApiResponse[bool
 		}
 		return dto.NewApiResponse[bool](ErrDataBaseError, false)
 	}
+	if res := checkUserInScope[bool](u.scope, scope, user.ID); res != nil {
+		u.logger.Errorf("user %04d no permission to update data of user %04d", data.Cid, user.Cid)
+		return res
+	}
+	// 只有超级管理员可以修改超级管理员的信息
+	perm := permission.Permission(data.Permission)
+	if Permission.Contains(Permission.Effective(user), Permission.SuperAdmin) && !perm.HasPermission(Permission.SuperAdmin) {
+		u.logger.Errorf("user %04d no permission to update data of super admin %04d", data.Cid, user.Cid)
+		return dto.NewApiResponse(dto.ErrNoPermission, false)
+	}
 
+	var oldEmail string
+	// 只改变大小写时不会与其他用户冲突, 无需检查
+	var checkUsername, checkEmail string
 	oldValue := map[string]interface{}{}
 	updates := map[string]interface{}{}
 	if data.Username != "" && user.Username != data.Username {
+		if !strings.EqualFold(user.Username, data.Username) {
+			checkUsername = data.Username
+		}
 		oldValue["username"] = user.Username
 		updates["username"] = data.Username
 	}
+	var canonicalEmail string
+	if data.Email != "" {
+		email, canonical, status := parseEmail(u.emailConfig, data.Email)
+		if status != nil {
+			return dto.NewApiResponse(status, false)
+		}
+		data.Email, canonicalEmail = email, canonical
+	}
 	if data.Email != "" && user.Email != data.Email {
+		if !strings.EqualFold(user.Email, data.Email) {
+			checkEmail = data.Email
+		}
+		oldEmail = user.Email
 		oldValue["email"] = user.Email
 		updates["email"] = data.Email
 	}
@@ -381,18 +754,77 @@ func (u *UserService) UpdateData(data *DTO.UpdateUserData) *dto.ApiResponse[bool
 		}
 		updates["password"] = string(password)
 	}
+	if checkUsername != "" || checkEmail != "" {
+		if status := checkUserConflict(u.logger, u.repo, 0, checkUsername, checkEmail); status != nil {
+			return dto.NewApiResponse(status, false)
+		}
+	}
+	if oldEmail != "" {
+		if status := checkEmailUsed(u.logger, u.repo, canonicalEmail, user.ID); status != nil {
+			return dto.NewApiResponse(status, false)
+		}
+	}
 
-	if err := u.repo.Update(user, updates); err != nil {
-		u.logger.Errorf("UpdateData handle fail, save user err, %v", err)
+	profile, err := getProfile(u.profileRepo, user.ID)
+	if err != nil {
+		u.logger.Errorf("UpdateData handle fail, get profile err, %v", err)
 		return dto.NewApiResponse[bool](ErrDataBaseError, false)
 	}
+	oldProfile, profileUpdates, status := profileChanges(u.divisionRepo, profile, &data.UpdateProfile)
+	if status != nil {
+		return dto.NewApiResponse(status, false)
+	}
+	oldFields, newFields, fieldValues, status := customFieldChanges(u.fieldRepo, profile, data.CustomFields, Entity.VisibilityStaff)
+	if status != nil {
+		return dto.NewApiResponse(status, false)
+	}
+
+	if len(updates) > 0 || len(profileUpdates) > 0 || len(fieldValues) > 0 || data.RequirePasswordChange {
+		update := &repository.UserUpdate{Updates: updates, Profile: profileUpdates, FieldValues: fieldValues}
+		// 修改邮箱时在同一事务中更新规范化邮箱
+		if oldEmail != "" {
+			update.CanonicalEmail = canonicalEmail
+		}
+		// 管理员覆盖密码或邮箱后, 使用旧凭据签发的会话全部失效
+		_, changePassword := updates["password"]
+		update.RevokeSessions = changePassword || oldEmail != ""
+		if data.RequirePasswordChange {
+			update.PasswordChange = &Entity.PasswordChangeRequirement{UserId: user.ID, OperatorCid: data.Cid}
+		}
+		// 管理员修改用户名不受修改间隔与用户名策略限制, 但同样记录历史并保留旧用户名
+		if oldUsername, ok := oldValue["username"]; ok {
+			update.UsernameChange = u.username.newChange(user.ID, oldUsername.(string), data.Username, data.Cid, false)
+		}
+		if err := u.repo.UpdateWithProfile(user, update); err != nil {
+			u.logger.Errorf("UpdateData handle fail, save user err, %v", err)
+			// 并发修改时由唯一索引拒绝, 重新检查以给出冲突的字段
+			if status := checkUserConflict(u.logger, u.repo, 0, checkUsername, checkEmail); status != nil && status != ErrDataBaseError {
+				return dto.NewApiResponse(status, false)
+			}
+			if errors.Is(err, repository.ErrDuplicated) && oldEmail != "" {
+				return dto.NewApiResponse(ErrEmailTaken, false)
+			}
+			return dto.NewApiResponse[bool](ErrDataBaseError, false)
+		}
+	}
+	if data.RequirePasswordChange {
+		updates["require_password_change"] = true
+	}
+	// 审计日志中不记录密码哈希
+	if _, ok := updates["password"]; ok {
+		updates["password"] = "changed"
+	}
+	maps.Copy(oldValue, oldProfile)
+	maps.Copy(oldValue, oldFields)
+	maps.Copy(updates, profileUpdates)
+	maps.Copy(updates, newFields)
 
-	go func(u *UserService, data *DTO.UpdateUserData, user *entity.User, oldValue map[string]interface{}, newValue map[string]interface{}) {
+	go func(u *UserService, data *DTO.UpdateUserData, user *entity.User, oldEmail string, oldValue map[string]interface{}, newValue map[string]interface{}) {
 		oldValueStr, _ := json.Marshal(oldValue)
 		newValueStr, _ := json.Marshal(newValue)
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
-		_, err = u.client.AuditLogClient().Log(ctx, &pb.AuditLogRequest{
+		_, err := u.client.AuditLogClient().Log(ctx, &pb.AuditLogRequest{
 			Event:     entity.AuditEventUserInformationEdit.Value,
 			Subject:   fmt.Sprintf("%04d", data.Cid),
 			Object:    fmt.Sprintf("%04d", user.Cid),
@@ -404,7 +836,35 @@ func (u *UserService) UpdateData(data *DTO.UpdateUserData) *dto.ApiResponse[bool
 		if err != nil {
 			u.logger.Errorf("error occurred when log audit: %v", err)
 		}
-	}(u, data, user, oldValue, updates)
+		email := user.Email
+		if oldEmail != "" {
+			email = data.Email
+			_, err = u.client.EmailClient().SendEmailChange(ctx, &pb.EmailChange{
+				TargetEmail: oldEmail,
+				Cid:         fmt.Sprintf("%04d", user.Cid),
+				Email:       data.Email,
+				Time:        time.Now().Format(time.RFC3339),
+				Ip:          data.Ip,
+				UserAgent:   data.UserAgent,
+			})
+			if err != nil {
+				u.logger.Errorf("error occurred when send email change email: %v", err)
+			}
+		}
+		if data.Password == "" {
+			return
+		}
+		_, err = u.client.EmailClient().SendPasswordChange(ctx, &pb.PasswordChange{
+			TargetEmail: email,
+			Cid:         fmt.Sprintf("%04d", user.Cid),
+			Time:        time.Now().Format(time.RFC3339),
+			Ip:          data.Ip,
+			UserAgent:   data.UserAgent,
+		})
+		if err != nil {
+			u.logger.Errorf("error occurred when send password change email: %v", err)
+		}
+	}(u, data, user, oldEmail, oldValue, updates)
 
 	return dto.NewApiResponse(dto.SuccessHandleRequest, true)
 }
@@ -413,6 +873,13 @@ var (
 	ErrOldPassword = dto.NewApiStatus("OLD_PASSWORD_ERROR", "原密码错误", dto.HttpCodeBadRequest)
 )
 
+// clearPasswordChange 用户自行修改密码后解除管理员的修改密码要求, 密码已经修改成功, 因此失败时只记录日志
+func (u *UserService) clearPasswordChange(userId uint) {
+	if err := u.sessionRepo.ClearPasswordChange(userId); err != nil {
+		u.logger.Errorf("error occurred when clear password change requirement: %v", err)
+	}
+}
+
 func (u *UserService) UpdatePassword(data *DTO.UpdateUserPassword) *dto.ApiResponse[bool] {
 	user, err := u.repo.GetById(data.Uid)
 	if err != nil {
@@ -442,6 +909,7 @@ func (u *UserService) UpdatePassword(data *DTO.UpdateUserPassword) *dto.ApiRespo
 		u.logger.Errorf("UpdatePassword handle fail, save user err, %v", err)
 		return dto.NewApiResponse[bool](ErrDataBaseError, false)
 	}
+	u.clearPasswordChange(user.ID)
 
 	go func(u *UserService, data *DTO.UpdateUserPassword, user *entity.User) {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -462,10 +930,10 @@ func (u *UserService) UpdatePassword(data *DTO.UpdateUserPassword) *dto.ApiRespo
 }
 
 func (u *UserService) Ban(data *DTO.BanUser) *dto.ApiResponse[bool] {
-	perm := permission.Permission(data.Permission)
-	if !perm.HasPermission(permission.UserBan) {
+	scope, res := checkScope[bool](u.scope, data.Uid, data.Permission, permission.UserBan)
+	if res != nil {
 		u.logger.Errorf("user %04d no permission to ban user", data.Cid)
-		return dto.NewApiResponse[bool](dto.ErrNoPermission, false)
+		return res
 	}
 
 	user, err := u.repo.GetById(data.Id)
@@ -476,6 +944,10 @@ func (u *UserService) Ban(data *DTO.BanUser) *dto.ApiResponse[bool] {
 		}
 		return dto.NewApiResponse[bool](ErrDataBaseError, false)
 	}
+	if res := checkUserInScope[bool](u.scope, scope, user.ID); res != nil {
+		u.logger.Errorf("user %04d no permission to ban user %04d", data.Cid, user.Cid)
+		return res
+	}
 
 	var bannedUntil sql.NullTime
 	bannedUntil.Valid = data.BannedSeconds > 0
@@ -523,10 +995,10 @@ func (u *UserService) Ban(data *DTO.BanUser) *dto.ApiResponse[bool] {
 }
 
 func (u *UserService) Unban(data *DTO.UnbanUser) *dto.ApiResponse[bool] {
-	perm := permission.Permission(data.Permission)
-	if !perm.HasPermission(permission.UserBan) {
+	scope, res := checkScope[bool](u.scope, data.Uid, data.Permission, permission.UserBan)
+	if res != nil {
 		u.logger.Errorf("user %04d no permission to unban user", data.Cid)
-		return dto.NewApiResponse[bool](dto.ErrNoPermission, false)
+		return res
 	}
 
 	user, err := u.repo.GetById(data.Id)
@@ -537,6 +1009,10 @@ func (u *UserService) Unban(data *DTO.UnbanUser) *dto.ApiResponse[bool] {
 		}
 		return dto.NewApiResponse[bool](ErrDataBaseError, false)
 	}
+	if res := checkUserInScope[bool](u.scope, scope, user.ID); res != nil {
+		u.logger.Errorf("user %04d no permission to unban user %04d", data.Cid, user.Cid)
+		return res
+	}
 
 	if err := u.repo.Unban(user.ID); err != nil {
 		return dto.NewApiResponse[bool](ErrDataBaseError, false)
@@ -0,0 +1,214 @@
+// Copyright (c) 2025 Half_nothing
+// SPDX-License-Identifier: MIT
+
+// Package service
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	c "user-service/src/interfaces/config"
+	Entity "user-service/src/interfaces/database/entity"
+	"user-service/src/interfaces/repository"
+	DTO "user-service/src/interfaces/server/dto"
+
+	"gorm.io/gorm"
+	"half-nothing.cn/service-core/interfaces/http/dto"
+	"half-nothing.cn/service-core/interfaces/logger"
+	"half-nothing.cn/service-core/permission"
+)
+
+var (
+	ErrUsernameBlocked    = dto.NewApiStatus("USERNAME_BLOCKED", "用户名包含不允许使用的内容", dto.HttpCodeBadRequest)
+	ErrUsernameProtected  = dto.NewApiStatus("USERNAME_PROTECTED", "用户名与受保护的名称过于相似", dto.HttpCodeBadRequest)
+	ErrUsernameConfusable = dto.NewApiStatus("USERNAME_CONFUSABLE", "用户名与已有用户名过于相似", dto.HttpCodeConflict)
+	ErrUsernameReserved   = dto.NewApiStatus("USERNAME_RESERVED", "该用户名近期被其他用户使用过, 暂时无法使用", dto.HttpCodeConflict)
+)
+
+// UsernameService 用户名策略与修改历史
+//
+// 比较用户名时使用 repository.UsernameSkeleton 计算的骨架, 骨架相同即视为容易混淆,
+// 骨架包含受保护的名称或其他管理人员的用户名时同样不允许使用
+type UsernameService struct {
+	logger    logger.Interface
+	config    *c.UsernameConfig
+	repo      repository.UsernameInterface
+	userRepo  repository.UserInterface
+	scope     *scopeResolver
+	blocked   []string
+	protected []string
+}
+
+func NewUsernameService(
+	lg logger.Interface,
+	config *c.UsernameConfig,
+	repo repository.UsernameInterface,
+	userRepo repository.UserInterface,
+	divisionRepo repository.DivisionInterface,
+) *UsernameService {
+	adapter := logger.NewLoggerAdapter(lg, "username-service")
+	blocked := make([]string, 0, len(config.Blocklist))
+	for _, word := range config.Blocklist {
+		blocked = append(blocked, repository.UsernameSkeleton(word))
+	}
+	protected := make([]string, 0, len(config.ProtectedNames))
+	for _, name := range config.ProtectedNames {
+		protected = append(protected, repository.UsernameSkeleton(name))
+	}
+	return &UsernameService{
+		logger:    adapter,
+		config:    config,
+		repo:      repo,
+		userRepo:  userRepo,
+		scope:     newScopeResolver(adapter, divisionRepo),
+		blocked:   blocked,
+		protected: protected,
+	}
+}
+
+// check 检查用户名是否允许被该用户使用, 注册时 userId 为 0
+func (service *UsernameService) check(userId uint, username string) *dto.ApiStatus {
+	skeleton := repository.UsernameSkeleton(username)
+	for _, word := range service.blocked {
+		if strings.Contains(skeleton, word) {
+			return ErrUsernameBlocked
+		}
+	}
+	for _, name := range service.protected {
+		if strings.Contains(skeleton, name) {
+			return ErrUsernameProtected
+		}
+	}
+	staff, err := service.repo.ContainsStaff(skeleton, userId)
+	if err != nil {
+		service.logger.Errorf("error occurred when find staff username: %v", err)
+		return ErrDataBaseError
+	}
+	if staff {
+		return ErrUsernameProtected
+	}
+	similar, err := service.repo.HasSimilar(skeleton, userId)
+	if err != nil {
+		service.logger.Errorf("error occurred when find similar username: %v", err)
+		return ErrDataBaseError
+	}
+	if similar {
+		return ErrUsernameConfusable
+	}
+	reserved, err := service.repo.IsReserved(skeleton, userId, time.Now())
+	if err != nil {
+		service.logger.Errorf("error occurred when check username reservation: %v", err)
+		return ErrDataBaseError
+	}
+	if reserved {
+		return ErrUsernameReserved
+	}
+	return nil
+}
+
+// nextChangeAt 获取用户下次可以自行修改用户名的时间, 不受限制时返回 nil
+func (service *UsernameService) nextChangeAt(userId uint) (*time.Time, error) {
+	if service.config.ChangeCooldownDuration == 0 {
+		return nil, nil
+	}
+	latest, err := service.repo.GetLatestSelfService(userId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	next := latest.CreatedAt.Add(service.config.ChangeCooldownDuration)
+	if !next.After(time.Now()) {
+		return nil, nil
+	}
+	return &next, nil
+}
+
+// checkChange 检查用户自行修改用户名是否满足修改间隔与用户名策略
+func (service *UsernameService) checkChange(userId uint, username string) *dto.ApiStatus {
+	next, err := service.nextChangeAt(userId)
+	if err != nil {
+		service.logger.Errorf("error occurred when get latest username change: %v", err)
+		return ErrDataBaseError
+	}
+	if next != nil {
+		return dto.NewApiStatus(
+			"USERNAME_CHANGE_COOLDOWN",
+			fmt.Sprintf("用户名修改过于频繁，下次可修改时间：%s", next.Format("2006-01-02 15:04:05")),
+			dto.HttpCodeBadRequest,
+		)
+	}
+	return service.check(userId, username)
+}
+
+// newChange 创建用户名修改记录, 由 UserInterface.UpdateWithProfile 与用户名修改在同一事务中保存
+//
+// 管理员修改用户名时 selfService 为 false, 不计入用户自行修改的间隔
+func (service *UsernameService) newChange(userId uint, oldUsername string, newUsername string, operatorCid uint, selfService bool) *Entity.UsernameChange {
+	return &Entity.UsernameChange{
+		UserId:        userId,
+		OldUsername:   oldUsername,
+		NewUsername:   newUsername,
+		OldSkeleton:   repository.UsernameSkeleton(oldUsername),
+		OperatorCid:   operatorCid,
+		SelfService:   selfService,
+		ReservedUntil: time.Now().Add(service.config.ReservationDuration),
+	}
+}
+
+func (service *UsernameService) history(userId uint) (*DTO.UsernameHistory, error) {
+	changes, err := service.repo.GetByUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	next, err := service.nextChangeAt(userId)
+	if err != nil {
+		return nil, err
+	}
+	history := &DTO.UsernameHistory{
+		History:      make([]*DTO.UsernameChangeInfo, 0, len(changes)),
+		NextChangeAt: next,
+	}
+	for _, change := range changes {
+		history.History = append(history.History, (&DTO.UsernameChangeInfo{}).FromEntity(change))
+	}
+	return history, nil
+}
+
+func (service *UsernameService) GetSelfUsernameHistory(data *DTO.GetSelfUsernameHistory) *dto.ApiResponse[*DTO.UsernameHistory] {
+	history, err := service.history(data.Uid)
+	if err != nil {
+		service.logger.Errorf("GetSelfUsernameHistory handle fail, get username history err, %v", err)
+		return dto.NewApiResponse[*DTO.UsernameHistory](ErrDataBaseError, nil)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, history)
+}
+
+func (service *UsernameService) GetUsernameHistory(data *DTO.GetUsernameHistory) *dto.ApiResponse[*DTO.UsernameHistory] {
+	scope, res := checkScope[*DTO.UsernameHistory](service.scope, data.Uid, data.Permission, permission.UserShowList)
+	if res != nil {
+		service.logger.Errorf("user %04d no permission to get username history", data.Cid)
+		return res
+	}
+	user, err := service.userRepo.GetById(data.Id)
+	if err != nil {
+		service.logger.Errorf("GetUsernameHistory handle fail, get user err, %v", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.NewApiResponse[*DTO.UsernameHistory](ErrUserNotFound, nil)
+		}
+		return dto.NewApiResponse[*DTO.UsernameHistory](ErrDataBaseError, nil)
+	}
+	if res := checkUserInScope[*DTO.UsernameHistory](service.scope, scope, user.ID); res != nil {
+		service.logger.Errorf("user %04d no permission to get username history of user %04d", data.Cid, user.Cid)
+		return res
+	}
+	history, err := service.history(user.ID)
+	if err != nil {
+		service.logger.Errorf("GetUsernameHistory handle fail, get username history err, %v", err)
+		return dto.NewApiResponse[*DTO.UsernameHistory](ErrDataBaseError, nil)
+	}
+	return dto.NewApiResponse(dto.SuccessHandleRequest, history)
+}